@@ -0,0 +1,217 @@
+// Command huidu-exporter serves Prometheus metrics for Huidu LED controllers.
+//
+// It keeps one connection per controller (with heartbeat and automatic
+// reconnect), polls device info, brightness, the on/off schedule and the
+// file list at a fixed interval, and serves the results on /metrics. See
+// package huiduprom for the list of metrics.
+//
+// Usage:
+//
+//	huidu-exporter [-listen addr] [-interval d] [-config file] [-device name=host[:port]]...
+//
+// Controllers are configured with repeated -device flags or a JSON file:
+//
+//	{
+//	  "listen": ":9810",
+//	  "interval": "30s",
+//	  "devices": {
+//	    "lobby":  "10.0.0.11",
+//	    "street": "10.0.0.12:10001"
+//	  }
+//	}
+//
+// Example alert rules:
+//
+//	huidu_up == 0                      # sign unreachable
+//	huidu_brightness_percent < 20      # sign dimmed
+//	huidu_screen_default_on == 0       # screen switched off
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+	"github.com/alparslanahmed/huidu-led/huiduprom"
+)
+
+// config, exporter ayarlarıdır (-config dosyası ve flag'ler).
+type config struct {
+	Listen   string            `json:"listen"`
+	Interval string            `json:"interval"`
+	Devices  map[string]string `json:"devices"`
+}
+
+// deviceFlags, tekrarlanabilir -device flag'idir.
+type deviceFlags map[string]string
+
+func (f deviceFlags) String() string {
+	names := make([]string, 0, len(f))
+	for name, addr := range f {
+		names = append(names, name+"="+addr)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+func (f deviceFlags) Set(v string) error {
+	name, addr, ok := strings.Cut(v, "=")
+	if !ok || name == "" || addr == "" {
+		return fmt.Errorf("want name=host[:port], got %q", v)
+	}
+	f[name] = addr
+	return nil
+}
+
+// İlk bağlantı denemeleri arasındaki bekleme aralığı. Bağlantı bir kez
+// kurulduktan sonra kopuşlar WithAutoReconnect ile toparlanır.
+const (
+	connectMinDelay = time.Second
+	connectMaxDelay = time.Minute
+)
+
+func main() {
+	devices := deviceFlags{}
+	configPath := flag.String("config", "", "JSON config file")
+	listen := flag.String("listen", "", `HTTP listen address (default ":9810")`)
+	interval := flag.Duration("interval", huiduprom.DefaultInterval, "poll interval")
+	timeout := flag.Duration("timeout", huidu.DefaultTimeout, "per-command device timeout")
+	verbose := flag.Bool("v", false, "log protocol activity")
+	flag.Var(devices, "device", "controller as name=host[:port] (repeatable)")
+	flag.Parse()
+
+	cfg := config{Listen: ":9810", Devices: map[string]string{}}
+	if *configPath != "" {
+		data, err := os.ReadFile(*configPath)
+		if err != nil {
+			log.Fatalf("huidu-exporter: %v", err)
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			log.Fatalf("huidu-exporter: %s: %v", *configPath, err)
+		}
+		if cfg.Devices == nil {
+			cfg.Devices = map[string]string{}
+		}
+	}
+	// Flag'ler dosyadaki değerleri geçersiz kılar
+	if *listen != "" {
+		cfg.Listen = *listen
+	}
+	for name, addr := range devices {
+		cfg.Devices[name] = addr
+	}
+	if len(cfg.Devices) == 0 {
+		log.Fatal("huidu-exporter: no devices configured (use -device or -config)")
+	}
+	pollInterval := *interval
+	if cfg.Interval != "" && !isFlagSet("interval") {
+		d, err := time.ParseDuration(cfg.Interval)
+		if err != nil {
+			log.Fatalf("huidu-exporter: config interval: %v", err)
+		}
+		pollInterval = d
+	}
+
+	logger := log.New(os.Stderr, "huidu-exporter: ", log.LstdFlags)
+	exp := huiduprom.NewExporter(
+		huiduprom.WithInterval(pollInterval),
+		huiduprom.WithPollTimeout(pollInterval),
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var wg sync.WaitGroup
+	var devs []*huidu.Device
+	for name, addr := range cfg.Devices {
+		host, port, err := huidu.SplitHostPort(addr)
+		if err != nil {
+			log.Fatalf("huidu-exporter: device %s: %v", name, err)
+		}
+		opts := append(exp.DeviceOptions(name), huidu.WithAutoReconnect(true), huidu.WithTimeout(*timeout))
+		if *verbose {
+			opts = append(opts, huidu.WithLogger(logger))
+		}
+		dev := huidu.NewDevice(host, port, opts...)
+		exp.Add(name, dev)
+		devs = append(devs, dev)
+
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			connectLoop(ctx, name, dev, logger)
+		}(name)
+	}
+	go exp.Run(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", exp)
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `<html><body><h1>huidu-exporter</h1><a href="/metrics">Metrics</a></body></html>`)
+	})
+	srv := &http.Server{Addr: cfg.Listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	logger.Printf("listening on %s (%d devices, polling every %s)", cfg.Listen, len(cfg.Devices), pollInterval)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Fatal(err)
+	}
+	wg.Wait()
+	for _, dev := range devs {
+		dev.Close()
+	}
+}
+
+// connectLoop, ilk bağlantı kurulana veya ctx iptal edilene kadar dener.
+func connectLoop(ctx context.Context, name string, dev *huidu.Device, logger *log.Logger) {
+	delay := connectMinDelay
+	for {
+		err := dev.ConnectContext(ctx)
+		if err == nil {
+			logger.Printf("%s: connected to %s:%d", name, dev.Host(), dev.Port())
+			return
+		}
+		logger.Printf("%s: connect: %v (retrying in %s)", name, err, delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		delay *= 2
+		if delay > connectMaxDelay {
+			delay = connectMaxDelay
+		}
+	}
+}
+
+// isFlagSet, flag'in komut satırında açıkça verilip verilmediğini döner.
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
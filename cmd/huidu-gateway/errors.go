@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+)
+
+// ─── Hata Yanıtları ─────────────────────────────────────────────────────────────
+//
+// Tüm hatalar aynı gövdeyle döner:
+//
+//	{"error": {"kind": "device", "message": "...", "code": 18, "codeMessage": "...", "result": "kFileNotFound"}}
+//
+// kind, hatanın kaynağını belirtir; code ve codeMessage yalnızca kart bir
+// huidu.ErrorCode bildirdiğinde, result ise SDK result değeri olduğunda
+// doldurulur.
+
+// Hata türleri (apiError.Kind).
+const (
+	kindBadRequest   = "bad_request"  // Hatalı istek gövdesi veya parametre
+	kindUnauthorized = "unauthorized" // Eksik veya yanlış token
+	kindNotFound     = "not_found"    // Bilinmeyen kart veya dosya
+	kindDevice       = "device"       // Kart bir ErrorCode bildirdi
+	kindUnavailable  = "unavailable"  // Karta bağlantı yok veya koptu
+	kindTimeout      = "timeout"      // Kart zamanında yanıt vermedi
+	kindProtocol     = "protocol"     // Beklenmeyen veya bozuk yanıt
+	kindInternal     = "internal"     // Diğer hatalar
+)
+
+// apiError, JSON hata gövdesidir.
+type apiError struct {
+	Kind        string `json:"kind"`
+	Message     string `json:"message"`
+	Code        *int   `json:"code,omitempty"`
+	CodeMessage string `json:"codeMessage,omitempty"`
+	Result      string `json:"result,omitempty"`
+
+	status int
+}
+
+// requestError, istemci kaynaklı bir hatadır (400/404 vb.).
+type requestError struct {
+	status int
+	kind   string
+	msg    string
+}
+
+func (e *requestError) Error() string { return e.msg }
+
+// badRequest, 400 döndüren bir hata oluşturur.
+func badRequest(msg string) error {
+	return &requestError{status: http.StatusBadRequest, kind: kindBadRequest, msg: msg}
+}
+
+// notFound, 404 döndüren bir hata oluşturur.
+func notFound(msg string) error {
+	return &requestError{status: http.StatusNotFound, kind: kindNotFound, msg: msg}
+}
+
+// toAPIError, err'i HTTP durum koduna ve JSON gövdesine eşler.
+func toAPIError(err error) *apiError {
+	e := &apiError{Message: err.Error()}
+
+	var re *requestError
+	if errors.As(err, &re) {
+		e.Kind, e.status = re.kind, re.status
+		return e
+	}
+
+	var rerr *huidu.ResultError
+	if errors.As(err, &rerr) {
+		e.Result = rerr.Result
+	}
+	var code huidu.ErrorCode
+	if errors.As(err, &code) && code != huidu.ErrSuccess {
+		n := int(code)
+		e.Code = &n
+		e.CodeMessage = code.String()
+		e.Kind, e.status = kindDevice, deviceStatus(code)
+		return e
+	}
+
+	var perr *huidu.ProtocolError
+	var nerr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		// İstemci vazgeçti; yanıt büyük olasılıkla okunmayacak
+		e.Kind, e.status = kindUnavailable, 499
+	case errors.Is(err, huidu.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		e.Kind, e.status = kindTimeout, http.StatusGatewayTimeout
+	case errors.Is(err, errNotConnected), errors.Is(err, huidu.ErrConnectionLost),
+		errors.Is(err, huidu.ErrClosed), errors.As(err, &nerr):
+		e.Kind, e.status = kindUnavailable, http.StatusServiceUnavailable
+	case errors.As(err, &perr), rerr != nil:
+		e.Kind, e.status = kindProtocol, http.StatusBadGateway
+	default:
+		e.Kind, e.status = kindInternal, http.StatusInternalServerError
+	}
+	return e
+}
+
+// deviceStatus, kartın bildirdiği hata kodunu HTTP durumuna eşler.
+func deviceStatus(code huidu.ErrorCode) int {
+	switch code {
+	case huidu.ErrFileNotFound, huidu.ErrNodeNotExist, huidu.ErrNotFoundWifi:
+		return http.StatusNotFound
+	case huidu.ErrDeviceOccupied, huidu.ErrFileOccupied, huidu.ErrNodeExist:
+		return http.StatusConflict
+	case huidu.ErrNotSpaceToSave:
+		return http.StatusInsufficientStorage
+	case huidu.ErrInvalidParam, huidu.ErrInvalidFileData, huidu.ErrFileContentError,
+		huidu.ErrNotMediaFile, huidu.ErrParseVideoFailed, huidu.ErrUnsupportVideo,
+		huidu.ErrUnsupportFPS, huidu.ErrUnsupportRes, huidu.ErrUnsupportFormat,
+		huidu.ErrUnsupportDuration, huidu.ErrFirmwareFormat, huidu.ErrPasswdTooSimple:
+		return http.StatusUnprocessableEntity
+	case huidu.ErrUnsupportMethod, huidu.ErrUnsupportDevice, huidu.ErrPluginNotExist:
+		return http.StatusNotImplemented
+	case huidu.ErrPermissionDenied, huidu.ErrCheckLicense:
+		return http.StatusForbidden
+	}
+	return http.StatusBadGateway
+}
+
+// writeError, err'i JSON hata gövdesiyle yazar.
+func writeError(w http.ResponseWriter, err error) {
+	e := toAPIError(err)
+	writeJSON(w, e.status, struct {
+		Error *apiError `json:"error"`
+	}{e})
+}
+
+// writeJSON, v'yi JSON olarak yazar.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
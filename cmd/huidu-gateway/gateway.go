@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+	"github.com/alparslanahmed/huidu-led/huiduprom"
+)
+
+// ─── Cihaz Havuzu ───────────────────────────────────────────────────────────────
+//
+// Her yapılandırılmış kart için tek bir kalıcı Device tutulur. İlk bağlantı
+// arka planda artan aralıklarla denenir; bağlantı bir kez kurulduktan sonra
+// kopuşlar WithAutoReconnect ile kütüphane tarafından toparlanır.
+//
+// Aynı karta gelen istekler sign.sem ile sıraya sokulur: bir yükleme sürerken
+// gelen SendScreen, yükleme bitene (veya istemci vazgeçene) kadar bekler.
+
+const (
+	// connectMinDelay ve connectMaxDelay, ilk bağlantı denemeleri arasındaki
+	// bekleme aralığıdır.
+	connectMinDelay = time.Second
+	connectMaxDelay = time.Minute
+
+	// defaultMaxUpload, -max-upload flag'inin varsayılan değeridir.
+	defaultMaxUpload = 256 << 20
+)
+
+// errNotConnected, kart henüz hiç bağlanamadığında döner.
+var errNotConnected = errors.New("device not connected")
+
+// sign, gateway'in yönettiği tek bir karttır.
+type sign struct {
+	name string
+	addr string
+	dev  *huidu.Device
+
+	// sem, karta erişimi tek isteğe indirir (kapasite 1).
+	sem chan struct{}
+
+	mu      sync.Mutex
+	ready   bool  // ilk bağlantı kuruldu; sonrası WithAutoReconnect'te
+	lastErr error // son bağlantı hatası
+}
+
+// acquire, karta özel erişim alır. ctx iptal edilirse beklemeden vazgeçer.
+func (s *sign) acquire(ctx context.Context) (release func(), err error) {
+	select {
+	case s.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if !s.isReady() {
+		<-s.sem
+		return nil, s.notReady()
+	}
+	return func() { <-s.sem }, nil
+}
+
+func (s *sign) isReady() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ready
+}
+
+// notReady, henüz bağlanamamış kart için hata oluşturur.
+func (s *sign) notReady() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lastErr != nil {
+		return fmt.Errorf("%w: %w", errNotConnected, s.lastErr)
+	}
+	return errNotConnected
+}
+
+// connectLoop, ilk bağlantı kurulana veya ctx iptal edilene kadar dener.
+func (s *sign) connectLoop(ctx context.Context, logger *log.Logger) {
+	delay := connectMinDelay
+	for {
+		err := s.dev.ConnectContext(ctx)
+		s.mu.Lock()
+		s.lastErr = err
+		s.ready = err == nil
+		s.mu.Unlock()
+		if err == nil {
+			logger.Printf("%s: connected to %s", s.name, s.addr)
+			return
+		}
+		logger.Printf("%s: connect %s: %v (retrying in %s)", s.name, s.addr, err, delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		delay *= 2
+		if delay > connectMaxDelay {
+			delay = connectMaxDelay
+		}
+	}
+}
+
+// gateway, yapılandırılmış kartların kümesidir.
+type gateway struct {
+	signs  map[string]*sign
+	logger *log.Logger
+	wg     sync.WaitGroup
+
+	// metrics, /metrics adresinde sunulan kart metrikleridir.
+	metrics *huiduprom.Exporter
+
+	// maxUpload, yüklenen tek bir dosyanın en büyük boyutudur. Dosyalar
+	// karta gönderilmeden önce geçici dizine yazılır.
+	maxUpload int64
+}
+
+// newGateway, devices (ad → host[:port]) için Device'ları oluşturur.
+// Bağlantılar start ile açılır.
+func newGateway(devices map[string]string, logger *log.Logger, options ...huidu.DeviceOption) (*gateway, error) {
+	gw := &gateway{
+		signs:     make(map[string]*sign),
+		logger:    logger,
+		metrics:   huiduprom.NewExporter(),
+		maxUpload: defaultMaxUpload,
+	}
+	for name, addr := range devices {
+		host, port, err := huidu.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("device %s: %w", name, err)
+		}
+		opts := append(gw.metrics.DeviceOptions(name), huidu.WithAutoReconnect(true))
+		dev := huidu.NewDevice(host, port, append(opts, options...)...)
+		gw.metrics.Add(name, dev)
+		gw.signs[name] = &sign{
+			name: name,
+			addr: net.JoinHostPort(host, strconv.Itoa(port)),
+			dev:  dev,
+			sem:  make(chan struct{}, 1),
+		}
+	}
+	return gw, nil
+}
+
+// start, tüm kartlara arka planda bağlanmaya ve metrikleri toplamaya başlar.
+func (gw *gateway) start(ctx context.Context) {
+	gw.wg.Add(1)
+	go func() {
+		defer gw.wg.Done()
+		gw.metrics.Run(ctx)
+	}()
+	for _, s := range gw.signs {
+		gw.wg.Add(1)
+		go func(s *sign) {
+			defer gw.wg.Done()
+			s.connectLoop(ctx, gw.logger)
+		}(s)
+	}
+}
+
+// sign, adı verilen kartı döner (yoksa nil).
+func (gw *gateway) sign(name string) *sign {
+	return gw.signs[name]
+}
+
+// names, kart adlarını sıralı döner.
+func (gw *gateway) names() []string {
+	names := make([]string, 0, len(gw.signs))
+	for name := range gw.signs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Close, tüm bağlantıları kapatır ve bağlantı döngülerini bekler.
+func (gw *gateway) Close() error {
+	for _, s := range gw.signs {
+		s.dev.Close()
+	}
+	gw.wg.Wait()
+	return nil
+}
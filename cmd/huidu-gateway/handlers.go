@@ -0,0 +1,439 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+)
+
+// maxJSONBody, JSON istek gövdelerinin en büyük boyutudur.
+const maxJSONBody = 8 << 20
+
+// fileTypes, yükleme formundaki "type" alanının kabul ettiği değerlerdir.
+var fileTypes = map[string]huidu.FileType{
+	"":         huidu.FileTypeAuto,
+	"auto":     huidu.FileTypeAuto,
+	"image":    huidu.FileTypeImage,
+	"video":    huidu.FileTypeVideo,
+	"font":     huidu.FileTypeFont,
+	"firmware": huidu.FileTypeFirmware,
+	"fpga":     huidu.FileTypeFPGAConfig,
+	"config":   huidu.FileTypeSettingConfig,
+	"program":  huidu.FileTypeProgramXML,
+}
+
+// handler, API'nin HTTP yönlendiricisini oluşturur. token boş değilse
+// /openapi.json, /healthz ve /metrics dışındaki istekler Bearer token ister.
+func (gw *gateway) handler(token string) http.Handler {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /v1/devices", gw.listDevices)
+	api.HandleFunc("GET /v1/devices/{id}", gw.device(getDeviceInfo))
+
+	api.HandleFunc("GET /v1/devices/{id}/brightness", gw.device(getBrightness))
+	api.HandleFunc("PUT /v1/devices/{id}/brightness", gw.device(putBrightness))
+	api.HandleFunc("PUT /v1/devices/{id}/power", gw.device(putPower))
+	api.HandleFunc("GET /v1/devices/{id}/power/schedule", gw.device(getSwitchTime))
+	api.HandleFunc("PATCH /v1/devices/{id}/power/schedule", gw.device(patchSwitchTime))
+
+	api.HandleFunc("GET /v1/devices/{id}/time", gw.device(getTime))
+	api.HandleFunc("PATCH /v1/devices/{id}/time", gw.device(patchTime))
+	api.HandleFunc("POST /v1/devices/{id}/time/sync", gw.device(syncTime))
+
+	api.HandleFunc("GET /v1/devices/{id}/network/ethernet", gw.device(getEthernet))
+	api.HandleFunc("PATCH /v1/devices/{id}/network/ethernet", gw.device(patchEthernet))
+	api.HandleFunc("GET /v1/devices/{id}/network/wifi", gw.device(getWifi))
+	api.HandleFunc("PATCH /v1/devices/{id}/network/wifi", gw.device(patchWifi))
+
+	api.HandleFunc("GET /v1/devices/{id}/files", gw.device(listFiles))
+	api.HandleFunc("POST /v1/devices/{id}/files", gw.device(gw.uploadFiles))
+	api.HandleFunc("GET /v1/devices/{id}/files/{name}", gw.device(downloadFile))
+	api.HandleFunc("DELETE /v1/devices/{id}/files/{name}", gw.device(deleteFile))
+
+	api.HandleFunc("GET /v1/devices/{id}/screen", gw.device(getScreen))
+	api.HandleFunc("PUT /v1/devices/{id}/screen", gw.device(putScreen))
+
+	api.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, notFound("no route for "+r.Method+" "+r.URL.Path))
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPISpec)
+	})
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, okBody)
+	})
+	mux.Handle("GET /metrics", gw.metrics)
+	mux.Handle("/", requireToken(token, api))
+	return mux
+}
+
+// requireToken, token boş değilse Authorization başlığını doğrular.
+func requireToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(got, want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="huidu-gateway"`)
+			writeError(w, &requestError{status: http.StatusUnauthorized, kind: kindUnauthorized, msg: "missing or invalid bearer token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// okBody, çıktısı olmayan işlemlerin yanıtıdır.
+var okBody = struct {
+	OK bool `json:"ok"`
+}{OK: true}
+
+// deviceFunc, tek bir karta karşı çalışan işleyicidir. Dönen değer JSON
+// olarak yazılır; nil değer {"ok": true} yazar. w'ye kendisi yazan
+// işleyiciler errHandled döner.
+type deviceFunc func(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error)
+
+// errHandled, yanıtın işleyici tarafından zaten yazıldığını belirtir.
+var errHandled = errors.New("response written")
+
+// device, fn'i {id} kartına özel erişim alarak çalıştırır.
+func (gw *gateway) device(fn deviceFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := gw.sign(r.PathValue("id"))
+		if s == nil {
+			writeError(w, notFound(fmt.Sprintf("unknown device %q", r.PathValue("id"))))
+			return
+		}
+		release, err := s.acquire(r.Context())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		defer release()
+
+		v, err := fn(r.Context(), s, w, r)
+		switch {
+		case errors.Is(err, errHandled):
+		case err != nil:
+			writeError(w, err)
+		case v == nil:
+			writeJSON(w, http.StatusOK, okBody)
+		default:
+			writeJSON(w, http.StatusOK, v)
+		}
+	}
+}
+
+// decodeJSON, istek gövdesini v'ye okur. Bilinmeyen alanlar hatadır;
+// v önceden doldurulmuşsa yalnızca gövdede verilen alanlar değişir.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxJSONBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return badRequest("invalid JSON body: " + err.Error())
+	}
+	return nil
+}
+
+// ─── Cihaz Listesi ──────────────────────────────────────────────────────────────
+
+// deviceSummary, GET /v1/devices öğesidir.
+type deviceSummary struct {
+	ID           string            `json:"id"`
+	Addr         string            `json:"addr"`
+	Connected    bool              `json:"connected"`
+	Error        string            `json:"error,omitempty"`
+	HeartbeatRTT string            `json:"heartbeatRTT,omitempty"`
+	Info         *huidu.DeviceInfo `json:"info,omitempty"`
+}
+
+func (gw *gateway) listDevices(w http.ResponseWriter, r *http.Request) {
+	list := []deviceSummary{}
+	for _, name := range gw.names() {
+		s := gw.sign(name)
+		d := deviceSummary{
+			ID:        name,
+			Addr:      s.addr,
+			Connected: s.dev.IsConnected(),
+			Info:      s.dev.CachedDeviceInfo(),
+		}
+		if !s.isReady() {
+			d.Error = s.notReady().Error()
+		}
+		if rtt := s.dev.HeartbeatRTT(); rtt > 0 {
+			d.HeartbeatRTT = rtt.String()
+		}
+		list = append(list, d)
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func getDeviceInfo(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	return s.dev.GetDeviceInfoContext(ctx)
+}
+
+// ─── Parlaklık / Güç ────────────────────────────────────────────────────────────
+
+func getBrightness(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	return s.dev.GetLuminanceInfoContext(ctx)
+}
+
+func putBrightness(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	var body struct {
+		Value int `json:"value"`
+	}
+	if err := decodeJSON(w, r, &body); err != nil {
+		return nil, err
+	}
+	if body.Value < 1 || body.Value > 100 {
+		return nil, badRequest("value must be between 1 and 100")
+	}
+	return nil, s.dev.SetBrightnessContext(ctx, body.Value)
+}
+
+func putPower(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	var body struct {
+		On *bool `json:"on"`
+	}
+	if err := decodeJSON(w, r, &body); err != nil {
+		return nil, err
+	}
+	if body.On == nil {
+		return nil, badRequest(`missing "on"`)
+	}
+	if *body.On {
+		return nil, s.dev.OpenScreenContext(ctx)
+	}
+	return nil, s.dev.CloseScreenContext(ctx)
+}
+
+func getSwitchTime(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	return s.dev.GetSwitchTimeInfoContext(ctx)
+}
+
+func patchSwitchTime(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	info, err := s.dev.GetSwitchTimeInfoContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeJSON(w, r, info); err != nil {
+		return nil, err
+	}
+	return info, s.dev.SetSwitchTimeInfoContext(ctx, info)
+}
+
+// ─── Zaman ──────────────────────────────────────────────────────────────────────
+
+func getTime(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	return s.dev.GetTimeInfoContext(ctx)
+}
+
+func patchTime(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	info, err := s.dev.GetTimeInfoContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeJSON(w, r, info); err != nil {
+		return nil, err
+	}
+	return info, s.dev.SetTimeInfoContext(ctx, info)
+}
+
+// syncTime, kartın saatini gateway'in yerel saatine ayarlar.
+func syncTime(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	info, err := s.dev.GetTimeInfoContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info.Sync = "none"
+	info.Time = time.Now().Format("2006-01-02 15:04:05")
+	return info, s.dev.SetTimeInfoContext(ctx, info)
+}
+
+// ─── Ağ ─────────────────────────────────────────────────────────────────────────
+
+func getEthernet(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	return s.dev.GetEthernetInfoContext(ctx)
+}
+
+func patchEthernet(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	info, err := s.dev.GetEthernetInfoContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeJSON(w, r, info); err != nil {
+		return nil, err
+	}
+	return info, s.dev.SetEthernetInfoContext(ctx, info)
+}
+
+func getWifi(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	return s.dev.GetWifiInfoContext(ctx)
+}
+
+func patchWifi(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	info, err := s.dev.GetWifiInfoContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeJSON(w, r, info); err != nil {
+		return nil, err
+	}
+	return info, s.dev.SetWifiInfoContext(ctx, info)
+}
+
+// ─── Dosyalar ───────────────────────────────────────────────────────────────────
+
+func listFiles(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	files, err := s.dev.GetFileListContext(ctx)
+	if files == nil {
+		files = []huidu.FileInfo{}
+	}
+	return files, err
+}
+
+// uploadedFile, POST /files yanıtındaki öğedir.
+type uploadedFile struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// uploadFiles, multipart/form-data gövdesindeki dosyaları karta yükler.
+// İsteğe bağlı "type" alanı kendisinden sonra gelen dosyalara uygulanır.
+func (gw *gateway) uploadFiles(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, badRequest("expected multipart/form-data body: " + err.Error())
+	}
+
+	fileType := huidu.FileTypeAuto
+	uploaded := []uploadedFile{}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, badRequest("reading multipart body: " + err.Error())
+		}
+
+		if part.FileName() == "" {
+			if part.FormName() == "type" {
+				v, err := io.ReadAll(io.LimitReader(part, 64))
+				if err != nil {
+					return nil, badRequest("reading type field: " + err.Error())
+				}
+				t, ok := fileTypes[string(v)]
+				if !ok {
+					return nil, badRequest(fmt.Sprintf("invalid file type %q", v))
+				}
+				fileType = t
+			}
+			continue
+		}
+
+		name := path.Base(part.FileName())
+		size, err := gw.uploadPart(ctx, s, name, part, fileType)
+		if err != nil {
+			return nil, err
+		}
+		uploaded = append(uploaded, uploadedFile{Name: name, Size: size})
+	}
+	if len(uploaded) == 0 {
+		return nil, badRequest("no file parts in request")
+	}
+	return struct {
+		Uploaded []uploadedFile `json:"uploaded"`
+	}{uploaded}, nil
+}
+
+// uploadPart, tek bir dosya parçasını geçici dosyaya yazar ve oradan karta
+// yükler. Böylece büyük videolar belleğe alınmaz.
+func (gw *gateway) uploadPart(ctx context.Context, s *sign, name string, part io.Reader, fileType huidu.FileType) (int64, error) {
+	tmp, err := os.CreateTemp("", "huidu-upload-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, io.LimitReader(part, gw.maxUpload+1))
+	if err != nil {
+		return 0, badRequest("reading " + name + ": " + err.Error())
+	}
+	if size > gw.maxUpload {
+		return 0, &requestError{status: http.StatusRequestEntityTooLarge, kind: kindBadRequest,
+			msg: fmt.Sprintf("%s exceeds the %d byte upload limit", name, gw.maxUpload)}
+	}
+	if err := s.dev.UploadReaderContext(ctx, name, tmp, size, fileType); err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+	return size, nil
+}
+
+// lazyWriter, başlıkları ilk yazmada gönderir; böylece indirme veri
+// gelmeden başarısız olursa JSON hata yanıtı hâlâ yazılabilir.
+type lazyWriter struct {
+	w       http.ResponseWriter
+	name    string
+	started bool
+}
+
+func (lw *lazyWriter) Write(p []byte) (int, error) {
+	if !lw.started {
+		lw.started = true
+		h := lw.w.Header()
+		h.Set("Content-Type", "application/octet-stream")
+		h.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", lw.name))
+		lw.w.WriteHeader(http.StatusOK)
+	}
+	return lw.w.Write(p)
+}
+
+func downloadFile(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	lw := &lazyWriter{w: w, name: r.PathValue("name")}
+	err := s.dev.DownloadFileContext(ctx, lw.name, lw)
+	if err != nil && lw.started {
+		// Gövde yarıda kaldı; bağlantıyı keserek istemcinin fark etmesini sağla
+		panic(http.ErrAbortHandler)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !lw.started {
+		// Boş dosya
+		lw.Write(nil)
+	}
+	return nil, errHandled
+}
+
+func deleteFile(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	return nil, s.dev.DeleteFilesContext(ctx, r.PathValue("name"))
+}
+
+// ─── Programlar ─────────────────────────────────────────────────────────────────
+
+func getScreen(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	return s.dev.GetScreenContext(ctx)
+}
+
+// putScreen, JSON ekran tanımını (huidu.Screen JSON biçimi) karta gönderir.
+func putScreen(ctx context.Context, s *sign, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	var screen huidu.Screen
+	if err := decodeJSON(w, r, &screen); err != nil {
+		return nil, err
+	}
+	return nil, s.dev.SendScreenContext(ctx, &screen)
+}
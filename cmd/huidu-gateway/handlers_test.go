@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+	"github.com/alparslanahmed/huidu-led/huidutest"
+)
+
+const testToken = "s3cret"
+
+// newTestGateway, tek bir simüle karta ("lobby") bağlı gateway'i httptest
+// sunucusunda çalıştırır.
+func newTestGateway(t *testing.T) (*huidutest.Controller, *gateway, *httptest.Server) {
+	t.Helper()
+	ctrl := huidutest.NewController()
+	if err := ctrl.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ctrl.Close() })
+
+	gw, err := newGateway(map[string]string{"lobby": ctrl.Addr()}, log.New(io.Discard, "", 0),
+		huidu.WithTimeout(2*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	gw.start(ctx)
+	t.Cleanup(func() {
+		cancel()
+		gw.Close()
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !gw.sign("lobby").isReady() {
+		if time.Now().After(deadline) {
+			t.Fatal("gateway karta bağlanamadı")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	srv := httptest.NewServer(gw.handler(testToken))
+	t.Cleanup(srv.Close)
+	return ctrl, gw, srv
+}
+
+// do, token'lı bir istek gönderir ve yanıtı döner.
+func do(t *testing.T, srv *httptest.Server, method, path, contentType string, body io.Reader) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// decodeError, JSON hata gövdesini okur.
+func decodeError(t *testing.T, resp *http.Response) apiError {
+	t.Helper()
+	var body struct {
+		Error apiError `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("hata gövdesi okunamadı: %v", err)
+	}
+	return body.Error
+}
+
+func TestRequireToken(t *testing.T) {
+	_, _, srv := newTestGateway(t)
+
+	for _, auth := range []string{"", "Bearer yanlis", testToken} {
+		req, _ := http.NewRequest("GET", srv.URL+"/v1/devices", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: durum %d, beklenen 401", auth, resp.StatusCode)
+		}
+		if !strings.HasPrefix(resp.Header.Get("WWW-Authenticate"), "Bearer") {
+			t.Errorf("Authorization %q: WWW-Authenticate = %q", auth, resp.Header.Get("WWW-Authenticate"))
+		}
+		if e := decodeError(t, resp); e.Kind != kindUnauthorized {
+			t.Errorf("Authorization %q: kind = %q", auth, e.Kind)
+		}
+		resp.Body.Close()
+	}
+
+	// Token gerektirmeyen uçlar
+	for _, path := range []string{"/healthz", "/openapi.json", "/metrics"} {
+		resp, err := srv.Client().Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: durum %d", path, resp.StatusCode)
+		}
+	}
+
+	resp := do(t, srv, "GET", "/v1/devices", "", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("token ile durum %d", resp.StatusCode)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	_, _, srv := newTestGateway(t)
+
+	tests := []struct {
+		method, path string
+	}{
+		{"GET", "/v1/devices/nope"},
+		{"PUT", "/v1/devices/nope/brightness"},
+		{"GET", "/v1/nothing"},
+	}
+	for _, tt := range tests {
+		resp := do(t, srv, tt.method, tt.path, "application/json", strings.NewReader(`{"value":50}`))
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("%s %s: durum %d, beklenen 404", tt.method, tt.path, resp.StatusCode)
+			continue
+		}
+		if e := decodeError(t, resp); e.Kind != kindNotFound {
+			t.Errorf("%s %s: kind = %q", tt.method, tt.path, e.Kind)
+		}
+	}
+}
+
+func TestDeviceErrorMapping(t *testing.T) {
+	ctrl, _, srv := newTestGateway(t)
+
+	tests := []struct {
+		name   string
+		fault  *huidutest.Fault
+		method string
+		path   string
+		status int
+		code   huidu.ErrorCode
+	}{
+		{"meşgul", &huidutest.Fault{On: huidu.CmdSdkCmdAsk, Method: huidu.MethodGetLuminancePloy, ErrorAnswer: huidu.ErrDeviceOccupied},
+			"GET", "/v1/devices/lobby/brightness", http.StatusConflict, huidu.ErrDeviceOccupied},
+		{"dosya yok", nil, "GET", "/v1/devices/lobby/files/missing.png", http.StatusNotFound, huidu.ErrFileNotFound},
+		{"yer yok", &huidutest.Fault{On: huidu.CmdFileStartAsk, FileError: huidu.ErrNotSpaceToSave},
+			"POST", "/v1/devices/lobby/files", http.StatusInsufficientStorage, huidu.ErrNotSpaceToSave},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl.ClearFaults()
+			if tt.fault != nil {
+				ctrl.Inject(*tt.fault)
+			}
+			var resp *http.Response
+			if tt.method == "POST" {
+				body, ct := multipartBody(t, "clip.png", []byte("png"))
+				resp = do(t, srv, tt.method, tt.path, ct, body)
+			} else {
+				resp = do(t, srv, tt.method, tt.path, "", nil)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("durum %d, beklenen %d", resp.StatusCode, tt.status)
+			}
+			e := decodeError(t, resp)
+			if e.Kind != kindDevice || e.Code == nil || *e.Code != int(tt.code) || e.CodeMessage != tt.code.String() {
+				t.Fatalf("hata = %+v", e)
+			}
+		})
+	}
+}
+
+// multipartBody, tek dosyalı bir multipart/form-data gövdesi oluşturur.
+func multipartBody(t *testing.T, name string, data []byte) (io.Reader, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, err := mw.CreateFormFile("file", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write(data)
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf, mw.FormDataContentType()
+}
+
+func TestUploadLimit(t *testing.T) {
+	ctrl, gw, srv := newTestGateway(t)
+	gw.maxUpload = 16
+
+	body, ct := multipartBody(t, "small.png", bytes.Repeat([]byte("a"), 16))
+	resp := do(t, srv, "POST", "/v1/devices/lobby/files", ct, body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("sınırdaki dosya: durum %d", resp.StatusCode)
+	}
+	if _, ok := ctrl.File("small.png"); !ok {
+		t.Fatal("sınırdaki dosya karta yüklenmedi")
+	}
+
+	body, ct = multipartBody(t, "big.mp4", bytes.Repeat([]byte("a"), 17))
+	resp = do(t, srv, "POST", "/v1/devices/lobby/files", ct, body)
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Fatalf("büyük dosya: durum %d, beklenen 413", resp.StatusCode)
+	}
+	if e := decodeError(t, resp); !strings.Contains(e.Message, "big.mp4") {
+		t.Fatalf("hata = %+v", e)
+	}
+	if _, ok := ctrl.File("big.mp4"); ok {
+		t.Fatal("sınırı aşan dosya karta yüklendi")
+	}
+}
@@ -0,0 +1,176 @@
+// Command huidu-gateway exposes Huidu LED controllers over an HTTP/JSON API.
+//
+// The gateway keeps one persistent connection per configured controller
+// (with heartbeat and automatic reconnect) and serialises requests per
+// device, so callers never speak the binary TCP protocol themselves.
+//
+// Usage:
+//
+//	huidu-gateway [-listen addr] [-config file] [-device name=host[:port]]... [-token t]
+//
+// Controllers are configured with repeated -device flags or a JSON file:
+//
+//	{
+//	  "listen": ":8080",
+//	  "token": "secret",
+//	  "devices": {
+//	    "lobby":  "10.0.0.11",
+//	    "street": "10.0.0.12:10001"
+//	  }
+//	}
+//
+// The device name is the {id} path segment of the API. The API is described
+// by the OpenAPI document served at /openapi.json, and Prometheus metrics
+// (see package huiduprom) are served at /metrics. If a token is set, every
+// request except /openapi.json, /healthz and /metrics must carry
+// "Authorization: Bearer <token>".
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+)
+
+// config, gateway ayarlarıdır (-config dosyası ve flag'ler).
+type config struct {
+	Listen  string            `json:"listen"`
+	Token   string            `json:"token"`
+	Timeout string            `json:"timeout"`
+	Devices map[string]string `json:"devices"`
+}
+
+// deviceFlags, tekrarlanabilir -device flag'idir.
+type deviceFlags map[string]string
+
+func (f deviceFlags) String() string {
+	names := make([]string, 0, len(f))
+	for name, addr := range f {
+		names = append(names, name+"="+addr)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+func (f deviceFlags) Set(v string) error {
+	name, addr, ok := strings.Cut(v, "=")
+	if !ok || name == "" || addr == "" {
+		return fmt.Errorf("want name=host[:port], got %q", v)
+	}
+	f[name] = addr
+	return nil
+}
+
+func main() {
+	devices := deviceFlags{}
+	configPath := flag.String("config", "", "JSON config file")
+	listen := flag.String("listen", "", `HTTP listen address (default ":8080")`)
+	token := flag.String("token", os.Getenv("HUIDU_GATEWAY_TOKEN"), "bearer token required on API requests (default $HUIDU_GATEWAY_TOKEN)")
+	timeout := flag.Duration("timeout", huidu.DefaultTimeout, "per-command device timeout")
+	maxUpload := flag.Int64("max-upload", defaultMaxUpload, "largest accepted upload in bytes")
+	verbose := flag.Bool("v", false, "log protocol activity")
+	flag.Var(devices, "device", "controller as name=host[:port] (repeatable)")
+	flag.Parse()
+
+	cfg := config{Listen: ":8080", Devices: map[string]string{}}
+	if *configPath != "" {
+		if err := loadConfig(*configPath, &cfg); err != nil {
+			log.Fatalf("huidu-gateway: %v", err)
+		}
+		if cfg.Devices == nil {
+			cfg.Devices = map[string]string{}
+		}
+	}
+	// Flag'ler dosyadaki değerleri geçersiz kılar
+	if *listen != "" {
+		cfg.Listen = *listen
+	}
+	if *token != "" {
+		cfg.Token = *token
+	}
+	for name, addr := range devices {
+		cfg.Devices[name] = addr
+	}
+	if len(cfg.Devices) == 0 {
+		log.Fatal("huidu-gateway: no devices configured (use -device or -config)")
+	}
+
+	deviceTimeout := *timeout
+	if cfg.Timeout != "" && !isFlagSet("timeout") {
+		d, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			log.Fatalf("huidu-gateway: config timeout: %v", err)
+		}
+		deviceTimeout = d
+	}
+
+	logger := log.New(os.Stderr, "huidu-gateway: ", log.LstdFlags)
+	opts := []huidu.DeviceOption{huidu.WithTimeout(deviceTimeout)}
+	if *verbose {
+		opts = append(opts, huidu.WithLogger(logger))
+	}
+
+	gw, err := newGateway(cfg.Devices, logger, opts...)
+	if err != nil {
+		log.Fatalf("huidu-gateway: %v", err)
+	}
+	gw.maxUpload = *maxUpload
+
+	srv := &http.Server{
+		Addr:              cfg.Listen,
+		Handler:           gw.handler(cfg.Token),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	gw.start(ctx)
+
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	logger.Printf("listening on %s (%d devices)", cfg.Listen, len(cfg.Devices))
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Fatal(err)
+	}
+	gw.Close()
+}
+
+// loadConfig, JSON ayar dosyasını cfg üzerine okur.
+func loadConfig(path string, cfg *config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// isFlagSet, flag'in komut satırında açıkça verilip verilmediğini döner.
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
@@ -0,0 +1,9 @@
+package main
+
+import _ "embed"
+
+// openAPISpec, /openapi.json adresinden sunulan API tanımıdır. Yol veya
+// gövde değiştiğinde openapi.json da güncellenmelidir.
+//
+//go:embed openapi.json
+var openAPISpec []byte
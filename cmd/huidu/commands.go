@@ -0,0 +1,721 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+)
+
+// commands, desteklenen alt komutlardır (kullanım metnindeki sırayla).
+var commands = []*command{
+	{name: "info", summary: "show device model, firmware and screen size", run: cmdInfo},
+	{name: "discover", summary: "find controllers on the LAN", offline: true, run: cmdDiscover},
+	{name: "brightness", summary: "get | set <1-100>", run: cmdBrightness},
+	{name: "screen", summary: "on | off", run: cmdScreen},
+	{name: "time", summary: "get | set <YYYY-MM-DD hh:mm:ss> | sync", run: cmdTime},
+	{name: "eth", summary: "get | set [flags]", run: cmdEth},
+	{name: "wifi", summary: "get | set [flags]", run: cmdWifi},
+	{name: "files", summary: "ls | rm <name>... | upload <path>...", run: cmdFiles},
+	{name: "text", summary: "show a text message on the whole screen", run: cmdText},
+	{name: "bootlogo", summary: "get | set <name|path> | clear", run: cmdBootLogo},
+	{name: "server", summary: "get | set <host> <port>", run: cmdServer},
+	{name: "raw", summary: "send a raw SDK XML command (<xml> or - for stdin)", run: cmdRaw},
+}
+
+// findCommand, adı verilen komutu döner (yoksa nil).
+func findCommand(name string) *command {
+	for _, c := range commands {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// subcommand, "get|set" biçimindeki komutların ilk argümanını ayırır.
+// Argüman verilmezse def kullanılır.
+func subcommand(args []string, def string, valid ...string) (string, []string, error) {
+	sub := def
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		sub, args = args[0], args[1:]
+	}
+	for _, v := range valid {
+		if sub == v {
+			return sub, args, nil
+		}
+	}
+	return "", nil, usagef("expected one of %s, got %q", strings.Join(valid, "|"), sub)
+}
+
+// ─── info / discover ────────────────────────────────────────────────────────────
+
+func cmdInfo(a *app, args []string) error {
+	fs := a.flags("info", "info")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	info, err := a.dev.GetDeviceInfoContext(a.ctx)
+	if err != nil {
+		return err
+	}
+	a.print(info, func(w io.Writer) {
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "Device ID:\t%s\n", info.DeviceID)
+		fmt.Fprintf(tw, "Name:\t%s\n", info.DeviceName)
+		fmt.Fprintf(tw, "Model:\t%s\n", info.Model)
+		fmt.Fprintf(tw, "CPU:\t%s\n", info.CPU)
+		fmt.Fprintf(tw, "Firmware:\t%s\n", info.AppVersion)
+		fmt.Fprintf(tw, "FPGA:\t%s\n", info.FPGAVersion)
+		fmt.Fprintf(tw, "Kernel:\t%s\n", info.KernelVersion)
+		fmt.Fprintf(tw, "Screen:\t%dx%d (rotation %d)\n", info.ScreenWidth, info.ScreenHeight, info.ScreenRotation)
+		tw.Flush()
+	})
+	return nil
+}
+
+func cmdDiscover(a *app, args []string) error {
+	fs := a.flags("discover", "discover [-wait d] [-iface name,...]")
+	wait := fs.Duration("wait", 3*time.Second, "how long to listen for answers")
+	ifaces := fs.String("iface", "", "comma-separated interfaces to scan (default all)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	opts := huidu.DiscoverOptions{}
+	if *ifaces != "" {
+		opts.Interfaces = strings.Split(*ifaces, ",")
+	}
+	ctx, cancel := context.WithTimeout(a.ctx, *wait)
+	defer cancel()
+
+	found, err := huidu.Discover(ctx, opts)
+	if err != nil {
+		return err
+	}
+	devices := []huidu.DiscoveredDevice{}
+	for d := range found {
+		devices = append(devices, d)
+	}
+
+	a.print(devices, func(w io.Writer) {
+		if len(devices) == 0 {
+			fmt.Fprintln(w, "no controllers found")
+			return
+		}
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "DEVICE ID\tIP\tPORT\tMODEL\tFIRMWARE\tINTERFACE")
+		for _, d := range devices {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\n", d.DeviceID, d.IP, d.Port, d.Model, d.FirmwareVersion, d.Interface)
+		}
+		tw.Flush()
+	})
+	return nil
+}
+
+// ─── brightness / screen ────────────────────────────────────────────────────────
+
+func cmdBrightness(a *app, args []string) error {
+	sub, args, err := subcommand(args, "get", "get", "set")
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "get":
+		info, err := a.dev.GetLuminanceInfoContext(a.ctx)
+		if err != nil {
+			return err
+		}
+		a.print(info, func(w io.Writer) {
+			modes := []string{"default", "scheduled", "sensor"}
+			mode := fmt.Sprintf("%d", info.Mode)
+			if info.Mode >= 0 && info.Mode < len(modes) {
+				mode = modes[info.Mode]
+			}
+			fmt.Fprintf(w, "Brightness: %d%% (mode: %s)\n", info.DefaultValue, mode)
+			for _, it := range info.CustomItems {
+				fmt.Fprintf(w, "  %s  %d%%  enabled=%v\n", it.Start, it.Percent, it.Enabled)
+			}
+			if info.Mode == 2 {
+				fmt.Fprintf(w, "  sensor %d-%d%%, %ds\n", info.SensorMin, info.SensorMax, info.SensorTime)
+			}
+		})
+		return nil
+
+	default:
+		if len(args) != 1 {
+			return usagef("usage: huidu brightness set <1-100>")
+		}
+		value, err := atoi("brightness", args[0])
+		if err != nil {
+			return err
+		}
+		if value < 1 || value > 100 {
+			return usagef("brightness must be between 1 and 100, got %d", value)
+		}
+		if err := a.dev.SetBrightnessContext(a.ctx, value); err != nil {
+			return err
+		}
+		a.done(fmt.Sprintf("Brightness set to %d%%", value))
+		return nil
+	}
+}
+
+func cmdScreen(a *app, args []string) error {
+	if len(args) != 1 {
+		return usagef("usage: huidu screen on|off")
+	}
+	switch args[0] {
+	case "on":
+		if err := a.dev.OpenScreenContext(a.ctx); err != nil {
+			return err
+		}
+		a.done("Screen on")
+	case "off":
+		if err := a.dev.CloseScreenContext(a.ctx); err != nil {
+			return err
+		}
+		a.done("Screen off")
+	default:
+		return usagef("expected on|off, got %q", args[0])
+	}
+	return nil
+}
+
+// ─── time ───────────────────────────────────────────────────────────────────────
+
+// deviceTimeLayout, TimeInfo.Time alanının biçimidir.
+const deviceTimeLayout = "2006-01-02 15:04:05"
+
+func cmdTime(a *app, args []string) error {
+	sub, args, err := subcommand(args, "get", "get", "set", "sync")
+	if err != nil {
+		return err
+	}
+
+	if sub == "get" {
+		info, err := a.dev.GetTimeInfoContext(a.ctx)
+		if err != nil {
+			return err
+		}
+		a.print(info, func(w io.Writer) {
+			fmt.Fprintf(w, "Time:     %s\n", info.Time)
+			fmt.Fprintf(w, "Timezone: %s\n", info.Timezone)
+			fmt.Fprintf(w, "Summer:   %v\n", info.Summer)
+			fmt.Fprintf(w, "Sync:     %s\n", info.Sync)
+		})
+		return nil
+	}
+
+	usage := "time set [-tz zone] [-summer] [-mode none|network|gps|auto] [YYYY-MM-DD hh:mm:ss]"
+	if sub == "sync" {
+		usage = "time sync [-tz zone]"
+	}
+	fs := a.flags("time "+sub, usage)
+	tz := fs.String("tz", "", `timezone, e.g. "(UTC+03:00)Istanbul" (default: keep)`)
+	summer := fs.Bool("summer", false, "daylight saving time (default: keep)")
+	mode := fs.String("mode", "", "sync mode: none, network, gps or auto (default: none)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	// Verilmeyen alanlar için mevcut ayarları koru
+	info, err := a.dev.GetTimeInfoContext(a.ctx)
+	if err != nil {
+		return err
+	}
+	set := setFlags(fs)
+	if set["tz"] {
+		info.Timezone = *tz
+	}
+	if set["summer"] {
+		info.Summer = *summer
+	}
+
+	switch sub {
+	case "sync":
+		if fs.NArg() != 0 || set["mode"] {
+			return usagef("usage: huidu %s", usage)
+		}
+		info.Sync = "none"
+		info.Time = time.Now().Format(deviceTimeLayout)
+	default:
+		info.Sync = "none"
+		if set["mode"] {
+			info.Sync = *mode
+		}
+		switch {
+		case fs.NArg() == 1:
+			if _, err := time.Parse(deviceTimeLayout, fs.Arg(0)); err != nil {
+				return usagef("invalid time %q, want YYYY-MM-DD hh:mm:ss", fs.Arg(0))
+			}
+			info.Time = fs.Arg(0)
+		case fs.NArg() == 0 && info.Sync != "none":
+			// Otomatik senkronizasyonda zaman değeri kullanılmaz
+		default:
+			return usagef("usage: huidu %s", usage)
+		}
+	}
+
+	if err := a.dev.SetTimeInfoContext(a.ctx, info); err != nil {
+		return err
+	}
+	a.print(info, func(w io.Writer) {
+		if info.Sync == "none" {
+			fmt.Fprintf(w, "Device time set to %s\n", info.Time)
+		} else {
+			fmt.Fprintf(w, "Device time sync set to %s\n", info.Sync)
+		}
+	})
+	return nil
+}
+
+// setFlags, komut satırında açıkça verilen flag'lerin adlarını döner.
+func setFlags(fs *flag.FlagSet) map[string]bool {
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	return set
+}
+
+// ─── eth / wifi ─────────────────────────────────────────────────────────────────
+
+func cmdEth(a *app, args []string) error {
+	sub, args, err := subcommand(args, "get", "get", "set")
+	if err != nil {
+		return err
+	}
+
+	if sub == "get" {
+		info, err := a.dev.GetEthernetInfoContext(a.ctx)
+		if err != nil {
+			return err
+		}
+		a.print(info, func(w io.Writer) { printEthernet(w, info) })
+		return nil
+	}
+
+	fs := a.flags("eth set", "eth set [-dhcp] [-ip a -netmask m -gateway g -dns d] [-enable=false]")
+	dhcp := fs.Bool("dhcp", false, "obtain the address with DHCP")
+	ip := fs.String("ip", "", "static IP address (turns DHCP off)")
+	netmask := fs.String("netmask", "", "static netmask")
+	gateway := fs.String("gateway", "", "static gateway")
+	dns := fs.String("dns", "", "DNS server")
+	enable := fs.Bool("enable", true, "enable the Ethernet port")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	set := setFlags(fs)
+	if len(set) == 0 || fs.NArg() != 0 {
+		return usagef("usage: huidu eth set [-dhcp] [-ip a -netmask m -gateway g -dns d] [-enable=false]")
+	}
+
+	// Verilmeyen alanlar için mevcut ayarları koru
+	info, err := a.dev.GetEthernetInfoContext(a.ctx)
+	if err != nil {
+		return err
+	}
+	if set["enable"] {
+		info.Enabled = *enable
+	}
+	if set["ip"] {
+		info.IP = *ip
+		info.AutoDHCP = false
+	}
+	if set["dhcp"] {
+		info.AutoDHCP = *dhcp
+	}
+	if set["netmask"] {
+		info.Netmask = *netmask
+	}
+	if set["gateway"] {
+		info.Gateway = *gateway
+	}
+	if set["dns"] {
+		info.DNS = *dns
+	}
+
+	if err := a.dev.SetEthernetInfoContext(a.ctx, info); err != nil {
+		return err
+	}
+	a.print(info, func(w io.Writer) {
+		fmt.Fprintln(w, "Ethernet settings updated:")
+		printEthernet(w, info)
+	})
+	return nil
+}
+
+func printEthernet(w io.Writer, info *huidu.EthernetInfo) {
+	fmt.Fprintf(w, "Enabled: %v\n", info.Enabled)
+	fmt.Fprintf(w, "DHCP:    %v\n", info.AutoDHCP)
+	fmt.Fprintf(w, "IP:      %s\n", info.IP)
+	fmt.Fprintf(w, "Netmask: %s\n", info.Netmask)
+	fmt.Fprintf(w, "Gateway: %s\n", info.Gateway)
+	fmt.Fprintf(w, "DNS:     %s\n", info.DNS)
+}
+
+func cmdWifi(a *app, args []string) error {
+	sub, args, err := subcommand(args, "get", "get", "set")
+	if err != nil {
+		return err
+	}
+
+	if sub == "get" {
+		info, err := a.dev.GetWifiInfoContext(a.ctx)
+		if err != nil {
+			return err
+		}
+		a.print(info, func(w io.Writer) {
+			if !info.HasWifi {
+				fmt.Fprintln(w, "No WiFi module")
+				return
+			}
+			mode := "ap"
+			if info.WorkMode == 1 {
+				mode = "station"
+			}
+			fmt.Fprintf(w, "Enabled: %v\n", info.Enabled)
+			fmt.Fprintf(w, "Mode:    %s\n", mode)
+			fmt.Fprintf(w, "AP:      ssid=%q channel=%s ip=%s\n", info.APInfo.SSID, info.APInfo.Channel, info.APInfo.IP)
+			fmt.Fprintf(w, "Station: ssid=%q\n", info.StationSSID)
+		})
+		return nil
+	}
+
+	fs := a.flags("wifi set", "wifi set -mode ap|station [-ssid s] [-password p] [-channel c]")
+	mode := fs.String("mode", "", "work mode: ap or station (default: keep)")
+	ssid := fs.String("ssid", "", "network name for the selected mode")
+	password := fs.String("password", "", "password for the selected mode")
+	channel := fs.String("channel", "", "AP channel")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	set := setFlags(fs)
+	if len(set) == 0 || fs.NArg() != 0 {
+		return usagef("usage: huidu wifi set -mode ap|station [-ssid s] [-password p] [-channel c]")
+	}
+
+	info, err := a.dev.GetWifiInfoContext(a.ctx)
+	if err != nil {
+		return err
+	}
+	switch *mode {
+	case "":
+	case "ap":
+		info.WorkMode = 0
+	case "station":
+		info.WorkMode = 1
+	default:
+		return usagef("invalid mode %q, want ap or station", *mode)
+	}
+
+	// -ssid ve -password seçili moda uygulanır
+	if info.WorkMode == 1 {
+		if set["ssid"] {
+			info.StationSSID = *ssid
+		}
+		if set["password"] {
+			info.StationPass = *password
+		}
+	} else {
+		if set["ssid"] {
+			info.APInfo.SSID = *ssid
+		}
+		if set["password"] {
+			info.APInfo.Password = *password
+		}
+	}
+	if set["channel"] {
+		info.APInfo.Channel = *channel
+	}
+
+	if err := a.dev.SetWifiInfoContext(a.ctx, info); err != nil {
+		return err
+	}
+	a.done("WiFi settings updated")
+	return nil
+}
+
+// ─── files ──────────────────────────────────────────────────────────────────────
+
+// fileTypes, -type flag'inin kabul ettiği dosya tipleridir.
+var fileTypes = map[string]huidu.FileType{
+	"auto":     huidu.FileTypeAuto,
+	"image":    huidu.FileTypeImage,
+	"video":    huidu.FileTypeVideo,
+	"font":     huidu.FileTypeFont,
+	"firmware": huidu.FileTypeFirmware,
+	"fpga":     huidu.FileTypeFPGAConfig,
+	"config":   huidu.FileTypeSettingConfig,
+	"program":  huidu.FileTypeProgramXML,
+}
+
+func cmdFiles(a *app, args []string) error {
+	sub, args, err := subcommand(args, "ls", "ls", "rm", "upload")
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "ls":
+		files, err := a.dev.GetFileListContext(a.ctx)
+		if err != nil {
+			return err
+		}
+		if files == nil {
+			files = []huidu.FileInfo{}
+		}
+		a.print(files, func(w io.Writer) {
+			tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "NAME\tSIZE\tTYPE\tMD5")
+			for _, f := range files {
+				size := fmt.Sprintf("%d", f.Size)
+				if f.ExistSize < f.Size {
+					size = fmt.Sprintf("%d/%d", f.ExistSize, f.Size)
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", f.Name, size, f.Type, f.MD5)
+			}
+			tw.Flush()
+		})
+		return nil
+
+	case "rm":
+		if len(args) == 0 {
+			return usagef("usage: huidu files rm <name>...")
+		}
+		if err := a.dev.DeleteFilesContext(a.ctx, args...); err != nil {
+			return err
+		}
+		a.done(fmt.Sprintf("Deleted %d file(s)", len(args)))
+		return nil
+
+	default:
+		fs := a.flags("files upload", "files upload [-type auto|image|video|font|firmware|...] [-if-changed] <path>...")
+		typ := fs.String("type", "auto", "file type")
+		ifChanged := fs.Bool("if-changed", false, "skip files already on the card with the same name and MD5")
+		if err := parse(fs, args); err != nil {
+			return err
+		}
+		fileType, ok := fileTypes[*typ]
+		if !ok {
+			return usagef("invalid file type %q", *typ)
+		}
+		if fs.NArg() == 0 {
+			return usagef("usage: huidu files upload [-type t] [-if-changed] <path>...")
+		}
+		if *ifChanged && fileType != huidu.FileTypeAuto {
+			return usagef("-if-changed detects file types from extensions; it cannot be combined with -type")
+		}
+
+		type uploaded struct {
+			Name    string `json:"name"`
+			Path    string `json:"path"`
+			Skipped bool   `json:"skipped,omitempty"`
+		}
+		var done []uploaded
+		report := func(path string, skipped bool) {
+			name := filepath.Base(path)
+			done = append(done, uploaded{Name: name, Path: path, Skipped: skipped})
+			if a.json {
+				return
+			}
+			if skipped {
+				fmt.Fprintf(a.stdout, "Skipped %s (unchanged)\n", name)
+			} else {
+				fmt.Fprintf(a.stdout, "Uploaded %s\n", name)
+			}
+		}
+
+		if *ifChanged {
+			res, err := a.dev.UploadIfChangedContext(a.ctx, fs.Args()...)
+			if res != nil {
+				outcome := make(map[string]bool, len(res.Uploaded)+len(res.Skipped))
+				for _, path := range res.Uploaded {
+					outcome[path] = false
+				}
+				for _, path := range res.Skipped {
+					outcome[path] = true
+				}
+				for _, path := range fs.Args() {
+					if skipped, ok := outcome[path]; ok {
+						report(path, skipped)
+					}
+				}
+			}
+			if err != nil {
+				return err
+			}
+		} else {
+			for _, path := range fs.Args() {
+				if err := a.dev.UploadFileAsContext(a.ctx, path, fileType); err != nil {
+					return fmt.Errorf("%s: %w", path, err)
+				}
+				report(path, false)
+			}
+		}
+		if a.json {
+			a.emitJSON(done)
+		}
+		return nil
+	}
+}
+
+// ─── text ───────────────────────────────────────────────────────────────────────
+
+func cmdText(a *app, args []string) error {
+	fs := a.flags("text", "text [-color #rrggbb] [-size n] [-font name] [-effect n] [-speed n] [-duration s] <message>")
+	color := fs.String("color", huidu.ColorRed, "text color (#rrggbb)")
+	size := fs.Int("size", 12, "font size")
+	font := fs.String("font", "", "font name (default Arial)")
+	effect := fs.Int("effect", int(huidu.EffectImmediate), "transition effect number (see README)")
+	speed := fs.Int("speed", 0, "effect speed 1-10")
+	duration := fs.Int("duration", 0, "seconds to show the text")
+	bold := fs.Bool("bold", false, "bold text")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return usagef("usage: huidu text [flags] <message>")
+	}
+	message := strings.Join(fs.Args(), " ")
+
+	err := a.dev.SendTextContext(a.ctx, message, huidu.TextConfig{
+		FontName: *font,
+		FontSize: *size,
+		Color:    *color,
+		Bold:     *bold,
+		Effect:   huidu.EffectType(*effect),
+		Speed:    *speed,
+		Duration: *duration,
+	})
+	if err != nil {
+		return err
+	}
+	a.done("Text sent")
+	return nil
+}
+
+// ─── bootlogo / server ──────────────────────────────────────────────────────────
+
+func cmdBootLogo(a *app, args []string) error {
+	sub, args, err := subcommand(args, "get", "get", "set", "clear")
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "get":
+		info, err := a.dev.GetBootLogoInfoContext(a.ctx)
+		if err != nil {
+			return err
+		}
+		a.print(info, func(w io.Writer) {
+			if !info.Exists {
+				fmt.Fprintln(w, "No boot logo")
+				return
+			}
+			fmt.Fprintf(w, "Boot logo: %s (MD5 %s)\n", info.Name, info.MD5)
+		})
+		return nil
+
+	case "clear":
+		if err := a.dev.ClearBootLogoContext(a.ctx); err != nil {
+			return err
+		}
+		a.done("Boot logo cleared")
+		return nil
+
+	default:
+		if len(args) != 1 {
+			return usagef("usage: huidu bootlogo set <name|path>")
+		}
+		// Yerel bir dosya verilirse önce yüklenir
+		name := args[0]
+		if st, err := os.Stat(name); err == nil && !st.IsDir() {
+			if err := a.dev.UploadFileAsContext(a.ctx, name, huidu.FileTypeImage); err != nil {
+				return err
+			}
+			name = filepath.Base(name)
+		}
+		md5Hash := ""
+		if files, err := a.dev.GetFileListContext(a.ctx); err == nil {
+			for _, f := range files {
+				if f.Name == name {
+					md5Hash = f.MD5
+				}
+			}
+		}
+		if err := a.dev.SetBootLogoContext(a.ctx, &huidu.BootLogoInfo{Exists: true, Name: name, MD5: md5Hash}); err != nil {
+			return err
+		}
+		a.done("Boot logo set to " + name)
+		return nil
+	}
+}
+
+func cmdServer(a *app, args []string) error {
+	sub, args, err := subcommand(args, "get", "get", "set")
+	if err != nil {
+		return err
+	}
+
+	if sub == "get" {
+		info, err := a.dev.GetServerInfoContext(a.ctx)
+		if err != nil {
+			return err
+		}
+		a.print(info, func(w io.Writer) {
+			fmt.Fprintf(w, "Server: %s:%d\n", info.Host, info.Port)
+		})
+		return nil
+	}
+
+	if len(args) != 2 {
+		return usagef("usage: huidu server set <host> <port>")
+	}
+	port, err := atoi("port", args[1])
+	if err != nil {
+		return err
+	}
+	info := &huidu.ServerInfo{Host: args[0], Port: port}
+	if err := a.dev.SetServerInfoContext(a.ctx, info); err != nil {
+		return err
+	}
+	a.done(fmt.Sprintf("Server set to %s:%d", info.Host, info.Port))
+	return nil
+}
+
+// ─── raw ────────────────────────────────────────────────────────────────────────
+
+func cmdRaw(a *app, args []string) error {
+	if len(args) != 1 {
+		return usagef("usage: huidu raw <xml>|-")
+	}
+	xmlStr := args[0]
+	if xmlStr == "-" {
+		data, err := io.ReadAll(a.stdin)
+		if err != nil {
+			return err
+		}
+		xmlStr = string(data)
+	}
+
+	resp, err := a.dev.SendRawXMLContext(a.ctx, xmlStr)
+	if err != nil {
+		return err
+	}
+	a.print(resp, func(w io.Writer) { fmt.Fprintln(w, resp.RawXML) })
+	if !resp.IsSuccess() {
+		code, _ := huidu.ResultCode(resp.Result)
+		return &huidu.ResultError{Op: "raw", Method: resp.Method, Result: resp.Result, Code: code}
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+// Command huidu manages Huidu LED controllers from the command line.
+//
+// Usage:
+//
+//	huidu [-host addr] [-port n] [-timeout d] [-json] [-v] <command> [args]
+//
+// Commands:
+//
+//	info                          device model, firmware and screen size
+//	discover [-wait d]            find controllers on the LAN (UDP broadcast)
+//	brightness get|set <1-100>    read or set the brightness
+//	screen on|off                 switch the display on or off
+//	time get|set|sync             read the clock, set it, or copy the local time
+//	eth get|set                   read or change the Ethernet settings
+//	wifi get|set                  read or change the WiFi settings
+//	files ls|rm|upload            list, delete or upload files
+//	text <message>                show a text program on the whole screen
+//	bootlogo [get|set|clear]      read, set or clear the boot logo
+//	server get|set <host> <port>  read or set the reverse-connection server
+//	raw <xml>|-                   send a raw SDK XML command (SendRawXML)
+//
+// The controller address is taken from -host, or from the HUIDU_HOST
+// environment variable. "huidu <command> -h" prints the flags of a command.
+//
+// Output is human-readable by default. With -json every command prints a
+// single JSON document on stdout; failures print {"error": ..., "code": ...}.
+//
+// Exit status:
+//
+//	0       success
+//	1       other error (protocol error, unmapped SDK result, local I/O)
+//	2       usage error
+//	3       connection failed, lost or timed out
+//	10+N    the controller reported a known huidu.ErrorCode N (1-50)
+//	        (e.g. 14 = ErrDeviceOccupied, 28 = ErrFileNotFound)
+//
+// Codes outside the known range exit with 1, so a controller can never
+// produce a status that wraps around to 0 or collides with the ones above.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strconv"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+)
+
+// Çıkış kodları (bkz. paket dokümanı).
+const (
+	exitOK         = 0
+	exitError      = 1
+	exitUsage      = 2
+	exitConnection = 3
+	exitDeviceBase = 10
+
+	// maxDeviceCode, çıkış koduna eşlenen en büyük huidu.ErrorCode'dur.
+	// exitDeviceBase+maxDeviceCode, kabukların ayırdığı 126 ve üstüne ulaşmaz.
+	maxDeviceCode = int(huidu.ErrPasswdTooSimple)
+)
+
+// app, komutların paylaştığı çalışma durumudur.
+type app struct {
+	ctx    context.Context
+	dev    *huidu.Device
+	json   bool
+	stdout io.Writer
+	stderr io.Writer
+	stdin  io.Reader
+}
+
+// command, tek bir alt komuttur.
+type command struct {
+	name    string
+	summary string
+
+	// offline, komutun cihaz bağlantısı gerektirmediğini belirtir.
+	offline bool
+
+	run func(a *app, args []string) error
+}
+
+// usageError, hatalı komut satırı kullanımıdır (çıkış kodu 2).
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string { return e.msg }
+
+// usagef, biçimlendirilmiş usageError oluşturur.
+func usagef(format string, v ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, v...)}
+}
+
+// connectError, cihaza bağlanılamamasıdır (çıkış kodu 3).
+type connectError struct {
+	err error
+}
+
+func (e *connectError) Error() string { return "connect: " + e.err.Error() }
+func (e *connectError) Unwrap() error { return e.err }
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run, komut satırını işler ve çıkış kodunu döner.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("huidu", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	host := fs.String("host", os.Getenv("HUIDU_HOST"), "controller address (default $HUIDU_HOST)")
+	port := fs.Int("port", huidu.DefaultPort, "controller TCP port")
+	timeout := fs.Duration("timeout", huidu.DefaultTimeout, "per-command timeout")
+	jsonOut := fs.Bool("json", false, "print JSON instead of text")
+	verbose := fs.Bool("v", false, "log protocol activity to stderr")
+	fs.Usage = func() { printUsage(fs) }
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		printUsage(fs)
+		return exitUsage
+	}
+
+	name, rest := fs.Arg(0), fs.Args()[1:]
+	cmd := findCommand(name)
+	if cmd == nil {
+		fmt.Fprintf(stderr, "huidu: unknown command %q\n", name)
+		printUsage(fs)
+		return exitUsage
+	}
+
+	a := &app{
+		ctx:    context.Background(),
+		json:   *jsonOut,
+		stdout: stdout,
+		stderr: stderr,
+		stdin:  stdin,
+	}
+
+	err := func() error {
+		if cmd.offline {
+			return cmd.run(a, rest)
+		}
+		if *host == "" {
+			return usagef("no controller address: use -host or set HUIDU_HOST")
+		}
+		opts := []huidu.DeviceOption{huidu.WithTimeout(*timeout)}
+		if *verbose {
+			opts = append(opts, huidu.WithLogger(log.New(stderr, "huidu: ", log.Ltime)))
+		}
+		a.dev = huidu.NewDevice(*host, *port, opts...)
+		if err := a.dev.Connect(); err != nil {
+			return &connectError{err: err}
+		}
+		defer a.dev.Close()
+		return cmd.run(a, rest)
+	}()
+	if err == nil {
+		return exitOK
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+
+	code := exitCode(err)
+	a.fail(err, code)
+	return code
+}
+
+// exitCode, hatayı çıkış koduna eşler.
+func exitCode(err error) int {
+	var ue *usageError
+	if errors.As(err, &ue) {
+		return exitUsage
+	}
+
+	// Cihazın bildirdiği hata kodu (CmdErrorAnswer, dosya yanıtları veya
+	// eşlenmiş SDK result değeri). Bilinmeyen kodlar 8 bitlik çıkış
+	// durumunda taşıp başarı (0) gibi görünebileceğinden exitError olur.
+	var code huidu.ErrorCode
+	if errors.As(err, &code) && code != huidu.ErrSuccess {
+		if int(code) < 1 || int(code) > maxDeviceCode {
+			return exitError
+		}
+		return exitDeviceBase + int(code)
+	}
+
+	var ce *connectError
+	var ne net.Error
+	if errors.As(err, &ce) || errors.As(err, &ne) ||
+		errors.Is(err, huidu.ErrTimeout) || errors.Is(err, huidu.ErrConnectionLost) || errors.Is(err, huidu.ErrClosed) {
+		return exitConnection
+	}
+	return exitError
+}
+
+// fail, hatayı -json moduna göre yazar.
+func (a *app) fail(err error, code int) {
+	if !a.json {
+		fmt.Fprintf(a.stderr, "huidu: %v\n", err)
+		return
+	}
+
+	out := struct {
+		Error   string `json:"error"`
+		Exit    int    `json:"exit"`
+		Code    *int   `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+		Result  string `json:"result,omitempty"`
+	}{Error: err.Error(), Exit: code}
+
+	var ec huidu.ErrorCode
+	if errors.As(err, &ec) && ec != huidu.ErrSuccess {
+		n := int(ec)
+		out.Code = &n
+		out.Message = ec.String()
+	}
+	var re *huidu.ResultError
+	if errors.As(err, &re) {
+		out.Result = re.Result
+	}
+	a.emitJSON(out)
+}
+
+// print, -json modunda v'yi, aksi halde human ile üretilen metni yazar.
+func (a *app) print(v interface{}, human func(w io.Writer)) {
+	if a.json {
+		a.emitJSON(v)
+		return
+	}
+	human(a.stdout)
+}
+
+// done, çıktısı olmayan komutların sonucunu yazar.
+func (a *app) done(msg string) {
+	a.print(struct {
+		OK bool `json:"ok"`
+	}{OK: true}, func(w io.Writer) { fmt.Fprintln(w, msg) })
+}
+
+// emitJSON, v'yi girintili JSON olarak stdout'a yazar.
+func (a *app) emitJSON(v interface{}) {
+	enc := json.NewEncoder(a.stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintf(a.stderr, "huidu: writing JSON: %v\n", err)
+	}
+}
+
+// flags, alt komut için hata çıktısı stderr'e giden bir FlagSet oluşturur.
+func (a *app) flags(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "usage: huidu %s\n", usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse, alt komut flag'lerini ayrıştırır; hatalar usageError olarak döner.
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{msg: err.Error()}
+	}
+	return nil
+}
+
+// atoi, komut satırı sayısını ayrıştırır.
+func atoi(what, s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, usagef("invalid %s: %q", what, s)
+	}
+	return n, nil
+}
+
+// printUsage, genel kullanım metnini yazar.
+func printUsage(fs *flag.FlagSet) {
+	w := fs.Output()
+	fmt.Fprintln(w, "usage: huidu [flags] <command> [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags:")
+	fs.PrintDefaults()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Exit status: 0 ok, 1 error, 2 usage, 3 connection, 10+N device ErrorCode N.")
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+	"github.com/alparslanahmed/huidu-led/huidutest"
+)
+
+// result, run çağrısının çıktısıdır.
+type result struct {
+	code   int
+	stdout string
+	stderr string
+}
+
+// runCLI, run'ı verilen stdin ile çalıştırır.
+func runCLI(t *testing.T, stdin string, args ...string) result {
+	t.Helper()
+	t.Setenv("HUIDU_HOST", "")
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return result{code: code, stdout: stdout.String(), stderr: stderr.String()}
+}
+
+// startController, TCP üzerinden dinleyen bir simülatör başlatır ve
+// bağlanmak için gereken global bayrakları döner.
+func startController(t *testing.T, opts ...huidutest.Option) (*huidutest.Controller, []string) {
+	t.Helper()
+	ctrl := huidutest.NewController(opts...)
+	if err := ctrl.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ctrl.Close() })
+	return ctrl, []string{"-host", ctrl.Host(), "-port", strconv.Itoa(ctrl.Port()), "-timeout", "2s"}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"kullanım", usagef("hatalı"), exitUsage},
+		{"cihaz meşgul", huidu.ErrDeviceOccupied, exitDeviceBase + 4},
+		{"dosya yok (sarmalanmış)", fmt.Errorf("upload: %w", huidu.ErrFileNotFound), exitDeviceBase + 18},
+		{"SDK result", &huidu.ResultError{Op: "x", Result: "kDeviceOccupied", Code: huidu.ErrDeviceOccupied}, exitDeviceBase + 4},
+		{"en büyük bilinen kod", huidu.ErrPasswdTooSimple, exitDeviceBase + 50},
+		{"bilinmeyen kod", huidu.ErrorCode(51), exitError},
+		{"0'a taşan kod", huidu.ErrorCode(246), exitError},
+		{"negatif kod", huidu.ErrorCode(-3), exitError},
+		{"eşlenmemiş SDK result", &huidu.ResultError{Op: "x", Result: "kSomethingNew"}, exitError},
+		{"bağlantı", &connectError{err: errors.New("refused")}, exitConnection},
+		{"zaman aşımı", huidu.ErrTimeout, exitConnection},
+		{"bağlantı koptu", fmt.Errorf("x: %w", huidu.ErrConnectionLost), exitConnection},
+		{"diğer", io.ErrUnexpectedEOF, exitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Fatalf("exitCode(%v) = %d, beklenen %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"komutsuz", nil, exitUsage, "Commands:"},
+		{"yardım", []string{"-h"}, exitOK, "Commands:"},
+		{"bilinmeyen komut", []string{"-host", "127.0.0.1", "reboot"}, exitUsage, `unknown command "reboot"`},
+		{"adres yok", []string{"info"}, exitUsage, "no controller address"},
+		{"bilinmeyen bayrak", []string{"-nope"}, exitUsage, "-nope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := runCLI(t, "", tt.args...)
+			if r.code != tt.code {
+				t.Fatalf("çıkış kodu = %d, beklenen %d\n%s", r.code, tt.code, r.stderr)
+			}
+			if !strings.Contains(r.stderr, tt.stderr) {
+				t.Fatalf("stderr'de %q yok:\n%s", tt.stderr, r.stderr)
+			}
+		})
+	}
+}
+
+func TestRunCommands(t *testing.T) {
+	ctrl, global := startController(t)
+
+	r := runCLI(t, "", append(global, "info")...)
+	if r.code != exitOK || !strings.Contains(r.stdout, huidutest.DefaultDeviceInfo.DeviceID) {
+		t.Fatalf("info: %d\n%s%s", r.code, r.stdout, r.stderr)
+	}
+
+	r = runCLI(t, "", append(global, "brightness", "set", "35")...)
+	if r.code != exitOK {
+		t.Fatalf("brightness set: %d\n%s", r.code, r.stderr)
+	}
+	if got := ctrl.Luminance().DefaultValue; got != 35 {
+		t.Fatalf("kart parlaklığı = %d", got)
+	}
+
+	r = runCLI(t, "", append(global, "-json", "brightness", "get")...)
+	var lum huidu.LuminanceInfo
+	if r.code != exitOK || json.Unmarshal([]byte(r.stdout), &lum) != nil || lum.DefaultValue != 35 {
+		t.Fatalf("brightness get -json: %d\n%s%s", r.code, r.stdout, r.stderr)
+	}
+
+	// raw, "-" ile XML'i stdin'den okur
+	r = runCLI(t, `<sdk guid="##GUID"><in method="GetDeviceInfo"/></sdk>`, append(global, "raw", "-")...)
+	if r.code != exitOK || !strings.Contains(r.stdout, `result="kSuccess"`) {
+		t.Fatalf("raw -: %d\n%s%s", r.code, r.stdout, r.stderr)
+	}
+	if _, ok := ctrl.LastRequest(huidu.MethodGetDeviceInfo); !ok {
+		t.Fatal("raw isteği karta ulaşmadı")
+	}
+
+	r = runCLI(t, "", append(global, "brightness", "set", "500")...)
+	if r.code != exitUsage {
+		t.Fatalf("geçersiz parlaklık: çıkış kodu %d", r.code)
+	}
+}
+
+func TestRunUploadIfChanged(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	logo := write("logo.png", "logo")
+	intro := write("intro.mp4", "intro")
+	promo := write("promo.mp4", "promo")
+
+	t.Run("argüman sırasıyla", func(t *testing.T) {
+		ctrl, global := startController(t)
+		ctrl.PutFile("logo.png", []byte("logo"), huidu.FileTypeImage)
+
+		r := runCLI(t, "", append(global, "files", "upload", "-if-changed", intro, logo, promo)...)
+		want := "Uploaded intro.mp4\nSkipped logo.png (unchanged)\nUploaded promo.mp4\n"
+		if r.code != exitOK || r.stdout != want {
+			t.Fatalf("çıkış kodu = %d, çıktı:\n%s\nbeklenen:\n%s%s", r.code, r.stdout, want, r.stderr)
+		}
+	})
+
+	t.Run("yarıda kalan yükleme", func(t *testing.T) {
+		ctrl, global := startController(t)
+		ctrl.PutFile("logo.png", []byte("logo"), huidu.FileTypeImage)
+		ctrl.Inject(huidutest.Fault{On: huidu.CmdFileStartAsk, Skip: 1, ErrorAnswer: huidu.ErrNotSpaceToSave})
+
+		r := runCLI(t, "", append(global, "files", "upload", "-if-changed", logo, intro, promo)...)
+		want := "Skipped logo.png (unchanged)\nUploaded intro.mp4\n"
+		if r.code != exitDeviceBase+int(huidu.ErrNotSpaceToSave) || r.stdout != want {
+			t.Fatalf("çıkış kodu = %d, çıktı:\n%s\nbeklenen:\n%s%s", r.code, r.stdout, want, r.stderr)
+		}
+		if !strings.Contains(r.stderr, promo) {
+			t.Errorf("hata başarısız dosyayı belirtmiyor:\n%s", r.stderr)
+		}
+	})
+
+	t.Run("aynı ada düşen yollar", func(t *testing.T) {
+		ctrl, global := startController(t)
+		other := write("media/logo.png", "başka logo")
+
+		r := runCLI(t, "", append(global, "files", "upload", "-if-changed", intro, logo, other)...)
+		if r.code != exitError || r.stdout != "" || !strings.Contains(r.stderr, "logo.png") {
+			t.Fatalf("çıkış kodu = %d\n%s%s", r.code, r.stdout, r.stderr)
+		}
+		if _, ok := ctrl.File("intro.mp4"); ok {
+			t.Error("reddedilen komut dosya yükledi")
+		}
+	})
+}
+
+func TestRunDeviceErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault huidutest.Fault
+		exit  int
+		code  *int
+	}{
+		{"cihaz meşgul", huidutest.Fault{On: huidu.CmdSdkCmdAsk, ErrorAnswer: huidu.ErrDeviceOccupied}, exitDeviceBase + 4, intPtr(4)},
+		{"SDK result", huidutest.Fault{On: huidu.CmdSdkCmdAsk, SdkResult: "kInvalidXmlIndex"}, exitDeviceBase + 21, intPtr(21)},
+		{"bilinmeyen kod", huidutest.Fault{On: huidu.CmdSdkCmdAsk, ErrorAnswer: huidu.ErrorCode(246)}, exitError, intPtr(246)},
+		{"eşlenmemiş result", huidutest.Fault{On: huidu.CmdSdkCmdAsk, SdkResult: "kSomethingNew"}, exitError, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, global := startController(t)
+			f := tt.fault
+			f.Method = huidu.MethodGetLuminancePloy
+			ctrl.Inject(f)
+
+			r := runCLI(t, "", append(global, "-json", "brightness", "get")...)
+			if r.code != tt.exit {
+				t.Fatalf("çıkış kodu = %d, beklenen %d\n%s%s", r.code, tt.exit, r.stdout, r.stderr)
+			}
+			var out struct {
+				Error string `json:"error"`
+				Exit  int    `json:"exit"`
+				Code  *int   `json:"code"`
+			}
+			if err := json.Unmarshal([]byte(r.stdout), &out); err != nil {
+				t.Fatalf("JSON hata çıktısı okunamadı: %v\n%s", err, r.stdout)
+			}
+			if out.Error == "" || out.Exit != tt.exit || fmt.Sprint(deref(out.Code)) != fmt.Sprint(deref(tt.code)) {
+				t.Fatalf("hata çıktısı = %s", r.stdout)
+			}
+		})
+	}
+}
+
+func TestRunConnectionRefused(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	r := runCLI(t, "", "-host", "127.0.0.1", "-port", strconv.Itoa(port), "-timeout", "1s", "info")
+	if r.code != exitConnection || !strings.Contains(r.stderr, "connect:") {
+		t.Fatalf("çıkış kodu = %d\n%s", r.code, r.stderr)
+	}
+}
+
+func intPtr(n int) *int { return &n }
+
+// deref, nil işaretçiyi "nil" olarak gösterir.
+func deref(p *int) interface{} {
+	if p == nil {
+		return "nil"
+	}
+	return *p
+}
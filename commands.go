@@ -1,6 +1,7 @@
 package huidu
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -17,18 +18,23 @@ import (
 //	}
 //	fmt.Printf("Model: %s, Ekran: %dx%d\n", info.Model, info.ScreenWidth, info.ScreenHeight)
 func (d *Device) GetDeviceInfo() (*DeviceInfo, error) {
+	return d.GetDeviceInfoContext(context.Background())
+}
+
+// GetDeviceInfoContext, GetDeviceInfo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) GetDeviceInfoContext(ctx context.Context) (*DeviceInfo, error) {
 	if err := d.ensureConnected(); err != nil {
 		return nil, err
 	}
 
-	xmlData := buildSdkXML(d.sdkGUID, MethodGetDeviceInfo, "")
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodGetDeviceInfo, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("GetDeviceInfo başarısız: %s", resp.Result)
+		return nil, newResultError("GetDeviceInfo", resp)
 	}
 
 	info, err := parseDeviceInfoXML(resp.InnerXML)
@@ -54,18 +60,23 @@ func (d *Device) GetDeviceInfo() (*DeviceInfo, error) {
 //	}
 //	fmt.Printf("IP: %s, DHCP: %v\n", eth.IP, eth.AutoDHCP)
 func (d *Device) GetEthernetInfo() (*EthernetInfo, error) {
+	return d.GetEthernetInfoContext(context.Background())
+}
+
+// GetEthernetInfoContext, GetEthernetInfo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) GetEthernetInfoContext(ctx context.Context) (*EthernetInfo, error) {
 	if err := d.ensureConnected(); err != nil {
 		return nil, err
 	}
 
-	xmlData := buildSdkXML(d.sdkGUID, MethodGetEth0Info, "")
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodGetEth0Info, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("GetEthernetInfo başarısız: %s", resp.Result)
+		return nil, newResultError("GetEthernetInfo", resp)
 	}
 
 	return parseEthernetInfoXML(resp.InnerXML)
@@ -85,19 +96,24 @@ func (d *Device) GetEthernetInfo() (*EthernetInfo, error) {
 //	    DNS:      "8.8.8.8",
 //	})
 func (d *Device) SetEthernetInfo(info *EthernetInfo) error {
+	return d.SetEthernetInfoContext(context.Background(), info)
+}
+
+// SetEthernetInfoContext, SetEthernetInfo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) SetEthernetInfoContext(ctx context.Context, info *EthernetInfo) error {
 	if err := d.ensureConnected(); err != nil {
 		return err
 	}
 
 	inner := buildSetEthernetXML(info)
-	xmlData := buildSdkXML(d.sdkGUID, MethodSetEth0Info, inner)
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodSetEth0Info, inner)
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return err
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("SetEthernetInfo başarısız: %s", resp.Result)
+		return newResultError("SetEthernetInfo", resp)
 	}
 
 	return nil
@@ -114,18 +130,23 @@ func (d *Device) SetEthernetInfo(info *EthernetInfo) error {
 //	    fmt.Printf("WiFi SSID: %s, Mod: %d\n", wifi.APInfo.SSID, wifi.WorkMode)
 //	}
 func (d *Device) GetWifiInfo() (*WifiInfo, error) {
+	return d.GetWifiInfoContext(context.Background())
+}
+
+// GetWifiInfoContext, GetWifiInfo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) GetWifiInfoContext(ctx context.Context) (*WifiInfo, error) {
 	if err := d.ensureConnected(); err != nil {
 		return nil, err
 	}
 
-	xmlData := buildSdkXML(d.sdkGUID, MethodGetWifiInfo, "")
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodGetWifiInfo, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("GetWifiInfo başarısız: %s", resp.Result)
+		return nil, newResultError("GetWifiInfo", resp)
 	}
 
 	return parseWifiInfoXML(resp.InnerXML)
@@ -134,6 +155,11 @@ func (d *Device) GetWifiInfo() (*WifiInfo, error) {
 // SetWifiInfo, cihazın WiFi ayarlarını yapılandırır.
 // WiFi modülü olmayan cihazlarda hata döner.
 func (d *Device) SetWifiInfo(info *WifiInfo) error {
+	return d.SetWifiInfoContext(context.Background(), info)
+}
+
+// SetWifiInfoContext, SetWifiInfo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) SetWifiInfoContext(ctx context.Context, info *WifiInfo) error {
 	if err := d.ensureConnected(); err != nil {
 		return err
 	}
@@ -158,14 +184,81 @@ func (d *Device) SetWifiInfo(info *WifiInfo) error {
 		xmlElement("passwd", "value", info.StationPass),
 	)
 
-	xmlData := buildSdkXML(d.sdkGUID, MethodSetWifiInfo, inner)
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodSetWifiInfo, inner)
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return err
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("SetWifiInfo başarısız: %s", resp.Result)
+		return newResultError("SetWifiInfo", resp)
+	}
+
+	return nil
+}
+
+// GetCellularInfo, 3G/4G modem ve mobil veri bağlantısının durumunu sorgular.
+// Modemi olmayan cihazlarda HasModem=false döner.
+//
+//	cell, err := dev.GetCellularInfo()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if cell.HasModem {
+//	    fmt.Printf("%s %s, sinyal: %d, SIM: %v, IP: %s\n",
+//	        cell.Operator, cell.Network, cell.Signal, cell.SIMInserted, cell.IP)
+//	}
+func (d *Device) GetCellularInfo() (*CellularInfo, error) {
+	return d.GetCellularInfoContext(context.Background())
+}
+
+// GetCellularInfoContext, GetCellularInfo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) GetCellularInfoContext(ctx context.Context) (*CellularInfo, error) {
+	if err := d.ensureConnected(); err != nil {
+		return nil, err
+	}
+
+	xmlData := buildSdkXML(d.GUID(), MethodGetPppoeInfo, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		return nil, newResultError("GetCellularInfo", resp)
+	}
+
+	return parseCellularInfoXML(resp.InnerXML)
+}
+
+// SetAPN, mobil veri bağlantısının APN ayarlarını yapar.
+//
+// DİKKAT: Yanlış APN, mobil bağlantı üzerinden erişilen kartlara uzaktan
+// erişimi keser.
+//
+//	err := dev.SetAPN(&huidu.APNInfo{
+//	    APN:  "internet",
+//	    Auth: huidu.APNAuthNone,
+//	})
+func (d *Device) SetAPN(info *APNInfo) error {
+	return d.SetAPNContext(context.Background(), info)
+}
+
+// SetAPNContext, SetAPN ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) SetAPNContext(ctx context.Context, info *APNInfo) error {
+	if err := d.ensureConnected(); err != nil {
+		return err
+	}
+
+	inner := buildSetAPNXML(info)
+	xmlData := buildSdkXML(d.GUID(), MethodSetApn, inner)
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return newResultError("SetAPN", resp)
 	}
 
 	return nil
@@ -189,18 +282,23 @@ func (d *Device) SetWifiInfo(info *WifiInfo) error {
 //     }
 //     fmt.Printf("Mod: %d, Parlaklık: %d%%\n", lum.Mode, lum.DefaultValue)
 func (d *Device) GetLuminanceInfo() (*LuminanceInfo, error) {
+	return d.GetLuminanceInfoContext(context.Background())
+}
+
+// GetLuminanceInfoContext, GetLuminanceInfo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) GetLuminanceInfoContext(ctx context.Context) (*LuminanceInfo, error) {
 	if err := d.ensureConnected(); err != nil {
 		return nil, err
 	}
 
-	xmlData := buildSdkXML(d.sdkGUID, MethodGetLuminancePloy, "")
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodGetLuminancePloy, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("GetLuminanceInfo başarısız: %s", resp.Result)
+		return nil, newResultError("GetLuminanceInfo", resp)
 	}
 
 	return parseLuminanceInfoXML(resp.InnerXML)
@@ -227,6 +325,11 @@ func (d *Device) GetLuminanceInfo() (*LuminanceInfo, error) {
 //	    },
 //	})
 func (d *Device) SetLuminanceInfo(info *LuminanceInfo) error {
+	return d.SetLuminanceInfoContext(context.Background(), info)
+}
+
+// SetLuminanceInfoContext, SetLuminanceInfo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) SetLuminanceInfoContext(ctx context.Context, info *LuminanceInfo) error {
 	if err := d.ensureConnected(); err != nil {
 		return err
 	}
@@ -243,14 +346,14 @@ func (d *Device) SetLuminanceInfo(info *LuminanceInfo) error {
 	}
 
 	inner := buildSetLuminanceXML(info)
-	xmlData := buildSdkXML(d.sdkGUID, MethodSetLuminancePloy, inner)
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodSetLuminancePloy, inner)
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return err
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("SetLuminanceInfo başarısız: %s", resp.Result)
+		return newResultError("SetLuminanceInfo", resp)
 	}
 
 	return nil
@@ -261,13 +364,18 @@ func (d *Device) SetLuminanceInfo(info *LuminanceInfo) error {
 //
 //	err := dev.SetBrightness(80) // %80 parlaklık
 func (d *Device) SetBrightness(value int) error {
+	return d.SetBrightnessContext(context.Background(), value)
+}
+
+// SetBrightnessContext, SetBrightness ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) SetBrightnessContext(ctx context.Context, value int) error {
 	if value < 1 {
 		value = 1
 	}
 	if value > 100 {
 		value = 100
 	}
-	return d.SetLuminanceInfo(&LuminanceInfo{
+	return d.SetLuminanceInfoContext(ctx, &LuminanceInfo{
 		Mode:         0,
 		DefaultValue: value,
 	})
@@ -283,18 +391,23 @@ func (d *Device) SetBrightness(value int) error {
 //	}
 //	fmt.Printf("Saat Dilimi: %s, Senkronizasyon: %s\n", ti.Timezone, ti.Sync)
 func (d *Device) GetTimeInfo() (*TimeInfo, error) {
+	return d.GetTimeInfoContext(context.Background())
+}
+
+// GetTimeInfoContext, GetTimeInfo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) GetTimeInfoContext(ctx context.Context) (*TimeInfo, error) {
 	if err := d.ensureConnected(); err != nil {
 		return nil, err
 	}
 
-	xmlData := buildSdkXML(d.sdkGUID, MethodGetTimeInfo, "")
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodGetTimeInfo, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("GetTimeInfo başarısız: %s", resp.Result)
+		return nil, newResultError("GetTimeInfo", resp)
 	}
 
 	return parseTimeInfoXML(resp.InnerXML)
@@ -309,19 +422,24 @@ func (d *Device) GetTimeInfo() (*TimeInfo, error) {
 //	    Time:     "2024-01-15 14:30:00",
 //	})
 func (d *Device) SetTimeInfo(info *TimeInfo) error {
+	return d.SetTimeInfoContext(context.Background(), info)
+}
+
+// SetTimeInfoContext, SetTimeInfo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) SetTimeInfoContext(ctx context.Context, info *TimeInfo) error {
 	if err := d.ensureConnected(); err != nil {
 		return err
 	}
 
 	inner := buildSetTimeXML(info)
-	xmlData := buildSdkXML(d.sdkGUID, MethodSetTimeInfo, inner)
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodSetTimeInfo, inner)
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return err
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("SetTimeInfo başarısız: %s", resp.Result)
+		return newResultError("SetTimeInfo", resp)
 	}
 
 	return nil
@@ -333,18 +451,23 @@ func (d *Device) SetTimeInfo(info *TimeInfo) error {
 //
 //	err := dev.OpenScreen()
 func (d *Device) OpenScreen() error {
+	return d.OpenScreenContext(context.Background())
+}
+
+// OpenScreenContext, OpenScreen ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) OpenScreenContext(ctx context.Context) error {
 	if err := d.ensureConnected(); err != nil {
 		return err
 	}
 
-	xmlData := buildSdkXML(d.sdkGUID, MethodOpenScreen, "")
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodOpenScreen, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return err
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("OpenScreen başarısız: %s", resp.Result)
+		return newResultError("OpenScreen", resp)
 	}
 
 	return nil
@@ -355,18 +478,23 @@ func (d *Device) OpenScreen() error {
 //
 //	err := dev.CloseScreen()
 func (d *Device) CloseScreen() error {
+	return d.CloseScreenContext(context.Background())
+}
+
+// CloseScreenContext, CloseScreen ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) CloseScreenContext(ctx context.Context) error {
 	if err := d.ensureConnected(); err != nil {
 		return err
 	}
 
-	xmlData := buildSdkXML(d.sdkGUID, MethodCloseScreen, "")
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodCloseScreen, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return err
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("CloseScreen başarısız: %s", resp.Result)
+		return newResultError("CloseScreen", resp)
 	}
 
 	return nil
@@ -382,18 +510,23 @@ func (d *Device) CloseScreen() error {
 //	}
 //	fmt.Printf("Zamanlı kontrol aktif: %v, Kural sayısı: %d\n", sw.PloyEnabled, len(sw.Items))
 func (d *Device) GetSwitchTimeInfo() (*SwitchTimeInfo, error) {
+	return d.GetSwitchTimeInfoContext(context.Background())
+}
+
+// GetSwitchTimeInfoContext, GetSwitchTimeInfo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) GetSwitchTimeInfoContext(ctx context.Context) (*SwitchTimeInfo, error) {
 	if err := d.ensureConnected(); err != nil {
 		return nil, err
 	}
 
-	xmlData := buildSdkXML(d.sdkGUID, MethodGetSwitchTime, "")
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodGetSwitchTime, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("GetSwitchTimeInfo başarısız: %s", resp.Result)
+		return nil, newResultError("GetSwitchTimeInfo", resp)
 	}
 
 	return parseSwitchTimeInfoXML(resp.InnerXML)
@@ -409,19 +542,24 @@ func (d *Device) GetSwitchTimeInfo() (*SwitchTimeInfo, error) {
 //	    },
 //	})
 func (d *Device) SetSwitchTimeInfo(info *SwitchTimeInfo) error {
+	return d.SetSwitchTimeInfoContext(context.Background(), info)
+}
+
+// SetSwitchTimeInfoContext, SetSwitchTimeInfo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) SetSwitchTimeInfoContext(ctx context.Context, info *SwitchTimeInfo) error {
 	if err := d.ensureConnected(); err != nil {
 		return err
 	}
 
 	inner := buildSetSwitchTimeXML(info)
-	xmlData := buildSdkXML(d.sdkGUID, MethodSetSwitchTime, inner)
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodSetSwitchTime, inner)
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return err
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("SetSwitchTimeInfo başarısız: %s", resp.Result)
+		return newResultError("SetSwitchTimeInfo", resp)
 	}
 
 	return nil
@@ -431,18 +569,23 @@ func (d *Device) SetSwitchTimeInfo(info *SwitchTimeInfo) error {
 
 // GetBootLogoInfo, açılış logosu bilgisini sorgular.
 func (d *Device) GetBootLogoInfo() (*BootLogoInfo, error) {
+	return d.GetBootLogoInfoContext(context.Background())
+}
+
+// GetBootLogoInfoContext, GetBootLogoInfo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) GetBootLogoInfoContext(ctx context.Context) (*BootLogoInfo, error) {
 	if err := d.ensureConnected(); err != nil {
 		return nil, err
 	}
 
-	xmlData := buildSdkXML(d.sdkGUID, MethodGetBootLogo, "")
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodGetBootLogo, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("GetBootLogoInfo başarısız: %s", resp.Result)
+		return nil, newResultError("GetBootLogoInfo", resp)
 	}
 
 	return parseBootLogoInfoXML(resp.InnerXML)
@@ -451,19 +594,24 @@ func (d *Device) GetBootLogoInfo() (*BootLogoInfo, error) {
 // SetBootLogo, açılış logosunu ayarlar.
 // Önce görsel dosyasını UploadFile ile cihaza yükleyin.
 func (d *Device) SetBootLogo(info *BootLogoInfo) error {
+	return d.SetBootLogoContext(context.Background(), info)
+}
+
+// SetBootLogoContext, SetBootLogo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) SetBootLogoContext(ctx context.Context, info *BootLogoInfo) error {
 	if err := d.ensureConnected(); err != nil {
 		return err
 	}
 
 	inner := buildSetBootLogoXML(info)
-	xmlData := buildSdkXML(d.sdkGUID, MethodSetBootLogoName, inner)
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodSetBootLogoName, inner)
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return err
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("SetBootLogo başarısız: %s", resp.Result)
+		return newResultError("SetBootLogo", resp)
 	}
 
 	return nil
@@ -471,18 +619,23 @@ func (d *Device) SetBootLogo(info *BootLogoInfo) error {
 
 // ClearBootLogo, açılış logosunu temizler.
 func (d *Device) ClearBootLogo() error {
+	return d.ClearBootLogoContext(context.Background())
+}
+
+// ClearBootLogoContext, ClearBootLogo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) ClearBootLogoContext(ctx context.Context) error {
 	if err := d.ensureConnected(); err != nil {
 		return err
 	}
 
-	xmlData := buildSdkXML(d.sdkGUID, MethodClearBootLogo, "")
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodClearBootLogo, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return err
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("ClearBootLogo başarısız: %s", resp.Result)
+		return newResultError("ClearBootLogo", resp)
 	}
 
 	return nil
@@ -500,18 +653,23 @@ func (d *Device) ClearBootLogo() error {
 //	    fmt.Printf("Font: %s (%s)\n", f.FontName, f.FileName)
 //	}
 func (d *Device) GetFontInfo() ([]FontInfo, error) {
+	return d.GetFontInfoContext(context.Background())
+}
+
+// GetFontInfoContext, GetFontInfo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) GetFontInfoContext(ctx context.Context) ([]FontInfo, error) {
 	if err := d.ensureConnected(); err != nil {
 		return nil, err
 	}
 
-	xmlData := buildSdkXML(d.sdkGUID, MethodGetAllFontInfo, "")
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodGetAllFontInfo, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("GetFontInfo başarısız: %s", resp.Result)
+		return nil, newResultError("GetFontInfo", resp)
 	}
 
 	return parseFontInfoXML(resp.InnerXML)
@@ -521,18 +679,23 @@ func (d *Device) GetFontInfo() ([]FontInfo, error) {
 
 // GetServerInfo, cihazın bağlandığı TCP sunucu bilgisini sorgular.
 func (d *Device) GetServerInfo() (*ServerInfo, error) {
+	return d.GetServerInfoContext(context.Background())
+}
+
+// GetServerInfoContext, GetServerInfo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) GetServerInfoContext(ctx context.Context) (*ServerInfo, error) {
 	if err := d.ensureConnected(); err != nil {
 		return nil, err
 	}
 
-	xmlData := buildSdkXML(d.sdkGUID, MethodGetSDKTcpServer, "")
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodGetSDKTcpServer, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("GetServerInfo başarısız: %s", resp.Result)
+		return nil, newResultError("GetServerInfo", resp)
 	}
 
 	return parseServerInfoXML(resp.InnerXML)
@@ -543,19 +706,152 @@ func (d *Device) GetServerInfo() (*ServerInfo, error) {
 // DİKKAT: Bu ayar, cihazın uzak sunucuya bağlanmasını sağlar.
 // Yanlış ayar uzak erişimi engelleyebilir.
 func (d *Device) SetServerInfo(info *ServerInfo) error {
+	return d.SetServerInfoContext(context.Background(), info)
+}
+
+// SetServerInfoContext, SetServerInfo ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) SetServerInfoContext(ctx context.Context, info *ServerInfo) error {
 	if err := d.ensureConnected(); err != nil {
 		return err
 	}
 
 	inner := buildSetServerXML(info)
-	xmlData := buildSdkXML(d.sdkGUID, MethodSetSDKTcpServer, inner)
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodSetSDKTcpServer, inner)
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return err
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("SetServerInfo başarısız: %s", resp.Result)
+		return newResultError("SetServerInfo", resp)
+	}
+
+	return nil
+}
+
+// ─── Çoklu Ekran Senkronizasyon Komutları ───────────────────────────────────────
+
+// GetMultiScreenSync, çoklu ekran senkronizasyon ayarını sorgular.
+//
+//	sync, err := dev.GetMultiScreenSync()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("Senkron oynatma: %v\n", sync.Enabled)
+func (d *Device) GetMultiScreenSync() (*MultiScreenSyncInfo, error) {
+	return d.GetMultiScreenSyncContext(context.Background())
+}
+
+// GetMultiScreenSyncContext, GetMultiScreenSync ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) GetMultiScreenSyncContext(ctx context.Context) (*MultiScreenSyncInfo, error) {
+	if err := d.ensureConnected(); err != nil {
+		return nil, err
+	}
+
+	xmlData := buildSdkXML(d.GUID(), MethodGetMulScreenSync, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		return nil, newResultError("GetMultiScreenSync", resp)
+	}
+
+	return parseMultiScreenSyncXML(resp.InnerXML)
+}
+
+// SetMultiScreenSync, çoklu ekran senkronizasyonunu açar veya kapatır.
+//
+// Yan yana çalışan kartların içeriği kare hizalı oynatabilmesi için ayar
+// gruptaki her kartta yapılmalı ve kartlara aynı program gönderilmelidir.
+//
+//	err := dev.SetMultiScreenSync(&huidu.MultiScreenSyncInfo{Enabled: true})
+//
+// Diğer senkron parametrelerini korumak için GetMultiScreenSync'ten dönen
+// değer değiştirilip geri gönderilmelidir; RawXML'deki ayarlar aynen yazılır.
+func (d *Device) SetMultiScreenSync(info *MultiScreenSyncInfo) error {
+	return d.SetMultiScreenSyncContext(context.Background(), info)
+}
+
+// SetMultiScreenSyncContext, SetMultiScreenSync ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) SetMultiScreenSyncContext(ctx context.Context, info *MultiScreenSyncInfo) error {
+	inner, err := buildSetMultiScreenSyncXML(info)
+	if err != nil {
+		return err
+	}
+	if err := d.ensureConnected(); err != nil {
+		return err
+	}
+
+	xmlData := buildSdkXML(d.GUID(), MethodSetMulScreenSync, inner)
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return newResultError("SetMultiScreenSync", resp)
+	}
+
+	return nil
+}
+
+// ─── GPS Komutları ──────────────────────────────────────────────────────────────
+
+// GetGPSReporting, kartın GPS konumunu kendiliğinden raporlayıp
+// raporlamadığını sorgular.
+func (d *Device) GetGPSReporting() (bool, error) {
+	return d.GetGPSReportingContext(context.Background())
+}
+
+// GetGPSReportingContext, GetGPSReporting ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) GetGPSReportingContext(ctx context.Context) (bool, error) {
+	if err := d.ensureConnected(); err != nil {
+		return false, err
+	}
+
+	xmlData := buildSdkXML(d.GUID(), MethodGetGpsRespondEnable, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
+	if err != nil {
+		return false, err
+	}
+
+	if !resp.IsSuccess() {
+		return false, newResultError("GetGPSReporting", resp)
+	}
+
+	return parseGPSReportingXML(resp.InnerXML)
+}
+
+// SetGPSReporting, GPS konum raporlamasını açar veya kapatır.
+// Açıkken GPS modüllü kartlar konumlarını CmdGPSInfoAnswer paketleriyle
+// gönderir; paketler SubscribeGPS ile alınır.
+//
+//	stop := dev.SubscribeGPS(func(pos huidu.GPSPosition) {
+//	    log.Printf("%.6f,%.6f %.0f km/sa", pos.Latitude, pos.Longitude, pos.Speed)
+//	})
+//	defer stop()
+//	err := dev.SetGPSReporting(true)
+func (d *Device) SetGPSReporting(enabled bool) error {
+	return d.SetGPSReportingContext(context.Background(), enabled)
+}
+
+// SetGPSReportingContext, SetGPSReporting ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) SetGPSReportingContext(ctx context.Context, enabled bool) error {
+	if err := d.ensureConnected(); err != nil {
+		return err
+	}
+
+	inner := buildSetGPSReportingXML(enabled)
+	xmlData := buildSdkXML(d.GUID(), MethodSetGpsRespondEnable, inner)
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return newResultError("SetGPSReporting", resp)
 	}
 
 	return nil
@@ -573,18 +869,23 @@ func (d *Device) SetServerInfo(info *ServerInfo) error {
 //	    fmt.Printf("%s (%d bytes, MD5: %s)\n", f.Name, f.Size, f.MD5)
 //	}
 func (d *Device) GetFileList() ([]FileInfo, error) {
+	return d.GetFileListContext(context.Background())
+}
+
+// GetFileListContext, GetFileList ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) GetFileListContext(ctx context.Context) ([]FileInfo, error) {
 	if err := d.ensureConnected(); err != nil {
 		return nil, err
 	}
 
-	xmlData := buildSdkXML(d.sdkGUID, MethodGetFiles, "")
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodGetFiles, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return nil, err
 	}
 
 	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("GetFileList başarısız: %s", resp.Result)
+		return nil, newResultError("GetFileList", resp)
 	}
 
 	return parseFileListXML(resp.InnerXML)
@@ -594,6 +895,11 @@ func (d *Device) GetFileList() ([]FileInfo, error) {
 //
 //	err := dev.DeleteFiles("image1.jpg", "video1.mp4")
 func (d *Device) DeleteFiles(fileNames ...string) error {
+	return d.DeleteFilesContext(context.Background(), fileNames...)
+}
+
+// DeleteFilesContext, DeleteFiles ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) DeleteFilesContext(ctx context.Context, fileNames ...string) error {
 	if err := d.ensureConnected(); err != nil {
 		return err
 	}
@@ -603,14 +909,14 @@ func (d *Device) DeleteFiles(fileNames ...string) error {
 	}
 
 	inner := buildDeleteFilesXML(fileNames)
-	xmlData := buildSdkXML(d.sdkGUID, MethodDeleteFiles, inner)
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	xmlData := buildSdkXML(d.GUID(), MethodDeleteFiles, inner)
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return err
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("DeleteFiles başarısız: %s", resp.Result)
+		return newResultError("DeleteFiles", resp)
 	}
 
 	return nil
@@ -624,6 +930,11 @@ func (d *Device) DeleteFiles(fileNames ...string) error {
 // C# SDK'da AddProgram metodu ekrandaki tüm programları değiştirir (replace).
 // Boş bir screen göndermek tüm programları silmek anlamına gelir.
 func (d *Device) DeleteAllPrograms() error {
+	return d.DeleteAllProgramsContext(context.Background())
+}
+
+// DeleteAllProgramsContext, DeleteAllPrograms ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) DeleteAllProgramsContext(ctx context.Context) error {
 	if err := d.ensureConnected(); err != nil {
 		return err
 	}
@@ -632,17 +943,18 @@ func (d *Device) DeleteAllPrograms() error {
 	// AddProgram, mevcut tüm programları bu boş ekranla değiştirir → ekran temizlenir.
 	emptyScreen := NewScreen()
 	screenXML := emptyScreen.toXML()
-	xmlData := buildSdkXML(d.sdkGUID, MethodAddProgram, screenXML)
+	xmlData := buildSdkXML(d.GUID(), MethodAddProgram, screenXML)
 
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return err
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("DeleteAllPrograms başarısız: %s", resp.Result)
+		return newResultError("DeleteAllPrograms", resp)
 	}
 
+	d.setScreen(screenXML)
 	return nil
 }
 
@@ -651,15 +963,20 @@ func (d *Device) DeleteAllPrograms() error {
 //
 // GUID otomatik olarak mevcut oturum GUID'i ile değiştirilir.
 func (d *Device) SendRawXML(xmlStr string) (*SdkResponse, error) {
+	return d.SendRawXMLContext(context.Background(), xmlStr)
+}
+
+// SendRawXMLContext, SendRawXML ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) SendRawXMLContext(ctx context.Context, xmlStr string) (*SdkResponse, error) {
 	if err := d.ensureConnected(); err != nil {
 		return nil, err
 	}
 
 	// GUID'i güncelle
 	currentGUID := extractGUID(xmlStr)
-	if currentGUID != "" && currentGUID != d.sdkGUID {
-		xmlStr = replaceGUID(xmlStr, currentGUID, d.sdkGUID)
+	if currentGUID != "" && currentGUID != d.GUID() {
+		xmlStr = replaceGUID(xmlStr, currentGUID, d.GUID())
 	}
 
-	return d.sendSdkCmdAndReceive([]byte(xmlStr))
+	return d.sendSdkCmdAndReceive(ctx, []byte(xmlStr))
 }
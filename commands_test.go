@@ -0,0 +1,189 @@
+package huidu_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+	"github.com/alparslanahmed/huidu-led/huidutest"
+)
+
+// connect, ctrl'a net.Pipe üzerinden bağlı bir Device döner.
+func connect(t *testing.T, ctrl *huidutest.Controller) *huidu.Device {
+	t.Helper()
+	dev := huidu.NewDeviceFromConn(ctrl.Pipe())
+	if err := dev.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { dev.Close() })
+	return dev
+}
+
+func TestMultiScreenSyncKeepsUnknownSettings(t *testing.T) {
+	const stored = `<enable value="false"/><mode value="master"/><delay ms="40"/>`
+	ctrl := huidutest.NewController(huidutest.WithSetting("MulScreenSync", stored))
+	defer ctrl.Close()
+	dev := connect(t, ctrl)
+
+	sync, err := dev.GetMultiScreenSync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sync.Enabled {
+		t.Fatal("Enabled = true, beklenen false")
+	}
+
+	sync.Enabled = true
+	if err := dev.SetMultiScreenSync(sync); err != nil {
+		t.Fatal(err)
+	}
+	// Yanıt ayrıştırılırken boş elemanlar <x></x> biçimine açılır; içerik aynıdır.
+	want := `<enable value="true"></enable><mode value="master"></mode><delay ms="40"></delay>`
+	if got := ctrl.Setting("MulScreenSync"); got != want {
+		t.Fatalf("kart ayarı = %s, beklenen %s", got, want)
+	}
+}
+
+func TestMultiScreenSyncWithoutRawXML(t *testing.T) {
+	ctrl := huidutest.NewController()
+	defer ctrl.Close()
+	dev := connect(t, ctrl)
+
+	if err := dev.SetMultiScreenSync(&huidu.MultiScreenSyncInfo{Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ctrl.Setting("MulScreenSync"); got != `<enable value="true"/>` {
+		t.Fatalf("kart ayarı = %s", got)
+	}
+
+	// <enable> içermeyen ham ayara eklenir, bozuk XML reddedilir.
+	err := dev.SetMultiScreenSync(&huidu.MultiScreenSyncInfo{Enabled: true, RawXML: `<mode value="slave"/>`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ctrl.Setting("MulScreenSync"); !strings.HasSuffix(got, `<enable value="true"/>`) || !strings.HasPrefix(got, `<mode value="slave"/>`) {
+		t.Fatalf("kart ayarı = %s", got)
+	}
+	if err := dev.SetMultiScreenSync(&huidu.MultiScreenSyncInfo{RawXML: `<enable value="true"`}); err == nil {
+		t.Fatal("bozuk RawXML kabul edildi")
+	}
+}
+
+// pppoeAnswer, 4G modemli bir kartın GetPppoeInfo yanıtının iç XML'idir.
+const pppoeAnswer = `<pppoe valid="true">
+  <enable value="true"/><insert value="true"/><status value="connected"/>
+  <network value="4G"/><operators value="Turkcell"/><signal value="4"/>
+  <dbm value="-71"/><address ip="10.64.12.9"/><apn value="internet"/>
+  <imei value="866758041234567"/><number value=""/><manufacturer value="Quectel"/>
+  <model value="EC20"/><version value="EC20CEFAGR06A05M4G"/>
+</pppoe>`
+
+func TestGetCellularInfo(t *testing.T) {
+	ctrl := huidutest.NewController(huidutest.WithSetting("PppoeInfo", pppoeAnswer))
+	defer ctrl.Close()
+	dev := connect(t, ctrl)
+
+	got, err := dev.GetCellularInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := huidu.CellularInfo{
+		HasModem:     true,
+		Enabled:      true,
+		SIMInserted:  true,
+		Status:       "connected",
+		Network:      "4G",
+		Operator:     "Turkcell",
+		Signal:       4,
+		DBM:          -71,
+		IP:           "10.64.12.9",
+		APN:          "internet",
+		IMEI:         "866758041234567",
+		Manufacturer: "Quectel",
+		ModemModel:   "EC20",
+		ModemVersion: "EC20CEFAGR06A05M4G",
+	}
+	if *got != want {
+		t.Fatalf("GetCellularInfo = %+v, beklenen %+v", *got, want)
+	}
+}
+
+func TestGetCellularInfoRejectsBadNumbers(t *testing.T) {
+	for _, answer := range []string{
+		`<pppoe valid="true"><signal value="güçlü"/></pppoe>`,
+		`<pppoe valid="true"><dbm value="-71dBm"/></pppoe>`,
+	} {
+		ctrl := huidutest.NewController(huidutest.WithSetting("PppoeInfo", answer))
+		dev := connect(t, ctrl)
+
+		_, err := dev.GetCellularInfo()
+		var pe *huidu.ProtocolError
+		if !errors.As(err, &pe) || pe.Cmd != huidu.CmdSdkCmdAnswer {
+			t.Errorf("%s: hata = %v, *ProtocolError bekleniyordu", answer, err)
+		}
+		ctrl.Close()
+	}
+}
+
+func TestSetAPN(t *testing.T) {
+	ctrl := huidutest.NewController()
+	defer ctrl.Close()
+	dev := connect(t, ctrl)
+
+	tests := []struct {
+		name string
+		info huidu.APNInfo
+		want string
+	}{
+		{"kimlik doğrulamasız", huidu.APNInfo{APN: "internet"},
+			`<apn value="internet" user="" passwd="" auth="none"/>`},
+		{"CHAP", huidu.APNInfo{APN: "corp.apn", User: "led", Password: "p&ss<1>", Auth: huidu.APNAuthCHAP},
+			`<apn value="corp.apn" user="led" passwd="p&amp;ss&lt;1&gt;" auth="chap"/>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := dev.SetAPN(&tt.info); err != nil {
+				t.Fatal(err)
+			}
+			req, ok := ctrl.LastRequest(huidu.MethodSetApn)
+			if !ok {
+				t.Fatal("SetApn isteği kaydedilmedi")
+			}
+			if req.InnerXML != tt.want {
+				t.Fatalf("InnerXML = %s, beklenen %s", req.InnerXML, tt.want)
+			}
+		})
+	}
+}
+
+// Yanıttaki kaçışlı karakterler InnerXML'de kaçışlı kalmalı, ayrıştırılan
+// değerlerde ise bir kez çözülmelidir.
+func TestResponseEscaping(t *testing.T) {
+	const wifi = `<wifi valid="true"><enable value="true"/><mode value="ap"/>` +
+		`<ap><ssid value="Cafe &amp; &lt;Bar&gt;"/><passwd value="a&lt;b&amp;c"/></ap></wifi>`
+	const note = `<note kind="a&amp;b">1 &lt; 2 &amp;&amp; 3 &gt; 2</note>`
+	ctrl := huidutest.NewController(
+		huidutest.WithSetting("WifiInfo", wifi),
+		huidutest.WithSetting("Note", note),
+	)
+	defer ctrl.Close()
+	dev := connect(t, ctrl)
+
+	info, err := dev.GetWifiInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.APInfo.SSID != "Cafe & <Bar>" || info.APInfo.Password != "a<b&c" {
+		t.Fatalf("SSID = %q, şifre = %q", info.APInfo.SSID, info.APInfo.Password)
+	}
+
+	resp, err := dev.SendRawXML(`<?xml version="1.0" encoding="utf-8"?>` +
+		`<sdk guid="` + dev.GUID() + `"><in method="GetNote"/></sdk>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.InnerXML != note {
+		t.Fatalf("InnerXML = %s, beklenen %s", resp.InnerXML, note)
+	}
+}
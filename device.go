@@ -1,10 +1,12 @@
 package huidu
 
 import (
-	"encoding/binary"
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"net"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -31,8 +33,8 @@ type Device struct {
 	// port, cihazın TCP port numarasıdır.
 	port int
 
-	// conn, aktif TCP bağlantısıdır.
-	conn net.Conn
+	// link, aktif TCP bağlantısı ve onun okuma döngüsüdür.
+	link *link
 
 	// sdkGUID, bu oturum için benzersiz kimlik.
 	// Handshake sırasında cihazdan alınır.
@@ -41,21 +43,70 @@ type Device struct {
 	// opts, cihaz yapılandırma seçenekleridir.
 	opts deviceOptions
 
+	// initialConn, NewDeviceFromConn veya Server ile verilen hazır
+	// bağlantıdır. İlk Connect'te kullanılır ve sıfırlanır.
+	initialConn net.Conn
+
+	// noRedial, hazır bağlantı kullanıldıktan sonra yeniden bağlanmanın
+	// mümkün olmadığını belirtir (host:port'a ulaşılamayan bağlantılar).
+	noRedial bool
+
 	// mu, bağlantı durumu için mutex'tir.
+	// Yalnızca alanlara erişim süresince tutulur, G/Ç sırasında tutulmaz.
 	mu sync.Mutex
 
+	// dialMu, bağlantı kurma ve yeniden bağlanma işlemlerini sıraya sokar.
+	// Aynı anda yalnızca bir handshake yürütülür.
+	dialMu sync.Mutex
+
 	// writeMu, TCP yazma işlemleri için mutex'tir.
 	// Aynı anda birden fazla goroutine yazmasını engeller.
 	writeMu sync.Mutex
 
+	// cmdSem, istek-yanıt alışverişlerini sıraya sokan semafordur (kapasite 1).
+	// Protokolde istek kimliği olmadığından aynı anda tek komut yürütülür.
+	cmdSem chan struct{}
+
+	// evMu, evQueue ve evRunning alanlarını korur. Olaylar kuyruğa alınıp
+	// kilit tutulmadan tek bir goroutine'de iletilir (bkz. emit).
+	evMu      sync.Mutex
+	evQueue   []Event
+	evRunning bool
+
+	// subMu, subs ve nextSubID alanlarını korur.
+	subMu     sync.Mutex
+	subs      map[CmdType]map[int]func([]byte)
+	nextSubID int
+
 	// connected, bağlantı durumunu gösterir.
+	// Handshake tamamlanana kadar false kalır.
 	connected bool
 
+	// closed, Close() çağrıldıktan sonra true olur ve otomatik yeniden
+	// bağlanmayı durdurur. Connect() tekrar çağrıldığında sıfırlanır.
+	closed bool
+
+	// generation, her başarılı bağlantıda bir artar. Aynı kopuşu fark eden
+	// birden fazla goroutine'in art arda yeniden bağlanmasını engeller.
+	generation uint64
+
+	// reconnecting, arka plan yeniden bağlanma goroutine'i çalışırken true'dur.
+	reconnecting bool
+
+	// lifeCtx, Close() ile iptal edilen ve arka plan işlerini sınırlayan context'tir.
+	lifeCtx    context.Context
+	lifeCancel context.CancelFunc
+
 	// stopHeartbeat, heartbeat goroutine'ini durdurmak için kullanılır.
 	stopHeartbeat chan struct{}
 
 	// info, cihaz bilgileri (handshake sonrası doldurulur).
 	info *DeviceInfo
+
+	// screenXML, son gönderilen veya okunan ekranın XML'idir (CurrentProgram
+	// için). Çağıranın elindeki Screen sonradan değişebileceğinden nesnenin
+	// kendisi değil kartın aldığı XML saklanır.
+	screenXML string
 }
 
 // NewDevice, yeni bir Device nesnesi oluşturur.
@@ -76,12 +127,79 @@ func NewDevice(host string, port int, options ...DeviceOption) *Device {
 	}
 
 	return &Device{
-		host: host,
-		port: port,
-		opts: opts,
+		host:   host,
+		port:   port,
+		opts:   opts,
+		cmdSem: make(chan struct{}, 1),
 	}
 }
 
+// NewDeviceFromConn, önceden açılmış bir bağlantı üzerinde çalışan Device
+// oluşturur. SSH tüneli, SOCKS proxy, seri-TCP köprüsü veya testlerde
+// net.Pipe gibi kütüphanenin kendisinin açamadığı bağlantılar için kullanılır.
+// Handshake için yine Connect() çağrılmalıdır.
+//
+// Host ve Port, conn.RemoteAddr() değerinden alınır. Bağlantı koptuğunda
+// yeniden bağlanma (WithAutoReconnect veya tekrar Connect) yalnızca
+// WithDialer verilmişse mümkündür; dialer bu adrese yeni bağlantı açar.
+//
+//	client, server := net.Pipe()
+//	go fakeController(server)
+//	dev := huidu.NewDeviceFromConn(client)
+//	err := dev.Connect()
+func NewDeviceFromConn(conn net.Conn, options ...DeviceOption) *Device {
+	host, port := splitAddr(conn.RemoteAddr())
+	d := NewDevice(host, port, options...)
+	d.initialConn = conn
+	d.noRedial = d.opts.dialer == nil
+	return d
+}
+
+// splitAddr, bir net.Addr'ı host ve port'a ayırır. Port içermeyen
+// adreslerde (ör. net.Pipe) adresin tamamı host olarak, port 0 döner.
+func splitAddr(addr net.Addr) (string, int) {
+	if addr == nil {
+		return "", 0
+	}
+	host, portStr, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String(), 0
+	}
+	port, _ := strconv.Atoi(portStr)
+	return host, port
+}
+
+// SplitHostPort, "host", "host:port", "[ipv6]" veya "[ipv6]:port"
+// biçimindeki adresi NewDevice'ın beklediği host ve port'a ayırır. Port
+// verilmemişse DefaultPort döner. IPv6 adresleri köşeli parantezsiz döner;
+// köşeli parantezsiz yazılmış IPv6 adresi ("::1") port içermeyen adres
+// olarak okunur.
+//
+//	host, port, err := huidu.SplitHostPort("[fd00::6:1]:5005")
+//	dev := huidu.NewDevice(host, port) // "fd00::6:1", 5005
+func SplitHostPort(addr string) (host string, port int, err error) {
+	switch {
+	case strings.HasPrefix(addr, "[") && strings.HasSuffix(addr, "]"):
+		host, port = addr[1:len(addr)-1], DefaultPort
+	case !strings.Contains(addr, ":"), !strings.HasPrefix(addr, "[") && strings.Count(addr, ":") > 1:
+		host, port = addr, DefaultPort
+	default:
+		var portStr string
+		host, portStr, err = net.SplitHostPort(addr)
+		if err != nil {
+			return "", 0, fmt.Errorf("geçersiz adres %q: %w", addr, err)
+		}
+		port, err = strconv.Atoi(portStr)
+		if err != nil || port <= 0 || port > 65535 {
+			return "", 0, fmt.Errorf("geçersiz port: %q", addr)
+		}
+	}
+	if host == "" {
+		return "", 0, fmt.Errorf("adreste host yok: %q", addr)
+	}
+	return host, port, nil
+}
+
 // Connect, cihaza TCP bağlantısı kurar ve 3 aşamalı handshake gerçekleştirir.
 //
 // Handshake aşamaları:
@@ -91,73 +209,164 @@ func NewDevice(host string, port int, options ...DeviceOption) *Device {
 //
 // Eğer bağlantı zaten kuruluysa, önce mevcut bağlantı kapatılır.
 func (d *Device) Connect() error {
-	d.mu.Lock()
-	defer d.mu.Unlock()
+	return d.ConnectContext(context.Background())
+}
+
+// ConnectContext, Connect ile aynı işi yapar ancak ctx iptal edildiğinde
+// TCP bağlantısı ve handshake yarıda kesilir. ctx'in deadline'ı WithTimeout
+// süresinden önce doluyorsa her G/Ç işleminde ctx'in deadline'ı kullanılır.
+//
+//	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+//	defer cancel()
+//	err := dev.ConnectContext(ctx)
+func (d *Device) ConnectContext(ctx context.Context) error {
+	d.dialMu.Lock()
 
 	// Mevcut bağlantıyı kapat
-	if d.conn != nil {
-		d.closeInternal()
+	d.mu.Lock()
+	d.closeInternal()
+	if d.lifeCancel != nil {
+		d.lifeCancel()
 	}
+	d.lifeCtx, d.lifeCancel = context.WithCancel(context.Background())
+	d.closed = false
+	d.mu.Unlock()
 
-	d.logf("TCP bağlantısı kuruluyor: %s:%d", d.host, d.port)
+	err := d.connectLocked(ctx)
+	d.dialMu.Unlock()
+	if err != nil {
+		return err
+	}
+	d.emit(Event{Type: EventConnected})
+	return nil
+}
 
+// connectLocked, TCP bağlantısını kurar ve handshake'i yürütür.
+// Çağıran dialMu'yu tutmalıdır; mu bu fonksiyon içinde kısa süreli alınır.
+func (d *Device) connectLocked(ctx context.Context) error {
 	// TCP bağlantısı kur
-	addr := fmt.Sprintf("%s:%d", d.host, d.port)
-	conn, err := net.DialTimeout("tcp", addr, d.opts.timeout)
+	conn, err := d.dialConn(ctx)
 	if err != nil {
 		return fmt.Errorf("TCP bağlantı hatası: %w", err)
 	}
-	d.conn = conn
-	d.connected = true
+
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		conn.Close()
+		return ErrClosed
+	}
+	l := d.newLink(conn)
+	d.link = l
+	d.mu.Unlock()
 
 	// Aşama 1: Transport Protocol Version anlaşması
 	d.logf("Aşama 1: Transport Protocol Version anlaşması")
-	if err := d.handshakeVersion(); err != nil {
-		d.closeInternal()
+	if err := d.handshakeVersion(ctx, l); err != nil {
+		d.abortHandshake(l)
 		return fmt.Errorf("versiyon anlaşma hatası: %w", err)
 	}
 
 	// Aşama 2: SDK Version anlaşması
 	d.logf("Aşama 2: SDK Version anlaşması")
-	if err := d.handshakeSdkVersion(); err != nil {
-		d.closeInternal()
+	if err := d.handshakeSdkVersion(ctx, l); err != nil {
+		d.abortHandshake(l)
 		return fmt.Errorf("SDK versiyon anlaşma hatası: %w", err)
 	}
 
 	// Aşama 3: Device Info sorgulama
 	d.logf("Aşama 3: Cihaz bilgisi sorgulanıyor")
-	if err := d.handshakeDeviceInfo(); err != nil {
+	if err := d.handshakeDeviceInfo(ctx, l); err != nil {
 		// DeviceInfo alınamazsa bağlantıyı kapatma, devam et
 		d.logf("UYARI: Cihaz bilgisi alınamadı: %v", err)
 	}
 
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed || d.link != l {
+		conn.Close()
+		return ErrClosed
+	}
+	d.connected = true
+	d.generation++
+
 	// Heartbeat goroutine'ini başlat
 	d.stopHeartbeat = make(chan struct{})
-	go d.heartbeatLoop()
+	go d.heartbeatLoop(l, d.stopHeartbeat)
 
 	d.logf("Bağlantı başarıyla kuruldu (GUID: %s)", d.sdkGUID)
 	return nil
 }
 
+// dialConn, cihaza bağlantı açar. Hazır bir bağlantı varsa onu bir kez
+// döner; yoksa WithDialer ile verilen fonksiyonu veya doğrudan TCP'yi kullanır.
+func (d *Device) dialConn(ctx context.Context) (net.Conn, error) {
+	d.mu.Lock()
+	conn := d.initialConn
+	d.initialConn = nil
+	noRedial := d.noRedial
+	d.mu.Unlock()
+
+	if conn != nil {
+		return conn, nil
+	}
+	if noRedial {
+		return nil, errNoRedial
+	}
+
+	addr := net.JoinHostPort(d.host, strconv.Itoa(d.port))
+	d.logf("TCP bağlantısı kuruluyor: %s", addr)
+
+	if d.opts.dialer != nil {
+		ctx, cancel := context.WithTimeout(ctx, d.opts.timeout)
+		defer cancel()
+		return d.opts.dialer(ctx, "tcp", addr)
+	}
+	dialer := net.Dialer{Timeout: d.opts.timeout}
+	return dialer.DialContext(ctx, "tcp", addr)
+}
+
+// errNoRedial, hazır bağlantısı kapanmış ve yeniden bağlanma yolu
+// olmayan cihazlarda döner.
+var errNoRedial = errors.New("hazır bağlantı kapandı ve yeniden kurulamaz (WithDialer ayarlanmamış)")
+
+// abortHandshake, yarıda kalan bir handshake'in bağlantısını kapatır.
+func (d *Device) abortHandshake(l *link) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.link == l {
+		d.closeInternal()
+	} else {
+		l.conn.Close()
+	}
+}
+
 // Close, cihaz bağlantısını güvenli bir şekilde kapatır.
 // Heartbeat goroutine'i durdurulur ve TCP bağlantısı kapatılır.
+// Devam eden otomatik yeniden bağlanma denemeleri de sonlandırılır.
 func (d *Device) Close() error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
+	d.closed = true
+	if d.lifeCancel != nil {
+		d.lifeCancel()
+	}
 	return d.closeInternal()
 }
 
-// closeInternal, bağlantıyı kapatır (mutex dışında çağrılır).
+// closeInternal, bağlantıyı kapatır (mu tutulurken çağrılır).
+// link alanı bağlantı kapatılmadan önce sıfırlandığından okuma döngüsünün
+// aldığı hata kopuş olarak değerlendirilmez.
 func (d *Device) closeInternal() error {
 	if d.stopHeartbeat != nil {
 		close(d.stopHeartbeat)
 		d.stopHeartbeat = nil
 	}
 	d.connected = false
-	if d.conn != nil {
-		err := d.conn.Close()
-		d.conn = nil
-		return err
+	if d.link != nil {
+		l := d.link
+		d.link = nil
+		return l.conn.Close()
 	}
 	return nil
 }
@@ -198,70 +407,68 @@ func (d *Device) CachedDeviceInfo() *DeviceInfo {
 // ─── Handshake ──────────────────────────────────────────────────────────────────
 
 // handshakeVersion, transport protocol version anlaşmasını gerçekleştirir.
-func (d *Device) handshakeVersion() error {
-	// Version paketi gönder
-	pkt := buildVersionPacket()
-	if err := d.sendRaw(pkt); err != nil {
-		return fmt.Errorf("versiyon paketi gönderilemedi: %w", err)
+func (d *Device) handshakeVersion(ctx context.Context, l *link) error {
+	release, err := d.acquire(ctx)
+	if err != nil {
+		return err
 	}
+	defer release()
 
-	// Yanıt oku
-	data, cmdType, err := d.readPacket()
+	// Version paketi gönder ve yanıtı bekle
+	pkt := buildVersionPacket()
+	in, err := d.roundTrip(ctx, l, "", [][]byte{pkt}, CmdServiceAnswer, CmdErrorAnswer)
 	if err != nil {
-		return fmt.Errorf("versiyon yanıtı okunamadı: %w", err)
+		return fmt.Errorf("versiyon yanıtı alınamadı: %w", err)
 	}
 
-	switch cmdType {
+	switch in.cmd {
 	case CmdServiceAnswer:
-		ver, ok := parseVersionResponse(data)
+		ver, ok := parseVersionResponse(in.data)
 		if !ok {
-			return fmt.Errorf("versiyon yanıtı çözümlenemedi")
+			return protocolErrorf(CmdServiceAnswer, "versiyon yanıtı çözümlenemedi")
 		}
 		d.logf("Transport Protocol Version: 0x%08x", ver)
 		return nil
 
-	case CmdErrorAnswer:
-		errCode, ok := parseErrorCode(data)
-		if ok {
-			return fmt.Errorf("cihaz hata döndü: %s", errCode)
-		}
-		return fmt.Errorf("cihaz hata döndü (bilinmeyen format)")
-
 	default:
-		return fmt.Errorf("beklenmeyen yanıt tipi: %s", cmdType)
+		return fmt.Errorf("cihaz hata döndü: %w", errorAnswer(in.data))
 	}
 }
 
 // handshakeSdkVersion, SDK versiyon anlaşmasını gerçekleştirir.
 // Bu aşamada ##GUID placeholder'ı gerçek GUID ile değiştirilir.
-func (d *Device) handshakeSdkVersion() error {
+func (d *Device) handshakeSdkVersion(ctx context.Context, l *link) error {
 	xmlData := buildVersionXML()
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+	resp, err := d.exchangeSdkCmdOn(ctx, l, []byte(xmlData))
 	if err != nil {
 		return err
 	}
 
 	// GUID'i kaydet
-	d.sdkGUID = resp.GUID
-	if d.sdkGUID == "" || d.sdkGUID == "##GUID" {
+	guid := resp.GUID
+	if guid == "" || guid == "##GUID" {
 		// Yanıttan çıkarılamadıysa yeni GUID oluştur
-		d.sdkGUID = uuid.New().String()
+		guid = uuid.New().String()
 	}
 
-	d.logf("SDK GUID alındı: %s", d.sdkGUID)
+	d.mu.Lock()
+	d.sdkGUID = guid
+	d.mu.Unlock()
+
+	d.logf("SDK GUID alındı: %s", guid)
 	return nil
 }
 
 // handshakeDeviceInfo, cihaz bilgilerini sorgular ve kaydeder.
-func (d *Device) handshakeDeviceInfo() error {
-	xmlData := buildSdkXML(d.sdkGUID, MethodGetDeviceInfo, "")
-	resp, err := d.sendSdkCmdAndReceive([]byte(xmlData))
+func (d *Device) handshakeDeviceInfo(ctx context.Context, l *link) error {
+	xmlData := buildSdkXML(d.GUID(), MethodGetDeviceInfo, "")
+	resp, err := d.exchangeSdkCmdOn(ctx, l, []byte(xmlData))
 	if err != nil {
 		return err
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("GetDeviceInfo başarısız: %s", resp.Result)
+		return newResultError("GetDeviceInfo", resp)
 	}
 
 	info, err := parseDeviceInfoXML(resp.InnerXML)
@@ -269,184 +476,162 @@ func (d *Device) handshakeDeviceInfo() error {
 		return fmt.Errorf("cihaz bilgisi ayrıştırılamadı: %w", err)
 	}
 
+	d.mu.Lock()
 	d.info = info
+	d.mu.Unlock()
 	d.logf("Cihaz: %s (ID: %s, Ekran: %dx%d)", info.Model, info.DeviceID, info.ScreenWidth, info.ScreenHeight)
 	return nil
 }
 
 // ─── Veri Gönderme/Alma ─────────────────────────────────────────────────────────
 
-// sendRaw, ham byte verisini TCP bağlantısına yazar.
-func (d *Device) sendRaw(data []byte) error {
-	d.writeMu.Lock()
-	defer d.writeMu.Unlock()
+// sendSdkCmdAndReceive, SDK komutu gönderir ve yanıtı bekler.
+// Bu, en çok kullanılan gönder-al döngüsüdür.
+//
+// Kart komutu meşgul olduğu için reddederse WithRetryPolicy'ye göre tekrar
+// denenir (bkz. withRetry).
+func (d *Device) sendSdkCmdAndReceive(ctx context.Context, xmlData []byte) (*SdkResponse, error) {
+	method := SdkMethod(extractMethod(string(xmlData)))
+	start := time.Now()
+	resp, err := d.withRetry(ctx, string(method), isIdempotent(method), func(attempt int) (*SdkResponse, error) {
+		if attempt > 1 {
+			// Aradaki yeniden bağlanma GUID'i değiştirmiş olabilir
+			xmlStr := string(xmlData)
+			xmlData = []byte(replaceGUID(xmlStr, extractGUID(xmlStr), d.GUID()))
+		}
+		return d.sendSdkCmdOnce(ctx, method, xmlData)
+	})
 
-	if d.conn == nil {
-		return fmt.Errorf("bağlantı kapalı")
+	if d.opts.onCommand != nil {
+		cerr := err
+		if cerr == nil && resp != nil && !resp.IsSuccess() {
+			cerr = newResultError(string(method), resp)
+		}
+		d.opts.onCommand(CommandStats{Op: string(method), Duration: time.Since(start), Err: cerr})
 	}
-
-	d.conn.SetWriteDeadline(time.Now().Add(d.opts.timeout))
-	_, err := d.conn.Write(data)
-	return err
+	return resp, err
 }
 
-// sendSdkCmd, XML verisini SDK komut paketlerine dönüştürüp gönderir.
-// Büyük XML'ler otomatik olarak parçalara bölünür.
-func (d *Device) sendSdkCmd(xmlData []byte) error {
-	packets := buildSdkCmdPackets(xmlData)
-	for _, pkt := range packets {
-		if err := d.sendRaw(pkt); err != nil {
-			return err
-		}
+// sendSdkCmdOnce, SDK komutunu bir kez gönderir.
+//
+// WithAutoReconnect açıksa ve bağlantı komut sırasında koparsa önce yeniden
+// bağlanılır; komut salt okunur (idempotent) ise yeni GUID ile bir kez daha
+// gönderilir. Diğer komutlar cihazda uygulanmış olabileceğinden tekrarlanmaz
+// ve ErrConnectionLost ile döner.
+func (d *Device) sendSdkCmdOnce(ctx context.Context, method SdkMethod, xmlData []byte) (*SdkResponse, error) {
+	gen := d.currentGeneration()
+	resp, err := d.exchangeSdkCmd(ctx, xmlData)
+	if err == nil || !d.opts.autoReconnect || !errors.Is(err, ErrConnectionLost) {
+		return resp, err
 	}
-	return nil
-}
 
-// sendSdkCmdAndReceive, SDK komutu gönderir ve yanıtı bekler.
-// Bu, en çok kullanılan gönder-al döngüsüdür.
-func (d *Device) sendSdkCmdAndReceive(xmlData []byte) (*SdkResponse, error) {
-	if err := d.sendSdkCmd(xmlData); err != nil {
-		return nil, fmt.Errorf("SDK komutu gönderilemedi: %w", err)
+	d.logf("%s sırasında bağlantı koptu, yeniden bağlanılıyor: %v", method, err)
+	if rerr := d.reconnect(ctx, gen, d.opts.reconnectAttempts); rerr != nil {
+		return nil, fmt.Errorf("%w (yeniden bağlanılamadı: %v)", err, rerr)
+	}
+	if !isIdempotent(method) {
+		return nil, err
 	}
 
-	return d.readSdkResponse()
+	d.logf("%s yeniden deneniyor", method)
+	xmlStr := string(xmlData)
+	xmlStr = replaceGUID(xmlStr, extractGUID(xmlStr), d.GUID())
+	return d.exchangeSdkCmd(ctx, []byte(xmlStr))
 }
 
-// readPacket, TCP'den bir tam paket okur.
-// Huidu protokolünün sticky packet handling mantığını uygular:
-//  1. İlk 2 byte okunur → paket uzunluğu
-//  2. Kalan byte'lar okunur
-//  3. Tam paket döner
-//
-// Bu fonksiyon, birden fazla paketin tek bir TCP segment'inde
-// gelmesi durumunu doğru şekilde ele alır.
-func (d *Device) readPacket() ([]byte, CmdType, error) {
-	if d.conn == nil {
-		return nil, 0, fmt.Errorf("bağlantı kapalı")
+// exchangeSdkCmd, SDK komutunu aktif bağlantı üzerinden gönderip yanıtını bekler.
+// Yeniden bağlanma veya tekrar deneme yapmaz.
+func (d *Device) exchangeSdkCmd(ctx context.Context, xmlData []byte) (*SdkResponse, error) {
+	l := d.activeLink()
+	if l == nil {
+		return nil, ErrConnectionLost
 	}
+	return d.exchangeSdkCmdOn(ctx, l, xmlData)
+}
 
-	d.conn.SetReadDeadline(time.Now().Add(d.opts.timeout))
-
-	// İlk 2 byte: paket uzunluğu
-	lenBuf := make([]byte, 2)
-	if _, err := io.ReadFull(d.conn, lenBuf); err != nil {
-		return nil, 0, fmt.Errorf("paket uzunluğu okunamadı: %w", err)
+// exchangeSdkCmdOn, SDK komutunu l üzerinden gönderip yanıtını bekler.
+// Handshake henüz aktif olmayan bağlantı için bunu doğrudan kullanır.
+//
+// Büyük XML'ler otomatik olarak parçalara bölünür; parçalı yanıtlar okuma
+// döngüsünde birleştirilir.
+func (d *Device) exchangeSdkCmdOn(ctx context.Context, l *link, xmlData []byte) (*SdkResponse, error) {
+	release, err := d.acquire(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
 
-	pktLen := int(binary.LittleEndian.Uint16(lenBuf))
-	if pktLen < tcpHeaderLength {
-		return nil, 0, fmt.Errorf("geçersiz paket uzunluğu: %d", pktLen)
+	method := extractMethod(string(xmlData))
+	packets := buildSdkCmdPackets(xmlData)
+	in, err := d.roundTrip(ctx, l, method, packets, CmdSdkCmdAnswer, CmdErrorAnswer)
+	if err != nil {
+		return nil, fmt.Errorf("SDK komutu başarısız: %w", err)
 	}
 
-	// Kalan veriyi oku
-	pkt := make([]byte, pktLen)
-	copy(pkt[0:2], lenBuf)
-	if _, err := io.ReadFull(d.conn, pkt[2:]); err != nil {
-		return nil, 0, fmt.Errorf("paket verisi okunamadı: %w", err)
+	if in.cmd == CmdErrorAnswer {
+		return nil, fmt.Errorf("SDK hata yanıtı: %w", errorAnswer(in.data))
 	}
-
-	cmdType := CmdType(binary.LittleEndian.Uint16(pkt[2:4]))
-	return pkt, cmdType, nil
+	return in.resp, nil
 }
 
-// readSdkResponse, SDK komut yanıtını okur.
-// Fragment reassembly: Büyük XML yanıtları birden fazla pakette gelebilir.
-// Bu fonksiyon tüm parçaları birleştirir ve tam XML'i döner.
-func (d *Device) readSdkResponse() (*SdkResponse, error) {
-	var xmlBuf []byte
-	var totalExpected uint32
-
-	for {
-		data, cmdType, err := d.readPacket()
-		if err != nil {
-			return nil, err
-		}
+// ─── Dahili Yardımcılar ─────────────────────────────────────────────────────────
 
-		switch cmdType {
-		case CmdSdkCmdAnswer:
-			totalLen, offset, ok := parseSdkCmdHeader(data)
-			if !ok {
-				return nil, fmt.Errorf("SDK yanıt header'ı çözümlenemedi")
-			}
-
-			// İlk parçada buffer oluştur
-			if xmlBuf == nil {
-				xmlBuf = make([]byte, totalLen)
-				totalExpected = totalLen
-			}
-
-			// XML verisini kopyala
-			xmlChunk := data[sdkCmdHeaderLength:]
-			copy(xmlBuf[offset:], xmlChunk)
-
-			// Tüm parçalar alındı mı kontrol et
-			if offset+uint32(len(xmlChunk)) >= totalExpected {
-				// XML'i temizle ve ayrıştır
-				xmlStr := cleanXML(xmlBuf)
-				return parseSdkResponse(xmlStr)
-			}
-
-		case CmdErrorAnswer:
-			errCode, ok := parseErrorCode(data)
-			if ok {
-				return nil, fmt.Errorf("SDK hata yanıtı: %s", errCode)
-			}
-			return nil, fmt.Errorf("SDK hata yanıtı (bilinmeyen format)")
-
-		case CmdHeartbeatAnswer:
-			// Heartbeat yanıtı geldi, asıl yanıtı beklemeye devam et
-			d.logf("Heartbeat yanıtı alındı (SDK yanıt bekleniyor)")
-			continue
-
-		default:
-			return nil, fmt.Errorf("beklenmeyen yanıt tipi: %s (0x%04x)", cmdType, uint16(cmdType))
-		}
+// logf, yapılandırılmış logger varsa mesaj yazar.
+func (d *Device) logf(format string, v ...interface{}) {
+	if d.opts.logger != nil {
+		d.opts.logger.Printf("[huidu] "+format, v...)
 	}
 }
 
-// ─── Heartbeat ──────────────────────────────────────────────────────────────────
-
-// heartbeatLoop, periyodik heartbeat paketleri gönderen arka plan goroutine'idir.
-// Connect() tarafından başlatılır, Close() tarafından durdurulur.
-func (d *Device) heartbeatLoop() {
-	ticker := time.NewTicker(d.opts.heartbeatInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-d.stopHeartbeat:
-			return
-		case <-ticker.C:
-			d.mu.Lock()
-			if !d.connected {
-				d.mu.Unlock()
-				return
-			}
-			d.mu.Unlock()
-
-			pkt := buildHeartbeat()
-			if err := d.sendRaw(pkt); err != nil {
-				d.logf("Heartbeat gönderilemedi: %v", err)
-				return
-			}
-			d.logf("Heartbeat gönderildi")
-		}
+// observeTransfer, WithCommandObserver ayarlıysa bir dosya aktarımını bildirir.
+// Bytes yalnızca başarılı aktarımlarda doldurulur.
+func (d *Device) observeTransfer(op, fileName string, bytes int64, start time.Time, err error) {
+	if d.opts.onCommand == nil {
+		return
 	}
+	if err != nil {
+		bytes = 0
+	}
+	d.opts.onCommand(CommandStats{Op: op, File: fileName, Bytes: bytes, Duration: time.Since(start), Err: err})
 }
 
-// ─── Dahili Yardımcılar ─────────────────────────────────────────────────────────
+// aLongTimeAgo, bekleyen bir G/Ç işlemini hemen sonlandırmak için
+// deadline olarak kullanılan geçmiş bir zamandır.
+var aLongTimeAgo = time.Unix(1, 0)
 
-// logf, yapılandırılmış logger varsa mesaj yazar.
-func (d *Device) logf(format string, v ...interface{}) {
-	if d.opts.logger != nil {
-		d.opts.logger.Printf("[huidu] "+format, v...)
+// ioDeadline, bir G/Ç işlemi için deadline hesaplar: WithTimeout süresi
+// ile ctx'in deadline'ından hangisi önce doluyorsa o kullanılır.
+func (d *Device) ioDeadline(ctx context.Context) time.Time {
+	deadline := time.Now().Add(d.opts.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
 	}
+	return deadline
 }
 
 // ensureConnected, bağlantının aktif olduğunu kontrol eder.
+//
+// WithAutoReconnect açıkken daha önce bağlanmış ve Close() ile kapatılmamış
+// bir cihaz bağlı kabul edilir; komut, gönderim sırasında yeniden bağlanmayı
+// bekler.
 func (d *Device) ensureConnected() error {
-	if !d.connected || d.conn == nil {
-		return fmt.Errorf("cihaz bağlı değil, önce Connect() çağırın")
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.connected && d.link != nil {
+		return nil
 	}
-	return nil
+	if d.opts.autoReconnect && !d.closed && d.generation > 0 {
+		return nil
+	}
+	return fmt.Errorf("cihaz bağlı değil, önce Connect() çağırın")
+}
+
+// activeLink, handshake'i tamamlanmış aktif bağlantıyı döner (yoksa nil).
+func (d *Device) activeLink() *link {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.connected {
+		return nil
+	}
+	return d.link
 }
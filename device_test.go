@@ -0,0 +1,136 @@
+package huidu_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+	"github.com/alparslanahmed/huidu-led/huidutest"
+)
+
+// remoteConn, RemoteAddr'ı verilen adres olan bir bağlantıdır.
+type remoteConn struct {
+	net.Conn
+	remote net.Addr
+}
+
+func (c remoteConn) RemoteAddr() net.Addr { return c.remote }
+
+func TestNewDeviceFromConnAddress(t *testing.T) {
+	tests := []struct {
+		name   string
+		remote net.Addr
+		host   string
+		port   int
+	}{
+		{"IPv4", &net.TCPAddr{IP: net.ParseIP("192.168.6.1"), Port: 10001}, "192.168.6.1", 10001},
+		{"IPv6", &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 5005}, "2001:db8::1", 5005},
+		{"IPv6 loopback", &net.TCPAddr{IP: net.IPv6loopback, Port: 10001}, "::1", 10001},
+		{"IPv6 bölge", &net.TCPAddr{IP: net.ParseIP("fe80::1"), Port: 10001, Zone: "eth0"}, "fe80::1%eth0", 10001},
+		{"portsuz (net.Pipe)", pipeAddr{}, "pipe", 0},
+		{"adres yok", nil, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := net.Pipe()
+			defer client.Close()
+			defer server.Close()
+
+			dev := huidu.NewDeviceFromConn(remoteConn{Conn: client, remote: tt.remote})
+			if dev.Host() != tt.host || dev.Port() != tt.port {
+				t.Fatalf("Host/Port = %q/%d, beklenen %q/%d", dev.Host(), dev.Port(), tt.host, tt.port)
+			}
+		})
+	}
+}
+
+func TestSplitHostPort(t *testing.T) {
+	tests := []struct {
+		addr string
+		host string
+		port int
+		join string // JoinHostPort(host, port) sonucu
+	}{
+		{"192.168.6.1", "192.168.6.1", huidu.DefaultPort, "192.168.6.1:10001"},
+		{"192.168.6.1:5005", "192.168.6.1", 5005, "192.168.6.1:5005"},
+		{"pano.local", "pano.local", huidu.DefaultPort, "pano.local:10001"},
+		{"[::1]", "::1", huidu.DefaultPort, "[::1]:10001"},
+		{"[::1]:5005", "::1", 5005, "[::1]:5005"},
+		{"::1", "::1", huidu.DefaultPort, "[::1]:10001"},
+		{"fd00::6:1", "fd00::6:1", huidu.DefaultPort, "[fd00::6:1]:10001"},
+		{"[fe80::1%eth0]:5005", "fe80::1%eth0", 5005, "[fe80::1%eth0]:5005"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			host, port, err := huidu.SplitHostPort(tt.addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if host != tt.host || port != tt.port {
+				t.Fatalf("SplitHostPort = %q/%d, beklenen %q/%d", host, port, tt.host, tt.port)
+			}
+			if got := net.JoinHostPort(host, strconv.Itoa(port)); got != tt.join {
+				t.Fatalf("JoinHostPort = %q, beklenen %q", got, tt.join)
+			}
+		})
+	}
+
+	for _, bad := range []string{"", "10.0.0.1:bad", "10.0.0.1:0", "10.0.0.1:70000", ":5005", "[]", "[::1]x:5005"} {
+		if host, port, err := huidu.SplitHostPort(bad); err == nil {
+			t.Errorf("%q kabul edildi: %q/%d", bad, host, port)
+		}
+	}
+}
+
+// pipeAddr, net.Pipe'ın portsuz adresiyle aynı biçimdedir.
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string { return "pipe" }
+func (pipeAddr) String() string  { return "pipe" }
+
+func TestConnectIPv6(t *testing.T) {
+	ln, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback kullanılamıyor: %v", err)
+	}
+	defer ln.Close()
+
+	ctrl := huidutest.NewController()
+	defer ctrl.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go ctrl.Serve(conn)
+		}
+	}()
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	dev := huidu.NewDevice("::1", port)
+	if err := dev.Connect(); err != nil {
+		t.Fatalf("[::1]:%d adresine bağlanılamadı: %v", port, err)
+	}
+	defer dev.Close()
+	if _, err := dev.GetDeviceInfo(); err != nil {
+		t.Fatal(err)
+	}
+
+	// WithDialer'a köşeli parantezli adres verilir
+	var dialed string
+	dev2 := huidu.NewDevice("::1", port, huidu.WithDialer(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = addr
+		var d net.Dialer
+		return d.DialContext(ctx, network, addr)
+	}))
+	if err := dev2.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer dev2.Close()
+	if want := net.JoinHostPort("::1", strconv.Itoa(port)); dialed != want {
+		t.Fatalf("dialer adresi = %q, beklenen %q", dialed, want)
+	}
+}
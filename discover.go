@@ -0,0 +1,223 @@
+package huidu
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ─── UDP Cihaz Keşfi ────────────────────────────────────────────────────────────
+//
+// Discover, seçilen her IPv4 arayüzünden UDP port 10001'e CmdSearchDeviceAsk
+// broadcast'i gönderir ve gelen CmdSearchDeviceAnswer yanıtlarını toplar.
+// Cihazın IP'si bilinmese bile (ör. fabrika ayarı 192.168.6.1 farklı bir alt
+// ağda olsa bile) kart aynı fiziksel ağdaysa yanıt verir.
+
+// Discover, yerel ağdaki Huidu cihazlarını UDP broadcast ile arar.
+// Bulunan her cihaz dönen kanala bir kez gönderilir. Tarama ctx sona erene
+// kadar opts.Interval aralığıyla tekrarlanır; ctx bitince kanal kapatılır.
+//
+// Hiçbir arayüzde soket açılamazsa hata döner.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//
+//	found, err := huidu.Discover(ctx, huidu.DiscoverOptions{})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for dev := range found {
+//	    fmt.Printf("%s @ %s (%s)\n", dev.DeviceID, dev.IP, dev.Model)
+//	}
+func Discover(ctx context.Context, opts DiscoverOptions) (<-chan DiscoveredDevice, error) {
+	if opts.Port == 0 {
+		opts.Port = DefaultPort
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultDiscoverInterval
+	}
+
+	targets, err := discoverTargets(opts.Interfaces)
+	if err != nil {
+		return nil, err
+	}
+
+	// Her arayüz için ayrı soket aç; yanıtın hangi arayüzden geldiği bilinsin
+	var sockets []*discoverSocket
+	for _, t := range targets {
+		conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: t.local})
+		if err != nil {
+			continue
+		}
+		sockets = append(sockets, &discoverSocket{conn: conn, target: t})
+	}
+	if len(sockets) == 0 {
+		return nil, fmt.Errorf("keşif için UDP soketi açılamadı")
+	}
+
+	out := make(chan DiscoveredDevice)
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		seen = make(map[string]bool)
+	)
+
+	for _, s := range sockets {
+		wg.Add(2)
+		go func(s *discoverSocket) {
+			defer wg.Done()
+			s.scanLoop(ctx, opts.Port, opts.Interval)
+		}(s)
+		go func(s *discoverSocket) {
+			defer wg.Done()
+			s.readLoop(func(dev DiscoveredDevice) bool {
+				key := dev.DeviceID + "@" + dev.IP
+				mu.Lock()
+				dup := seen[key]
+				seen[key] = true
+				mu.Unlock()
+				if dup {
+					return true
+				}
+				select {
+				case out <- dev:
+					return true
+				case <-ctx.Done():
+					return false
+				}
+			})
+		}(s)
+	}
+
+	context.AfterFunc(ctx, func() {
+		for _, s := range sockets {
+			s.conn.Close()
+		}
+	})
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out, nil
+}
+
+// discoverTarget, tarama yapılacak bir arayüzün yerel ve broadcast adresleridir.
+type discoverTarget struct {
+	iface     string
+	local     net.IP
+	broadcast net.IP
+}
+
+// discoverTargets, verilen adlardaki (boşsa tüm uygun) arayüzler için
+// IPv4 tarama hedeflerini döner.
+func discoverTargets(names []string) ([]discoverTarget, error) {
+	var ifaces []net.Interface
+	if len(names) == 0 {
+		all, err := net.Interfaces()
+		if err != nil {
+			return nil, fmt.Errorf("ağ arayüzleri listelenemedi: %w", err)
+		}
+		for _, ifi := range all {
+			if ifi.Flags&net.FlagUp != 0 && ifi.Flags&net.FlagBroadcast != 0 && ifi.Flags&net.FlagLoopback == 0 {
+				ifaces = append(ifaces, ifi)
+			}
+		}
+	} else {
+		for _, name := range names {
+			ifi, err := net.InterfaceByName(name)
+			if err != nil {
+				return nil, fmt.Errorf("ağ arayüzü bulunamadı: %s: %w", name, err)
+			}
+			ifaces = append(ifaces, *ifi)
+		}
+	}
+
+	var targets []discoverTarget
+	for _, ifi := range ifaces {
+		addrs, err := ifi.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range addrs {
+			ipnet, ok := a.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			ip4 := ipnet.IP.To4()
+			if ip4 == nil || len(ipnet.Mask) != net.IPv4len {
+				continue
+			}
+			bcast := make(net.IP, net.IPv4len)
+			for i := range ip4 {
+				bcast[i] = ip4[i] | ^ipnet.Mask[i]
+			}
+			targets = append(targets, discoverTarget{iface: ifi.Name, local: ip4, broadcast: bcast})
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("keşif için uygun IPv4 arayüzü bulunamadı")
+	}
+	return targets, nil
+}
+
+// discoverSocket, tek bir arayüze bağlı keşif soketidir.
+type discoverSocket struct {
+	conn   *net.UDPConn
+	target discoverTarget
+}
+
+// scanLoop, ctx bitene kadar tarama paketini periyodik olarak gönderir.
+// Paket hem alt ağ broadcast adresine hem de 255.255.255.255'e gönderilir;
+// böylece farklı alt ağda yapılandırılmış kartlar da paketi alır.
+func (s *discoverSocket) scanLoop(ctx context.Context, port int, interval time.Duration) {
+	pkt := buildUDPScanPacket()
+	dests := []*net.UDPAddr{
+		{IP: s.target.broadcast, Port: port},
+		{IP: net.IPv4bcast, Port: port},
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, dst := range dests {
+			s.conn.WriteToUDP(pkt, dst)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// readLoop, soket kapanana kadar gelen yanıtları ayrıştırıp emit'e iletir.
+// emit false dönerse döngü sona erer.
+func (s *discoverSocket) readLoop(emit func(DiscoveredDevice) bool) {
+	buf := make([]byte, 2048)
+	for {
+		n, from, err := s.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		dev, ok := parseSearchDeviceAnswer(buf[:n])
+		if !ok {
+			// Kendi broadcast'imiz veya ilgisiz paket
+			continue
+		}
+		dev.IP = from.IP.String()
+		dev.Interface = s.target.iface
+		if !emit(dev) {
+			return
+		}
+	}
+}
+
+// Address, cihaza bağlanmak için "ip:port" biçiminde adres döner.
+func (dev DiscoveredDevice) Address() string {
+	return net.JoinHostPort(dev.IP, strconv.Itoa(dev.Port))
+}
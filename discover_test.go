@@ -0,0 +1,96 @@
+package huidu_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+)
+
+// loopbackInterface, IPv4 adresi olan loopback arayüzünün adını döner.
+func loopbackInterface(t *testing.T) string {
+	t.Helper()
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Skip(err)
+	}
+	for _, ifi := range ifaces {
+		if ifi.Flags&net.FlagLoopback == 0 || ifi.Flags&net.FlagUp == 0 {
+			continue
+		}
+		addrs, _ := ifi.Addrs()
+		for _, a := range addrs {
+			if ipnet, ok := a.(*net.IPNet); ok && ipnet.IP.To4() != nil {
+				return ifi.Name
+			}
+		}
+	}
+	t.Skip("IPv4 loopback arayüzü yok")
+	return ""
+}
+
+// fakeResponder, tarama paketine her seferinde ids için birer yanıt veren
+// bir UDP kartı başlatır ve portunu döner.
+func fakeResponder(t *testing.T, ids ...string) int {
+	t.Helper()
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, from, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			if n < 4 || huidu.CmdType(uint16(buf[2])|uint16(buf[3])<<8) != huidu.CmdSearchDeviceAsk {
+				continue
+			}
+			for _, id := range ids {
+				conn.WriteToUDP(searchAnswer(huidu.CmdSearchDeviceAnswer, id, ""), from)
+			}
+		}
+	}()
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func TestDiscoverLoopback(t *testing.T) {
+	iface := loopbackInterface(t)
+	port := fakeResponder(t, "C16-A", "C16-B", "C16-A")
+
+	const wait = 300 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), wait)
+	defer cancel()
+	start := time.Now()
+	found, err := huidu.Discover(ctx, huidu.DiscoverOptions{
+		Interfaces: []string{iface},
+		Port:       port,
+		Interval:   50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]int{}
+	for dev := range found {
+		got[dev.DeviceID]++
+		if dev.Interface != iface || net.ParseIP(dev.IP) == nil || dev.Port != huidu.DefaultPort {
+			t.Errorf("cihaz = %+v", dev)
+		}
+	}
+	elapsed := time.Since(start)
+
+	if len(got) == 0 {
+		t.Skip("loopback broadcast bu ortamda iletilmiyor")
+	}
+	if got["C16-A"] != 1 || got["C16-B"] != 1 || len(got) != 2 {
+		t.Fatalf("bulunan cihazlar = %v, her kimlik bir kez bekleniyordu", got)
+	}
+	if elapsed < wait || elapsed > wait+time.Second {
+		t.Fatalf("kanal %s sonra kapandı, beklenen ~%s", elapsed, wait)
+	}
+}
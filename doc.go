@@ -41,18 +41,32 @@
 //
 // # Supported Features
 //
+//   - LAN device discovery via UDP broadcast (Discover)
+//   - Reverse-connection Server for cards that dial in (SetServerInfo)
+//   - Fleet management with bounded-concurrency fan-out and per-device reports
 //   - Device info queries (CPU, model, screen size, firmware version)
 //   - Text, image, video, and clock programs with 30 transition effects
+//   - Program definitions saved and loaded as XML (WriteXML, ParseScreenXML), JSON or YAML
 //   - Brightness management (manual, scheduled, sensor-based)
 //   - Screen on/off and scheduled switch control
-//   - Ethernet and WiFi network configuration
+//   - Ethernet, WiFi and cellular (3G/4G APN) network configuration
 //   - Time synchronization
+//   - Multi-screen synchronisation and GPS position reporting (SubscribeGPS)
 //   - File upload (image, video, font, firmware) with resume support
-//   - File listing and deletion
+//   - File listing, download and deletion
 //   - Heartbeat-based connection keep-alive
+//   - context.Context variants of every command (ConnectContext, SendScreenContext, ...)
+//   - Per-command latency and transfer observation (WithCommandObserver)
+//   - Prometheus metrics exporter (package huiduprom)
+//   - In-process fake controller for tests (package huidutest)
 //
 // # Thread Safety
 //
-// The Device struct is thread-safe. All public methods are protected by mutex.
-// A single Device instance can be safely used from multiple goroutines.
+// The Device struct is thread-safe. A single Device instance can be safely
+// used from multiple goroutines. Each connection has one background reader
+// goroutine that routes incoming packets: command responses go to the waiting
+// caller, and unsolicited packets go to handlers registered with
+// Device.Subscribe. The protocol has no request IDs, so commands are
+// serialized and run one at a time. A response that arrives after its command
+// timed out (ErrTimeout) is discarded.
 package huidu
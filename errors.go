@@ -0,0 +1,187 @@
+package huidu
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ─── Tipli Hatalar ──────────────────────────────────────────────────────────────
+//
+// Cihazdan dönen hatalar üç biçimde gelir:
+//
+//   - SDK yanıtında kSuccess dışındaki result değeri → *ResultError
+//   - CmdErrorAnswer veya dosya yanıtlarındaki 2 byte'lık hata kodu → ErrorCode
+//   - Çözümlenemeyen veya beklenmeyen paketler → *ProtocolError
+//
+// ResultError, result değeri bilinen bir koda karşılık geliyorsa ErrorCode'u
+// sarar; böylece her üç yolda da errors.Is kullanılabilir:
+//
+//	err := device.SendScreen(screen)
+//	if errors.Is(err, huidu.ErrDeviceOccupied) {
+//	    // Başka bir istemci kartı kullanıyor, sonra tekrar dene
+//	}
+//
+//	var re *huidu.ResultError
+//	if errors.As(err, &re) {
+//	    log.Printf("%s → %s", re.Method, re.Result)
+//	}
+
+// ResultError, SDK komutunun kSuccess dışında bir sonuçla yanıtlanmasıdır.
+type ResultError struct {
+	// Op, hatayı döndüren kütüphane fonksiyonudur (ör. "SendScreen").
+	Op string
+
+	// Method, cihaza gönderilen SDK metodudur (ör. "AddProgram").
+	Method string
+
+	// Result, cihazın döndürdüğü ham sonuç değeridir (ör. "kParseXmlFailed").
+	Result string
+
+	// Code, Result'a karşılık gelen hata kodudur. Result bilinen bir
+	// koda eşlenemezse ErrSuccess olur ve Unwrap nil döner.
+	Code ErrorCode
+}
+
+// newResultError, başarısız SDK yanıtından ResultError oluşturur.
+func newResultError(op string, resp *SdkResponse) *ResultError {
+	code, _ := ResultCode(resp.Result)
+	return &ResultError{
+		Op:     op,
+		Method: resp.Method,
+		Result: resp.Result,
+		Code:   code,
+	}
+}
+
+// Error, "<Op> başarısız: <Result>" biçiminde hata mesajı döner.
+func (e *ResultError) Error() string {
+	return fmt.Sprintf("%s başarısız: %s", e.Op, e.Result)
+}
+
+// Unwrap, eşlenmiş ErrorCode'u döner (eşlenemediyse nil).
+func (e *ResultError) Unwrap() error {
+	if e.Code == ErrSuccess {
+		return nil
+	}
+	return e.Code
+}
+
+// ProtocolError, cihazdan gelen bir paketin çözümlenemediği veya
+// beklenmeyen tipte olduğu durumları temsil eder.
+type ProtocolError struct {
+	// Cmd, ilgili paketin komut tipidir (bilinmiyorsa 0).
+	Cmd CmdType
+
+	// Msg, hatanın açıklamasıdır.
+	Msg string
+
+	// Err, altta yatan hatadır (yoksa nil).
+	Err error
+}
+
+// Error, hata mesajını döner.
+func (e *ProtocolError) Error() string {
+	if e.Err != nil {
+		return e.Msg + ": " + e.Err.Error()
+	}
+	return e.Msg
+}
+
+// Unwrap, altta yatan hatayı döner.
+func (e *ProtocolError) Unwrap() error {
+	return e.Err
+}
+
+// protocolErrorf, biçimlendirilmiş mesajla ProtocolError oluşturur.
+func protocolErrorf(cmd CmdType, format string, v ...interface{}) *ProtocolError {
+	return &ProtocolError{Cmd: cmd, Msg: fmt.Sprintf(format, v...)}
+}
+
+// resultNames, SDK result değerlerinin hata kodlarına eşlemesidir.
+// Huidu SDK'nın kullandığı adlar ile sabit adlarından türetilen
+// ("k" + ad) kısaltmalar birlikte tanınır.
+var resultNames = map[string]ErrorCode{
+	"kSuccess":              ErrSuccess,
+	"kWriteFinish":          ErrWriteFinish,
+	"kProcessError":         ErrProcessError,
+	"kVersionTooLow":        ErrVersionTooLow,
+	"kDeviceOccupied":       ErrDeviceOccupied,
+	"kFileOccupied":         ErrFileOccupied,
+	"kReadFileExcessive":    ErrReadFileExcessive,
+	"kInvalidPacketLen":     ErrInvalidPacketLen,
+	"kInvalidParam":         ErrInvalidParam,
+	"kNotSpaceToSave":       ErrNotSpaceToSave,
+	"kCreateFileFailed":     ErrCreateFileFailed,
+	"kWriteFileFailed":      ErrWriteFileFailed,
+	"kReadFileFailed":       ErrReadFileFailed,
+	"kInvalidFileData":      ErrInvalidFileData,
+	"kFileContentError":     ErrFileContentError,
+	"kOpenFileFailed":       ErrOpenFileFailed,
+	"kSeekFileFailed":       ErrSeekFileFailed,
+	"kRenameFailed":         ErrRenameFailed,
+	"kFileNotFound":         ErrFileNotFound,
+	"kFileNotFinish":        ErrFileNotFinish,
+	"kXmlCmdTooLong":        ErrXmlCmdTooLong,
+	"kInvalidXmlIndex":      ErrInvalidXmlIndex,
+	"kParseXmlFailed":       ErrParseXmlFailed,
+	"kInvalidMethod":        ErrInvalidMethod,
+	"kMemoryFailed":         ErrMemoryFailed,
+	"kSystemError":          ErrSystemError,
+	"kUnsupportVideo":       ErrUnsupportVideo,
+	"kNotMediaFile":         ErrNotMediaFile,
+	"kParseVideoFailed":     ErrParseVideoFailed,
+	"kUnsupportFrameRate":   ErrUnsupportFPS,
+	"kUnsupportFPS":         ErrUnsupportFPS,
+	"kUnsupportResolution":  ErrUnsupportRes,
+	"kUnsupportRes":         ErrUnsupportRes,
+	"kUnsupportFormat":      ErrUnsupportFormat,
+	"kUnsupportDuration":    ErrUnsupportDuration,
+	"kDownloadFileFailed":   ErrDownloadFailed,
+	"kDownloadFailed":       ErrDownloadFailed,
+	"kScreenNodeIsNull":     ErrScreenNodeNull,
+	"kScreenNodeNull":       ErrScreenNodeNull,
+	"kNodeExist":            ErrNodeExist,
+	"kNodeNotExist":         ErrNodeNotExist,
+	"kPluginNotExist":       ErrPluginNotExist,
+	"kCheckLicenseFailed":   ErrCheckLicense,
+	"kCheckLicense":         ErrCheckLicense,
+	"kNotFoundWifiModule":   ErrNotFoundWifi,
+	"kNotFoundWifi":         ErrNotFoundWifi,
+	"kTestWifiUnsuccessful": ErrTestWifiFailed,
+	"kTestWifiFailed":       ErrTestWifiFailed,
+	"kRunningError":         ErrRunningError,
+	"kUnsupportMethod":      ErrUnsupportMethod,
+	"kInvalidGUID":          ErrInvalidGUID,
+	"kFirmwareFormatError":  ErrFirmwareFormat,
+	"kFirmwareFormat":       ErrFirmwareFormat,
+	"kTagNotFound":          ErrTagNotFound,
+	"kAttrNotFound":         ErrAttrNotFound,
+	"kCreateTagFailed":      ErrCreateTagFailed,
+	"kUnsupportDeviceType":  ErrUnsupportDevice,
+	"kUnsupportDevice":      ErrUnsupportDevice,
+	"kPermissionDenied":     ErrPermissionDenied,
+	"kPasswdTooSimple":      ErrPasswdTooSimple,
+}
+
+// ResultCode, SDK result değerini (ör. "kUnsupportMethod") karşılık gelen
+// ErrorCode'a çevirir. Büyük/küçük harf duyarsızdır; bilinmeyen değerler
+// için false döner.
+func ResultCode(result string) (ErrorCode, bool) {
+	if code, ok := resultNames[result]; ok {
+		return code, true
+	}
+	for name, code := range resultNames {
+		if strings.EqualFold(name, result) {
+			return code, true
+		}
+	}
+	return ErrSuccess, false
+}
+
+// errorAnswer, CmdErrorAnswer paketindeki hata kodunu error olarak döner.
+func errorAnswer(data []byte) error {
+	if code, ok := parseErrorCode(data); ok {
+		return code
+	}
+	return protocolErrorf(CmdErrorAnswer, "hata yanıtı çözümlenemedi")
+}
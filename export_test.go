@@ -0,0 +1,13 @@
+package huidu
+
+// Dış test paketinin (huidu_test) doğrudan sınadığı iç fonksiyonlar.
+var ParseSearchDeviceAnswer = parseSearchDeviceAnswer
+
+// Registered, sunucunun kaydında tuttuğu cihaz sayısını döner. Device ve
+// Devices bağlı olmayan kayıtları gizlediğinden kaydın silindiğini
+// doğrulamak için kullanılır.
+func (s *Server) Registered() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.devices)
+}
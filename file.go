@@ -1,6 +1,8 @@
 package huidu
 
 import (
+	"bytes"
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
@@ -8,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // ─── Dosya Yükleme ──────────────────────────────────────────────────────────────
@@ -21,6 +24,15 @@ import (
 //     Her parça için kFileContentAnswer (0x8004) beklenmez (fire-and-forget)
 //  3. kFileEndAsk (0x8005): Transfer tamamlandı bildirimi
 //     Cihaz kFileEndAnswer (0x8006) ile onaylar
+//
+// Context'li varyantlarda ctx her içerik parçasından önce kontrol edilir.
+// İptal edildiğinde kFileEndAsk gönderilmez; cihaz o ana kadar alınan
+// byte'ları saklar ve aynı dosya (aynı MD5) tekrar yüklendiğinde
+// kFileStartAnswer içindeki mevcut byte sayısından devam edilir.
+//
+// Dosya, bellek ve akış kaynaklı tüm yüklemeler aynı upload/uploadOnce
+// akışını kullanır; kaynaklar yalnızca uploadSource'un nasıl konumlandığında
+// ayrılır.
 
 // UploadFile, belirtilen dosyayı cihaza yükler.
 // Dosya tipi dosya uzantısından otomatik tespit edilir.
@@ -30,95 +42,270 @@ import (
 //	    log.Fatal(err)
 //	}
 func (d *Device) UploadFile(filePath string) error {
-	return d.UploadFileAs(filePath, FileTypeAuto)
+	return d.UploadFileAsContext(context.Background(), filePath, FileTypeAuto)
+}
+
+// UploadFileContext, UploadFile ile aynıdır; ctx iptal edildiğinde yükleme
+// bir sonraki parçadan önce durdurulur ve cihazda devam ettirilebilir
+// durumda bırakılır.
+func (d *Device) UploadFileContext(ctx context.Context, filePath string) error {
+	return d.UploadFileAsContext(ctx, filePath, FileTypeAuto)
 }
 
 // UploadFileAs, belirtilen dosyayı belirli bir dosya tipiyle cihaza yükler.
 //
 //	err := dev.UploadFileAs("/path/to/image.jpg", huidu.FileTypeImage)
 func (d *Device) UploadFileAs(filePath string, fileType FileType) error {
+	return d.UploadFileAsContext(context.Background(), filePath, fileType)
+}
+
+// UploadFileAsContext, UploadFileAs ile aynıdır; ctx iptal edildiğinde
+// yükleme yarıda kesilir (bkz. UploadFileContext).
+func (d *Device) UploadFileAsContext(ctx context.Context, filePath string, fileType FileType) error {
 	if err := d.ensureConnected(); err != nil {
 		return err
 	}
 
-	// Dosyayı aç
-	file, err := os.Open(filePath)
+	file, err := openLocalFile(filePath)
 	if err != nil {
-		return fmt.Errorf("dosya açılamadı: %w", err)
+		return err
 	}
 	defer file.Close()
 
-	// Dosya bilgilerini al
+	return d.UploadReaderContext(ctx, file.name, file, file.size, fileType)
+}
+
+// localFile, yüklenmek üzere açılmış yerel bir dosyadır. name, dosyanın
+// cihazdaki adıdır (yolun son öğesi).
+type localFile struct {
+	*os.File
+	name string
+	size int64
+}
+
+// openLocalFile, filePath'teki dosyayı açar ve boyutunu okur. Dosya tipi
+// yüklemede addan belirlenir (bkz. upload).
+func openLocalFile(filePath string) (*localFile, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("dosya açılamadı: %w", err)
+	}
 	stat, err := file.Stat()
 	if err != nil {
-		return fmt.Errorf("dosya bilgisi alınamadı: %w", err)
+		file.Close()
+		return nil, fmt.Errorf("dosya bilgisi alınamadı: %w", err)
 	}
+	return &localFile{File: file, name: filepath.Base(filePath), size: stat.Size()}, nil
+}
 
-	fileName := filepath.Base(filePath)
-	fileSize := stat.Size()
+// UploadFileData, bellek içi veriyi dosya olarak cihaza yükler.
+// Dosya sistemi kullanmadan doğrudan byte verisi yüklemek için kullanılır.
+//
+//	data := []byte("...ikili veri...")
+//	err := dev.UploadFileData("dynamic.jpg", data, huidu.FileTypeImage)
+func (d *Device) UploadFileData(fileName string, fileData []byte, fileType FileType) error {
+	return d.UploadFileDataContext(context.Background(), fileName, fileData, fileType)
+}
 
-	// Dosya tipini belirle
-	if fileType == FileTypeAuto {
-		fileType = detectFileType(filePath)
+// UploadFileDataContext, UploadFileData ile aynıdır; ctx iptal edildiğinde
+// yükleme yarıda kesilir (bkz. UploadFileContext).
+func (d *Device) UploadFileDataContext(ctx context.Context, fileName string, fileData []byte, fileType FileType) error {
+	return d.UploadReaderContext(ctx, fileName, bytes.NewReader(fileData), int64(len(fileData)), fileType)
+}
+
+// UploadReader, r'nin başından itibaren size byte'ı fileName adıyla cihaza
+// yükler. İçerik belleğe alınmaz: MD5 için r bir kez okunur, ardından başa
+// sarılarak parça parça gönderilir. Kaldığı yerden devam etme ve tekrar
+// deneme, r'nin ilgili konuma sarılmasıyla yapılır.
+//
+// Nesne depolamadan gelen büyük videolar için io.ReadSeeker sağlayan
+// herhangi bir kaynak (os.File, bytes.Reader, io.SectionReader) kullanılabilir.
+//
+//	f, _ := os.Open("/data/intro.mp4")
+//	defer f.Close()
+//	st, _ := f.Stat()
+//	err := dev.UploadReader("intro.mp4", f, st.Size(), huidu.FileTypeVideo)
+func (d *Device) UploadReader(fileName string, r io.ReadSeeker, size int64, fileType FileType) error {
+	return d.UploadReaderContext(context.Background(), fileName, r, size, fileType)
+}
+
+// UploadReaderContext, UploadReader ile aynıdır; ctx iptal edildiğinde
+// yükleme yarıda kesilir (bkz. UploadFileContext).
+func (d *Device) UploadReaderContext(ctx context.Context, fileName string, r io.ReadSeeker, size int64, fileType FileType) error {
+	if err := d.ensureConnected(); err != nil {
+		return err
+	}
+	if size < 0 {
+		return fmt.Errorf("geçersiz dosya boyutu: %d", size)
+	}
+
+	md5Hash, err := hashReader(r, size)
+	if err != nil {
+		return err
 	}
+	return d.upload(ctx, &uploadSource{r: r, seeker: r, pos: size}, fileName, size, fileType, md5Hash)
+}
+
+// UploadStream, yalnızca ileri okunabilen bir akıştan (HTTP gövdesi, pipe)
+// size byte'ı fileName adıyla cihaza yükler. Akış iki kez okunamadığından
+// içeriğin MD5'i (hex) önceden bilinmelidir; cihaz dosyayı bu değerle
+// doğrular.
+//
+// Cihaz daha önce alınmış byte'ları bildirirse akıştaki karşılığı okunup
+// atlanır. Gönderilmiş bir bölümün tekrarı gereken durumlarda (ör. içerik
+// gönderildikten sonra meşgul reddi) akış geri sarılamayacağı için hata döner.
+//
+//	resp, _ := http.Get(objectURL)
+//	defer resp.Body.Close()
+//	err := dev.UploadStream("intro.mp4", resp.Body, resp.ContentLength,
+//	    resp.Header.Get("X-Content-MD5"), huidu.FileTypeVideo)
+func (d *Device) UploadStream(fileName string, r io.Reader, size int64, md5Hash string, fileType FileType) error {
+	return d.UploadStreamContext(context.Background(), fileName, r, size, md5Hash, fileType)
+}
 
-	// MD5 hesapla
+// UploadStreamContext, UploadStream ile aynıdır; ctx iptal edildiğinde
+// yükleme yarıda kesilir (bkz. UploadFileContext).
+func (d *Device) UploadStreamContext(ctx context.Context, fileName string, r io.Reader, size int64, md5Hash string, fileType FileType) error {
+	if err := d.ensureConnected(); err != nil {
+		return err
+	}
+	if size < 0 {
+		return fmt.Errorf("geçersiz dosya boyutu: %d", size)
+	}
+	if sum, err := hex.DecodeString(md5Hash); err != nil || len(sum) != md5.Size {
+		return fmt.Errorf("geçersiz MD5 değeri: %q", md5Hash)
+	}
+
+	return d.upload(ctx, &uploadSource{r: r}, fileName, size, fileType, strings.ToLower(md5Hash))
+}
+
+// hashReader, r'nin başından itibaren size byte'ın MD5'ini (hex) hesaplar.
+// Dönüşte r, size konumunda kalır.
+func hashReader(r io.ReadSeeker, size int64) (string, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("dosya konumu sıfırlanamadı: %w", err)
+	}
 	hasher := md5.New()
-	if _, err := io.Copy(hasher, file); err != nil {
-		return fmt.Errorf("MD5 hesaplanamadı: %w", err)
+	if _, err := io.CopyN(hasher, r, size); err != nil {
+		return "", fmt.Errorf("MD5 hesaplanamadı: %w", err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// uploadSource, yüklenecek içeriğin kaynağıdır. pos, r'de okunmuş byte
+// sayısıdır; seeker nil ise kaynak yalnızca ileri okunabilir.
+type uploadSource struct {
+	r      io.Reader
+	seeker io.Seeker
+	pos    int64
+}
+
+// Read, io.Reader arayüzünü uygular ve okunan konumu izler.
+func (s *uploadSource) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	s.pos += int64(n)
+	return n, err
+}
+
+// skipTo, kaynağı offset'e getirir. Geri sarılamayan kaynaklarda ileri
+// konumlar okunup atılır; geri gitmek gerekiyorsa hata döner.
+func (s *uploadSource) skipTo(offset int64) error {
+	if s.seeker != nil {
+		if _, err := s.seeker.Seek(offset, io.SeekStart); err != nil {
+			return fmt.Errorf("dosya konumu ayarlanamadı: %w", err)
+		}
+		s.pos = offset
+		return nil
 	}
-	md5Hash := hex.EncodeToString(hasher.Sum(nil))
+	if offset < s.pos {
+		return fmt.Errorf("akış %d. byte'a geri sarılamaz (%d byte okundu)", offset, s.pos)
+	}
+	if _, err := io.CopyN(io.Discard, s, offset-s.pos); err != nil {
+		return fmt.Errorf("akış %d. byte'a ilerletilemedi: %w", offset, err)
+	}
+	return nil
+}
 
-	// Dosya başına geri dön
-	if _, err := file.Seek(0, io.SeekStart); err != nil {
-		return fmt.Errorf("dosya konumu sıfırlanamadı: %w", err)
+// upload, tüm yükleme yollarının ortak gövdesidir: dosya tipini belirler,
+// aktarımı RetryPolicy'ye göre tekrar dener ve WithCommandObserver'a bildirir.
+func (d *Device) upload(ctx context.Context, src *uploadSource, fileName string, fileSize int64, fileType FileType, md5Hash string) error {
+	// Dosya tipini belirle
+	if fileType == FileTypeAuto {
+		fileType = detectFileType(fileName)
 	}
 
 	d.logf("Dosya yükleme başlatılıyor: %s (%d bytes, MD5: %s)", fileName, fileSize, md5Hash)
 
-	// Aşama 1: File Start
-	startPkt := buildFileStartPacket(fileName, fileSize, fileType, md5Hash)
-	if err := d.sendRaw(startPkt); err != nil {
-		return fmt.Errorf("dosya başlatma paketi gönderilemedi: %w", err)
+	start := time.Now()
+	_, err := d.withRetry(ctx, "UploadFile "+fileName, true, func(int) (*SdkResponse, error) {
+		return nil, d.uploadOnce(ctx, src, fileName, fileSize, fileType, md5Hash)
+	})
+	d.observeTransfer("UploadFile", fileName, fileSize, start, err)
+	return err
+}
+
+// uploadOnce, içeriği tek bir transfer denemesiyle yükler.
+// Cihaz daha önce alınmış byte'ları bildirirse kaldığı yerden devam edilir;
+// bu sayede reddedilen veya yarıda kalan bir yükleme güvenle tekrarlanabilir.
+func (d *Device) uploadOnce(ctx context.Context, src *uploadSource, fileName string, fileSize int64, fileType FileType, md5Hash string) error {
+	// Transfer boyunca başka komutların araya girmesini engelle
+	l, release, err := d.beginTransfer(ctx)
+	if err != nil {
+		return err
 	}
+	defer release()
 
-	// File Start yanıtını oku
-	data, cmdType, err := d.readPacket()
+	// Aşama 1: File Start
+	startPkt := buildFileStartPacket(fileName, fileSize, fileType, md5Hash)
+	in, err := d.roundTrip(ctx, l, "", [][]byte{startPkt}, CmdFileStartAnswer, CmdErrorAnswer)
 	if err != nil {
-		return fmt.Errorf("dosya başlatma yanıtı okunamadı: %w", err)
+		return fmt.Errorf("dosya başlatma yanıtı alınamadı: %w", err)
 	}
+	data, cmdType := in.data, in.cmd
 
+	if cmdType == CmdErrorAnswer {
+		return fmt.Errorf("dosya başlatma hatası: %w", errorAnswer(data))
+	}
 	if cmdType != CmdFileStartAnswer {
-		return fmt.Errorf("beklenmeyen yanıt tipi: %s (0x%04x)", cmdType, uint16(cmdType))
+		return protocolErrorf(cmdType, "beklenmeyen yanıt tipi: %s (0x%04x)", cmdType, uint16(cmdType))
 	}
 
 	errCode, existBytes, ok := parseFileStartResponse(data)
 	if !ok {
-		return fmt.Errorf("dosya başlatma yanıtı çözümlenemedi")
+		return protocolErrorf(CmdFileStartAnswer, "dosya başlatma yanıtı çözümlenemedi")
 	}
 
 	if errCode != ErrSuccess {
-		return fmt.Errorf("dosya başlatma hatası: %s", errCode)
+		return fmt.Errorf("dosya başlatma hatası: %w", errCode)
 	}
 
 	// Resume desteği: daha önce gönderilmiş byte'ları atla
-	if existBytes > 0 {
-		d.logf("Devam ediliyor: %d byte zaten gönderilmiş", existBytes)
-		if _, err := file.Seek(int64(existBytes), io.SeekStart); err != nil {
-			return fmt.Errorf("dosya konumu ayarlanamadı: %w", err)
-		}
+	sentBytes := int64(existBytes)
+	if sentBytes > fileSize {
+		sentBytes = fileSize
+	}
+	if sentBytes > 0 {
+		d.logf("Devam ediliyor: %d byte zaten gönderilmiş", sentBytes)
+	}
+	if err := src.skipTo(sentBytes); err != nil {
+		return err
 	}
 
 	// Aşama 2: File Content (parçalar halinde gönder)
 	buf := make([]byte, MaxContentLength)
-	sentBytes := int64(existBytes)
-	totalBytes := fileSize
+	for sentBytes < fileSize {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("dosya yükleme iptal edildi (%d/%d byte gönderildi): %w", sentBytes, fileSize, err)
+		}
 
-	for {
-		n, err := file.Read(buf)
+		chunk := buf
+		if rest := fileSize - sentBytes; rest < int64(len(chunk)) {
+			chunk = chunk[:rest]
+		}
+		n, err := io.ReadFull(src, chunk)
 		if n > 0 {
-			contentPkt := buildFileContentPacket(buf[:n])
-			if err := d.sendRaw(contentPkt); err != nil {
+			contentPkt := buildFileContentPacket(chunk[:n])
+			if err := d.sendOn(ctx, l, contentPkt); err != nil {
 				return fmt.Errorf("dosya içeriği gönderilemedi: %w", err)
 			}
 
@@ -126,18 +313,16 @@ func (d *Device) UploadFileAs(filePath string, fileType FileType) error {
 
 			// İlerleme callback'i çağır
 			if d.opts.onProgress != nil {
-				progress := float64(sentBytes) / float64(totalBytes) * 100
 				d.opts.onProgress(UploadProgress{
 					FileName:   fileName,
-					TotalBytes: totalBytes,
+					TotalBytes: fileSize,
 					SentBytes:  sentBytes,
-					Percent:    progress,
+					Percent:    float64(sentBytes) / float64(fileSize) * 100,
 				})
 			}
 		}
-
-		if err == io.EOF {
-			break
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return fmt.Errorf("dosya okuma hatası: içerik beklenenden kısa (%d/%d byte): %w", sentBytes, fileSize, io.ErrUnexpectedEOF)
 		}
 		if err != nil {
 			return fmt.Errorf("dosya okuma hatası: %w", err)
@@ -146,133 +331,292 @@ func (d *Device) UploadFileAs(filePath string, fileType FileType) error {
 
 	// Aşama 3: File End
 	endPkt := buildFileEndPacket()
-	if err := d.sendRaw(endPkt); err != nil {
-		return fmt.Errorf("dosya bitiş paketi gönderilemedi: %w", err)
-	}
-
-	// File End yanıtını oku
-	data, cmdType, err = d.readPacket()
+	in, err = d.roundTrip(ctx, l, "", [][]byte{endPkt}, CmdFileEndAnswer, CmdErrorAnswer)
 	if err != nil {
-		return fmt.Errorf("dosya bitiş yanıtı okunamadı: %w", err)
+		return fmt.Errorf("dosya bitiş yanıtı alınamadı: %w", err)
 	}
+	data, cmdType = in.data, in.cmd
 
+	if cmdType == CmdErrorAnswer {
+		return fmt.Errorf("dosya bitiş hatası: %w", errorAnswer(data))
+	}
 	if cmdType != CmdFileEndAnswer {
-		return fmt.Errorf("beklenmeyen yanıt tipi: %s (0x%04x)", cmdType, uint16(cmdType))
+		return protocolErrorf(cmdType, "beklenmeyen yanıt tipi: %s (0x%04x)", cmdType, uint16(cmdType))
 	}
 
 	endErrCode, ok := parseFileEndResponse(data)
 	if !ok {
-		return fmt.Errorf("dosya bitiş yanıtı çözümlenemedi")
+		return protocolErrorf(CmdFileEndAnswer, "dosya bitiş yanıtı çözümlenemedi")
 	}
 
 	if endErrCode != ErrSuccess && endErrCode != ErrWriteFinish {
-		return fmt.Errorf("dosya bitiş hatası: %s", endErrCode)
+		return fmt.Errorf("dosya bitiş hatası: %w", endErrCode)
 	}
 
-	d.logf("Dosya başarıyla yüklendi: %s (%d bytes)", fileName, totalBytes)
+	d.logf("Dosya başarıyla yüklendi: %s (%d bytes)", fileName, fileSize)
 	return nil
 }
 
-// UploadFileData, bellek içi veriyi dosya olarak cihaza yükler.
-// Dosya sistemi kullanmadan doğrudan byte verisi yüklemek için kullanılır.
+// ─── Değişen Dosyaları Yükleme ──────────────────────────────────────────────────
 //
-//	data := []byte("...ikili veri...")
-//	err := dev.UploadFileData("dynamic.jpg", data, huidu.FileTypeImage)
-func (d *Device) UploadFileData(fileName string, fileData []byte, fileType FileType) error {
-	if err := d.ensureConnected(); err != nil {
-		return err
-	}
-
-	fileSize := int64(len(fileData))
+// Aynı oynatma listesi tekrar gönderildiğinde kartta zaten bulunan dosyaları
+// yeniden yüklememek için GetFileList'in bildirdiği ad, boyut ve MD5
+// kullanılır. Yarım kalmış dosyalar atlanmaz; aynı MD5 ile yüklendiklerinde
+// kaldıkları yerden devam ederler.
+
+// UploadIfChanged, verilen dosyalardan cihazda aynı ad ve MD5 ile eksiksiz
+// bulunmayanları yükler. Cihazın dosya listesi bir kez alınır; adı eşleşip
+// içeriği farklı olan dosyalar yeniden yüklenir. Dosya tipleri uzantıdan
+// belirlenir.
+//
+// Cihazdaki ad yolun son öğesi olduğundan, aynı ada düşen iki yol (ör.
+// "a/logo.png" ve "b/logo.png") birbirinin üzerine yazacağı için hiçbir
+// dosya yüklenmeden reddedilir.
+//
+// Bir dosya yüklenemezse kalan dosyalar denenmez; o ana kadarki sonuç
+// hatayla birlikte döner.
+//
+//	res, err := dev.UploadIfChanged("intro.mp4", "logo.png", "promo.mp4")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	log.Printf("%d yüklendi, %d atlandı", len(res.Uploaded), len(res.Skipped))
+func (d *Device) UploadIfChanged(filePaths ...string) (*UploadResult, error) {
+	return d.UploadIfChangedContext(context.Background(), filePaths...)
+}
 
-	// Dosya tipini belirle
-	if fileType == FileTypeAuto {
-		fileType = detectFileType(fileName)
+// UploadIfChangedContext, UploadIfChanged ile aynıdır; ctx iptal edildiğinde
+// o anki yükleme yarıda kesilir (bkz. UploadFileContext) ve kalan dosyalar
+// denenmez.
+func (d *Device) UploadIfChangedContext(ctx context.Context, filePaths ...string) (*UploadResult, error) {
+	paths := make(map[string]string, len(filePaths))
+	for _, filePath := range filePaths {
+		fileName := filepath.Base(filePath)
+		if prev, ok := paths[fileName]; ok {
+			return nil, fmt.Errorf("%s ve %s cihazda aynı adı taşır: %s", prev, filePath, fileName)
+		}
+		paths[fileName] = filePath
 	}
 
-	// MD5 hesapla
-	hasher := md5.New()
-	hasher.Write(fileData)
-	md5Hash := hex.EncodeToString(hasher.Sum(nil))
+	files, err := d.GetFileListContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("dosya listesi alınamadı: %w", err)
+	}
+	existing := make(map[string]FileInfo, len(files))
+	for _, f := range files {
+		existing[f.Name] = f
+	}
 
-	d.logf("Bellek verisi yükleniyor: %s (%d bytes)", fileName, fileSize)
+	res := &UploadResult{}
+	for _, filePath := range filePaths {
+		uploaded, err := d.uploadFileIfChanged(ctx, filePath, existing[filepath.Base(filePath)])
+		if err != nil {
+			return res, fmt.Errorf("%s: %w", filePath, err)
+		}
+		if uploaded {
+			res.Uploaded = append(res.Uploaded, filePath)
+		} else {
+			res.Skipped = append(res.Skipped, filePath)
+		}
+	}
+	return res, nil
+}
 
-	// Aşama 1: File Start
-	startPkt := buildFileStartPacket(fileName, fileSize, fileType, md5Hash)
-	if err := d.sendRaw(startPkt); err != nil {
-		return fmt.Errorf("dosya başlatma paketi gönderilemedi: %w", err)
+// uploadFileIfChanged, dosyanın MD5'ini cihazdaki kayıtla karşılaştırır ve
+// farklıysa yükler. Dosya yüklendiyse true döner.
+func (d *Device) uploadFileIfChanged(ctx context.Context, filePath string, remote FileInfo) (bool, error) {
+	file, err := openLocalFile(filePath)
+	if err != nil {
+		return false, err
 	}
+	defer file.Close()
 
-	data, cmdType, err := d.readPacket()
+	md5Hash, err := hashReader(file, file.size)
 	if err != nil {
-		return fmt.Errorf("dosya başlatma yanıtı okunamadı: %w", err)
+		return false, err
 	}
 
-	if cmdType != CmdFileStartAnswer {
-		return fmt.Errorf("beklenmeyen yanıt tipi: %s", cmdType)
+	if remote.Name != "" && remote.Size == file.size && remote.ExistSize >= remote.Size && strings.EqualFold(remote.MD5, md5Hash) {
+		d.logf("Dosya cihazda güncel, atlanıyor: %s (MD5: %s)", file.name, md5Hash)
+		return false, nil
 	}
 
-	errCode, existBytes, ok := parseFileStartResponse(data)
-	if !ok {
-		return fmt.Errorf("dosya başlatma yanıtı çözümlenemedi")
+	err = d.upload(ctx, &uploadSource{r: file, seeker: file, pos: file.size}, file.name, file.size, FileTypeAuto, md5Hash)
+	return err == nil, err
+}
+
+// ─── Dosya İndirme ──────────────────────────────────────────────────────────────
+//
+// Dosya indirme kReadFileAsk (0x8007) / kReadFileAnswer (0x8008) ile parça
+// parça yapılır: her istek bir offset ve en fazla MaxContentLength byte
+// ister, kart parçayı dosyanın toplam boyutuyla birlikte döner. Parçalar
+// sırayla hedefe yazılır ve indirme sonunda içeriğin MD5'i GetFileList'te
+// bildirilen değerle karşılaştırılır.
+//
+// Kart aynı anda sınırlı sayıda okumaya izin verir. Bir parça
+// ErrReadFileExcessive ile reddedilirse aynı offset, RetryPolicy
+// beklemeleriyle yeniden istenir (politika yoksa DefaultRetryAttempts
+// deneme). Hedefe yazılmış veri geri alınamadığından indirme bütünüyle
+// tekrarlanmaz.
+
+// DownloadFile, cihazdaki dosyayı w'ya indirir ve MD5'ini doğrular.
+// Dosya cihazda yoksa ErrFileNotFound, içerik bildirilen MD5 ile
+// uyuşmazsa ErrFileContentError sarılı döner.
+//
+//	f, _ := os.Create("logo.png")
+//	defer f.Close()
+//	err := dev.DownloadFile("logo.png", f)
+//	if errors.Is(err, huidu.ErrFileNotFound) {
+//	    // dosya kartta yok
+//	}
+func (d *Device) DownloadFile(fileName string, w io.Writer) error {
+	return d.DownloadFileContext(context.Background(), fileName, w)
+}
+
+// DownloadFileContext, DownloadFile ile aynıdır; ctx iptal edildiğinde
+// indirme bir sonraki parçadan önce durdurulur. O ana kadar alınan
+// parçalar w'ya yazılmış olarak kalır.
+func (d *Device) DownloadFileContext(ctx context.Context, fileName string, w io.Writer) (err error) {
+	if err := d.ensureConnected(); err != nil {
+		return err
 	}
 
-	if errCode != ErrSuccess {
-		return fmt.Errorf("dosya başlatma hatası: %s", errCode)
+	start := time.Now()
+	received := int64(0)
+	defer func() { d.observeTransfer("DownloadFile", fileName, received, start, err) }()
+
+	// Boyut ve MD5 için dosya listesini al
+	files, err := d.GetFileListContext(ctx)
+	if err != nil {
+		return fmt.Errorf("dosya listesi alınamadı: %w", err)
 	}
+	var info *FileInfo
+	for i := range files {
+		if files[i].Name == fileName {
+			info = &files[i]
+			break
+		}
+	}
+	if info == nil {
+		return fmt.Errorf("dosya indirilemedi (%s): %w", fileName, ErrFileNotFound)
+	}
+
+	d.logf("Dosya indirme başlatılıyor: %s (%d bytes, MD5: %s)", fileName, info.Size, info.MD5)
+
+	// Transfer boyunca başka komutların araya girmesini engelle
+	l, release, err := d.beginTransfer(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
 
-	// Aşama 2: File Content
-	offset := int(existBytes)
-	for offset < len(fileData) {
-		end := offset + MaxContentLength
-		if end > len(fileData) {
-			end = len(fileData)
+	hasher := md5.New()
+	out := io.MultiWriter(w, hasher)
+	totalBytes := info.Size
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("dosya indirme iptal edildi (%d/%d byte alındı): %w", received, totalBytes, err)
 		}
 
-		contentPkt := buildFileContentPacket(fileData[offset:end])
-		if err := d.sendRaw(contentPkt); err != nil {
-			return fmt.Errorf("dosya içeriği gönderilemedi: %w", err)
+		chunk, fileSize, err := d.readFileChunk(ctx, l, fileName, received)
+		if err != nil {
+			return err
 		}
+		totalBytes = fileSize
 
-		offset = end
+		if len(chunk) == 0 {
+			if received < totalBytes {
+				return protocolErrorf(CmdReadFileAnswer, "boş parça alındı (%d/%d byte)", received, totalBytes)
+			}
+			break
+		}
+		if _, err := out.Write(chunk); err != nil {
+			return fmt.Errorf("dosya yazılamadı: %w", err)
+		}
+		received += int64(len(chunk))
 
 		if d.opts.onProgress != nil {
-			progress := float64(offset) / float64(fileSize) * 100
 			d.opts.onProgress(UploadProgress{
 				FileName:   fileName,
-				TotalBytes: fileSize,
-				SentBytes:  int64(offset),
-				Percent:    progress,
+				TotalBytes: totalBytes,
+				SentBytes:  received,
+				Percent:    float64(received) / float64(totalBytes) * 100,
+				Download:   true,
 			})
 		}
-	}
 
-	// Aşama 3: File End
-	endPkt := buildFileEndPacket()
-	if err := d.sendRaw(endPkt); err != nil {
-		return fmt.Errorf("dosya bitiş paketi gönderilemedi: %w", err)
+		if received >= totalBytes {
+			break
+		}
 	}
 
-	data, cmdType, err = d.readPacket()
-	if err != nil {
-		return fmt.Errorf("dosya bitiş yanıtı okunamadı: %w", err)
+	// İçeriği cihazın bildirdiği MD5 ile doğrula
+	sum := hex.EncodeToString(hasher.Sum(nil))
+	if info.MD5 != "" && !strings.EqualFold(sum, info.MD5) {
+		return fmt.Errorf("dosya MD5 uyuşmuyor (%s: beklenen %s, alınan %s): %w", fileName, info.MD5, sum, ErrFileContentError)
 	}
 
-	if cmdType != CmdFileEndAnswer {
-		return fmt.Errorf("beklenmeyen yanıt tipi: %s", cmdType)
-	}
+	d.logf("Dosya başarıyla indirildi: %s (%d bytes)", fileName, received)
+	return nil
+}
 
-	endErrCode, ok := parseFileEndResponse(data)
-	if !ok {
-		return fmt.Errorf("dosya bitiş yanıtı çözümlenemedi")
+// readFileChunk, offset'ten başlayan bir parçayı ister ve dosyanın toplam
+// boyutuyla birlikte döner. ErrReadFileExcessive retlerinde aynı parça
+// RetryPolicy beklemeleriyle yeniden istenir.
+func (d *Device) readFileChunk(ctx context.Context, l *link, fileName string, offset int64) ([]byte, int64, error) {
+	policy := d.opts.retry
+	if policy == nil {
+		policy = &RetryPolicy{}
 	}
+	limit := policy.attempts()
 
-	if endErrCode != ErrSuccess && endErrCode != ErrWriteFinish {
-		return fmt.Errorf("dosya bitiş hatası: %s", endErrCode)
-	}
+	for attempt := 1; ; attempt++ {
+		askPkt := buildReadFileAskPacket(fileName, offset, MaxContentLength)
+		in, err := d.roundTrip(ctx, l, "", [][]byte{askPkt}, CmdReadFileAnswer, CmdErrorAnswer)
+		if err != nil {
+			return nil, 0, fmt.Errorf("dosya okuma yanıtı alınamadı: %w", err)
+		}
+		data, cmdType := in.data, in.cmd
 
-	d.logf("Veri başarıyla yüklendi: %s (%d bytes)", fileName, fileSize)
-	return nil
+		if cmdType == CmdErrorAnswer {
+			return nil, 0, fmt.Errorf("dosya okuma hatası: %w", errorAnswer(data))
+		}
+		if cmdType != CmdReadFileAnswer {
+			return nil, 0, protocolErrorf(cmdType, "beklenmeyen yanıt tipi: %s (0x%04x)", cmdType, uint16(cmdType))
+		}
+
+		errCode, fileSize, at, chunk, ok := parseReadFileResponse(data)
+		if !ok {
+			return nil, 0, protocolErrorf(CmdReadFileAnswer, "dosya okuma yanıtı çözümlenemedi")
+		}
+
+		if errCode == ErrReadFileExcessive && attempt < limit {
+			delay := policy.backoff(attempt)
+			op := "DownloadFile " + fileName
+			d.logf("%s reddedildi (%v), %s sonra tekrar denenecek (%d/%d)", op, errCode, delay, attempt+1, limit)
+			d.emit(Event{Type: EventRetrying, Attempt: attempt + 1, Op: op, Delay: delay, Err: errCode})
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, 0, fmt.Errorf("dosya indirme iptal edildi: %w", ctx.Err())
+			}
+			continue
+		}
+		if errCode != ErrSuccess {
+			return nil, 0, fmt.Errorf("dosya okuma hatası: %w", errCode)
+		}
+		if at != offset {
+			return nil, 0, protocolErrorf(CmdReadFileAnswer, "beklenmeyen parça offset'i: %d (beklenen %d)", at, offset)
+		}
+		if at+int64(len(chunk)) > fileSize {
+			return nil, 0, protocolErrorf(CmdReadFileAnswer, "parça dosya sınırını aşıyor: %d+%d > %d", at, len(chunk), fileSize)
+		}
+		return chunk, fileSize, nil
+	}
 }
 
 // ─── Dosya Tipi Tespiti ─────────────────────────────────────────────────────────
@@ -339,8 +683,14 @@ func detectFileType(filePath string) FileType {
 //
 //	err := dev.UploadFiles("/path/to/img1.jpg", "/path/to/img2.png")
 func (d *Device) UploadFiles(filePaths ...string) error {
+	return d.UploadFilesContext(context.Background(), filePaths...)
+}
+
+// UploadFilesContext, UploadFiles ile aynıdır; ctx iptal edildiğinde
+// yüklenmekte olan dosya yarıda kesilir ve kalan dosyalar atlanır.
+func (d *Device) UploadFilesContext(ctx context.Context, filePaths ...string) error {
 	for _, path := range filePaths {
-		if err := d.UploadFile(path); err != nil {
+		if err := d.UploadFileContext(ctx, path); err != nil {
 			return fmt.Errorf("dosya yükleme hatası (%s): %w", path, err)
 		}
 	}
@@ -0,0 +1,466 @@
+package huidu_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+	"github.com/alparslanahmed/huidu-led/huidutest"
+)
+
+// writeFile, dir altında name adlı dosyayı data ile oluşturur ve yolunu döner.
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestUploadIfChanged(t *testing.T) {
+	ctrl := huidutest.NewController()
+	defer ctrl.Close()
+	ctrl.PutFile("logo.png", []byte("logo"), huidu.FileTypeImage)
+	ctrl.PutFile("promo.mp4", []byte("eski promo"), huidu.FileTypeVideo)
+	dev := connect(t, ctrl)
+
+	dir := t.TempDir()
+	logo := writeFile(t, dir, "logo.png", []byte("logo"))
+	intro := writeFile(t, dir, "intro.mp4", []byte("intro"))
+	promo := writeFile(t, dir, "media/promo.mp4", []byte("yeni promo"))
+
+	res, err := dev.UploadIfChanged(logo, intro, promo)
+	if err != nil {
+		t.Fatalf("UploadIfChanged: %v", err)
+	}
+	want := &huidu.UploadResult{Uploaded: []string{intro, promo}, Skipped: []string{logo}}
+	if !reflect.DeepEqual(res, want) {
+		t.Fatalf("sonuç = %+v, beklenen %+v", res, want)
+	}
+
+	for name, data := range map[string]string{"logo.png": "logo", "intro.mp4": "intro", "promo.mp4": "yeni promo"} {
+		f, ok := ctrl.File(name)
+		if !ok || !f.Complete || !bytes.Equal(f.Data, []byte(data)) {
+			t.Errorf("kartta %s = %q (tamam: %v), beklenen %q", name, f.Data, f.Complete, data)
+		}
+	}
+	if f, _ := ctrl.File("intro.mp4"); f.Type != huidu.FileTypeVideo {
+		t.Errorf("intro.mp4 tipi = %d, uzantıdan video bekleniyordu", f.Type)
+	}
+}
+
+func TestUploadIfChangedRejectsDuplicateNames(t *testing.T) {
+	ctrl := huidutest.NewController()
+	defer ctrl.Close()
+	dev := connect(t, ctrl)
+	ctrl.ClearRequests()
+
+	dir := t.TempDir()
+	a := writeFile(t, dir, "a/logo.png", []byte("a"))
+	b := writeFile(t, dir, "b/logo.png", []byte("b"))
+	intro := writeFile(t, dir, "intro.mp4", []byte("intro"))
+
+	res, err := dev.UploadIfChanged(intro, a, b)
+	if err == nil || !strings.Contains(err.Error(), "logo.png") {
+		t.Fatalf("hata = %v, aynı ad hatası bekleniyordu", err)
+	}
+	if res != nil {
+		t.Errorf("sonuç = %+v, nil bekleniyordu", res)
+	}
+	if reqs := ctrl.Requests(); len(reqs) != 0 {
+		t.Errorf("reddedilen çağrı karta istek gönderdi: %v", reqs)
+	}
+	if _, ok := ctrl.File("intro.mp4"); ok {
+		t.Error("reddedilen çağrı dosya yükledi")
+	}
+}
+
+// waitFile, kartta name dosyasının en az size byte'a ulaşmasını bekler.
+// Simülatör içerik paketini yazma döndükten sonra işler.
+func waitFile(t *testing.T, ctrl *huidutest.Controller, name string, size int) huidutest.File {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		f, _ := ctrl.File(name)
+		if len(f.Data) >= size || time.Now().After(deadline) {
+			return f
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// İçerik aşamasında iptal edilen yükleme kFileEndAsk göndermeden durmalı,
+// bağlantıyı açık bırakmalı ve sonraki yükleme karttaki byte sayısından
+// devam etmelidir.
+func TestUploadCancelResumes(t *testing.T) {
+	ctrl := huidutest.NewController()
+	defer ctrl.Close()
+	// Tetiklenirse kFileEndAsk'in gönderildiği anlaşılır; yanıtı değiştirmez.
+	ctrl.Inject(huidutest.Fault{On: huidu.CmdFileEndAsk, Delay: time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var progress []int64
+	dev := huidu.NewDeviceFromConn(ctrl.Pipe(), huidu.WithProgressCallback(func(p huidu.UploadProgress) {
+		progress = append(progress, p.SentBytes)
+		if p.SentBytes == huidu.MaxContentLength {
+			cancel()
+		}
+	}))
+	if err := dev.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer dev.Close()
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), (3*huidu.MaxContentLength+100)/16)
+	err := dev.UploadFileDataContext(ctx, "clip.mp4", data, huidu.FileTypeVideo)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("hata = %v, context.Canceled bekleniyordu", err)
+	}
+	if f := waitFile(t, ctrl, "clip.mp4", huidu.MaxContentLength); f.Complete || len(f.Data) != huidu.MaxContentLength {
+		t.Fatalf("iptal sonrası kartta %d byte (tamam: %v), beklenen %d", len(f.Data), f.Complete, huidu.MaxContentLength)
+	}
+	if ctrl.PendingFaults() != 1 {
+		t.Fatal("iptal edilen yükleme kFileEndAsk gönderdi")
+	}
+	if !dev.IsConnected() {
+		t.Fatal("paketler arasında iptal bağlantıyı kapattı")
+	}
+
+	progress = nil
+	if err := dev.UploadFileData("clip.mp4", data, huidu.FileTypeVideo); err != nil {
+		t.Fatalf("ikinci yükleme: %v", err)
+	}
+	if len(progress) == 0 || progress[0] != 2*huidu.MaxContentLength {
+		t.Fatalf("ilerleme = %v, %d byte'tan devam bekleniyordu", progress, huidu.MaxContentLength)
+	}
+	if f, _ := ctrl.File("clip.mp4"); !f.Complete || !bytes.Equal(f.Data, data) {
+		t.Fatalf("kartta %d/%d byte (tamam: %v)", len(f.Data), len(data), f.Complete)
+	}
+}
+
+// stallConn, skip içerik paketini olduğu gibi geçirir; sonrakinin yalnızca
+// yarısını yazar ve yazma deadline'ı geçmişe çekilene (ctx iptali) kadar
+// bekler.
+type stallConn struct {
+	net.Conn
+	skip    int
+	stalled chan struct{}
+	expired chan struct{}
+	once    sync.Once
+}
+
+func (c *stallConn) Write(p []byte) (int, error) {
+	if len(p) <= huidu.MaxContentLength || c.skip < 0 {
+		return c.Conn.Write(p)
+	}
+	if c.skip > 0 {
+		c.skip--
+		return c.Conn.Write(p)
+	}
+	c.skip = -1
+	n, err := c.Conn.Write(p[:len(p)/2])
+	if err != nil {
+		return n, err
+	}
+	close(c.stalled)
+	<-c.expired
+	return n, os.ErrDeadlineExceeded
+}
+
+func (c *stallConn) SetWriteDeadline(t time.Time) error {
+	if !t.IsZero() && t.Before(time.Now()) {
+		c.once.Do(func() { close(c.expired) })
+	}
+	return c.Conn.SetWriteDeadline(t)
+}
+
+// Bir içerik paketi yarım yazılmışken iptal edilen yükleme bağlantıyı
+// kapatmalıdır; yeniden bağlandıktan sonra yükleme, kartın eksiksiz aldığı
+// paketlerden devam eder.
+func TestUploadCancelMidPacketClosesLink(t *testing.T) {
+	ctrl := huidutest.NewController()
+	defer ctrl.Close()
+
+	var conn *stallConn
+	var progress []int64
+	dev := huidu.NewDevice("sim", huidu.DefaultPort,
+		huidu.WithTimeout(2*time.Second),
+		huidu.WithDialer(func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if conn != nil {
+				return ctrl.Pipe(), nil
+			}
+			conn = &stallConn{Conn: ctrl.Pipe(), skip: 1, stalled: make(chan struct{}), expired: make(chan struct{})}
+			return conn, nil
+		}),
+		huidu.WithProgressCallback(func(p huidu.UploadProgress) {
+			progress = append(progress, p.SentBytes)
+		}),
+	)
+	if err := dev.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer dev.Close()
+
+	data := bytes.Repeat([]byte("fedcba9876543210"), (3*huidu.MaxContentLength+100)/16)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errc := make(chan error, 1)
+	go func() {
+		errc <- dev.UploadFileDataContext(ctx, "clip.mp4", data, huidu.FileTypeVideo)
+	}()
+
+	select {
+	case <-conn.stalled:
+	case err := <-errc:
+		t.Fatalf("yükleme yarım pakete ulaşmadan bitti: %v", err)
+	}
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("hata = %v, context.Canceled bekleniyordu", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for dev.IsConnected() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if dev.IsConnected() {
+		t.Fatal("yarım paket sonrası bağlantı açık kaldı")
+	}
+	if f, _ := ctrl.File("clip.mp4"); f.Complete || len(f.Data) != huidu.MaxContentLength {
+		t.Fatalf("kartta %d byte (tamam: %v), yalnızca ilk paket bekleniyordu", len(f.Data), f.Complete)
+	}
+
+	if err := dev.Connect(); err != nil {
+		t.Fatalf("yeniden bağlanma: %v", err)
+	}
+	progress = nil
+	if err := dev.UploadFileData("clip.mp4", data, huidu.FileTypeVideo); err != nil {
+		t.Fatalf("ikinci yükleme: %v", err)
+	}
+	if len(progress) == 0 || progress[0] != 2*huidu.MaxContentLength {
+		t.Fatalf("ilerleme = %v, %d byte'tan devam bekleniyordu", progress, huidu.MaxContentLength)
+	}
+	if f, _ := ctrl.File("clip.mp4"); !f.Complete || !bytes.Equal(f.Data, data) {
+		t.Fatalf("kartta %d/%d byte (tamam: %v)", len(f.Data), len(data), f.Complete)
+	}
+}
+
+// seekRecorder, Seek ile gidilen konumları ve son Seek'ten sonra okunan
+// byte sayısını kaydeder.
+type seekRecorder struct {
+	*bytes.Reader
+	seeks []int64
+	read  int64
+}
+
+func (r *seekRecorder) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.read += int64(n)
+	return n, err
+}
+
+func (r *seekRecorder) Seek(offset int64, whence int) (int64, error) {
+	pos, err := r.Reader.Seek(offset, whence)
+	r.seeks = append(r.seeks, pos)
+	r.read = 0
+	return pos, err
+}
+
+// forwardReader, yalnızca ileri okunabilen (io.Seeker olmayan) bir akıştır.
+type forwardReader struct {
+	r    io.Reader
+	read int64
+}
+
+func (r *forwardReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.read += int64(n)
+	return n, err
+}
+
+// partialCard, kartta name dosyasının ilk resume byte'ı varmış gibi
+// davranan bir simülatör ve ona bağlı Device döner.
+func partialCard(t *testing.T, name string, data []byte, resume int64, options ...huidu.DeviceOption) (*huidutest.Controller, *huidu.Device) {
+	t.Helper()
+	ctrl := huidutest.NewController()
+	t.Cleanup(func() { ctrl.Close() })
+	ctrl.PutFile(name, data, huidu.FileTypeVideo)
+	ctrl.Inject(huidutest.Fault{On: huidu.CmdFileStartAsk, ResumeAt: resume})
+
+	dev := huidu.NewDeviceFromConn(ctrl.Pipe(), options...)
+	if err := dev.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dev.Close() })
+	return ctrl, dev
+}
+
+func TestUploadReaderResumesBySeeking(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), (2*huidu.MaxContentLength+500)/16)
+	resume := int64(huidu.MaxContentLength + 7)
+	ctrl, dev := partialCard(t, "intro.mp4", data, resume)
+
+	r := &seekRecorder{Reader: bytes.NewReader(data)}
+	if err := dev.UploadReader("intro.mp4", r, int64(len(data)), huidu.FileTypeVideo); err != nil {
+		t.Fatal(err)
+	}
+	// MD5 için baştan okunur, ardından doğrudan devam konumuna gidilir
+	if want := []int64{0, resume}; !reflect.DeepEqual(r.seeks, want) {
+		t.Fatalf("Seek konumları = %v, beklenen %v", r.seeks, want)
+	}
+	if want := int64(len(data)) - resume; r.read != want {
+		t.Fatalf("devam konumundan sonra %d byte okundu, beklenen %d", r.read, want)
+	}
+	if f, _ := ctrl.File("intro.mp4"); !f.Complete || !bytes.Equal(f.Data, data) {
+		t.Fatalf("kartta %d/%d byte (tamam: %v)", len(f.Data), len(data), f.Complete)
+	}
+}
+
+func TestUploadStreamResumesByDiscarding(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), (2*huidu.MaxContentLength+500)/16)
+	resume := int64(huidu.MaxContentLength + 7)
+	var progress []int64
+	ctrl, dev := partialCard(t, "intro.mp4", data, resume, huidu.WithProgressCallback(func(p huidu.UploadProgress) {
+		progress = append(progress, p.SentBytes)
+	}))
+
+	sum := md5.Sum(data)
+	r := &forwardReader{r: bytes.NewReader(data)}
+	if err := dev.UploadStream("intro.mp4", r, int64(len(data)), hex.EncodeToString(sum[:]), huidu.FileTypeVideo); err != nil {
+		t.Fatal(err)
+	}
+	// Kartta olan byte'lar okunup atılır; akış bir kez, sonuna kadar okunur
+	if r.read != int64(len(data)) {
+		t.Fatalf("akıştan %d byte okundu, beklenen %d", r.read, len(data))
+	}
+	if want := resume + huidu.MaxContentLength; len(progress) == 0 || progress[0] != want {
+		t.Fatalf("ilerleme = %v, ilk değer %d bekleniyordu", progress, want)
+	}
+	if f, _ := ctrl.File("intro.mp4"); !f.Complete || !bytes.Equal(f.Data, data) {
+		t.Fatalf("kartta %d/%d byte (tamam: %v)", len(f.Data), len(data), f.Complete)
+	}
+}
+
+// İçerik gönderildikten sonra meşgul reddi alan yükleme tekrar denenir ve
+// kart daha az byte bildirir; akış geri sarılamadığından hata dönmeli,
+// sarılabilen kaynak ise tamamlanmalıdır.
+func TestUploadStreamCannotRewind(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), (2*huidu.MaxContentLength+500)/16)
+	sum := md5.Sum(data)
+	md5Hash := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name   string
+		upload func(dev *huidu.Device) error
+		ok     bool
+	}{
+		{"akış", func(dev *huidu.Device) error {
+			return dev.UploadStream("intro.mp4", &forwardReader{r: bytes.NewReader(data)}, int64(len(data)), md5Hash, huidu.FileTypeVideo)
+		}, false},
+		{"seeker", func(dev *huidu.Device) error {
+			return dev.UploadReader("intro.mp4", bytes.NewReader(data), int64(len(data)), huidu.FileTypeVideo)
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := huidutest.NewController()
+			defer ctrl.Close()
+			ctrl.Inject(
+				huidutest.Fault{On: huidu.CmdFileEndAsk, ErrorAnswer: huidu.ErrDeviceOccupied},
+				huidutest.Fault{On: huidu.CmdFileStartAsk, Skip: 1, ResumeAt: huidu.MaxContentLength},
+			)
+			dev := huidu.NewDeviceFromConn(ctrl.Pipe(), huidu.WithRetryPolicy(huidu.RetryPolicy{
+				MaxAttempts:    2,
+				InitialBackoff: time.Millisecond,
+			}))
+			if err := dev.Connect(); err != nil {
+				t.Fatal(err)
+			}
+			defer dev.Close()
+
+			err := tt.upload(dev)
+			f, _ := ctrl.File("intro.mp4")
+			if tt.ok {
+				if err != nil || !f.Complete || !bytes.Equal(f.Data, data) {
+					t.Fatalf("hata = %v, kartta %d/%d byte (tamam: %v)", err, len(f.Data), len(data), f.Complete)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "geri sarılamaz") {
+				t.Fatalf("hata = %v, geri sarma hatası bekleniyordu", err)
+			}
+			if f.Complete {
+				t.Fatal("geri sarılamayan yükleme tamamlandı")
+			}
+			if _, err := dev.GetDeviceInfo(); err != nil {
+				t.Fatalf("başarısız yüklemeden sonra komut: %v", err)
+			}
+		})
+	}
+}
+
+// Bildirilen boyutla uyuşmayan kaynaklar ve geçersiz parametreler kartta
+// tamamlanmış dosya bırakmadan hata döner; bağlantı kullanılabilir kalır.
+func TestUploadSizeMismatch(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), huidu.MaxContentLength/16)
+	sum := md5.Sum(data)
+	md5Hash := hex.EncodeToString(sum[:])
+	size := int64(len(data)) + 100
+
+	tests := []struct {
+		name   string
+		upload func(dev *huidu.Device) error
+		is     error
+	}{
+		{"kısa seeker", func(dev *huidu.Device) error {
+			return dev.UploadReader("a.mp4", bytes.NewReader(data), size, huidu.FileTypeVideo)
+		}, io.EOF},
+		{"kısa akış", func(dev *huidu.Device) error {
+			return dev.UploadStream("a.mp4", &forwardReader{r: bytes.NewReader(data)}, size, md5Hash, huidu.FileTypeVideo)
+		}, io.ErrUnexpectedEOF},
+		{"negatif boyut (seeker)", func(dev *huidu.Device) error {
+			return dev.UploadReader("a.mp4", bytes.NewReader(data), -1, huidu.FileTypeVideo)
+		}, nil},
+		{"negatif boyut (akış)", func(dev *huidu.Device) error {
+			return dev.UploadStream("a.mp4", bytes.NewReader(data), -1, md5Hash, huidu.FileTypeVideo)
+		}, nil},
+		{"geçersiz MD5", func(dev *huidu.Device) error {
+			return dev.UploadStream("a.mp4", bytes.NewReader(data), int64(len(data)), "abc", huidu.FileTypeVideo)
+		}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := huidutest.NewController()
+			defer ctrl.Close()
+			dev := connect(t, ctrl)
+
+			err := tt.upload(dev)
+			if err == nil || tt.is != nil && !errors.Is(err, tt.is) {
+				t.Fatalf("hata = %v, beklenen %v", err, tt.is)
+			}
+			if f, ok := ctrl.File("a.mp4"); ok && f.Complete {
+				t.Fatal("uyuşmayan yükleme tamamlandı")
+			}
+			if _, err := dev.GetDeviceInfo(); err != nil {
+				t.Fatalf("başarısız yüklemeden sonra komut: %v", err)
+			}
+		})
+	}
+}
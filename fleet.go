@@ -0,0 +1,365 @@
+package huidu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ─── Cihaz Filosu ───────────────────────────────────────────────────────────────
+//
+// Fleet, çok sayıda kartı DeviceID ile yönetir ve aynı işlemi sınırlı
+// eşzamanlılıkla hepsine uygular. Bir cihazdaki hata diğerlerini durdurmaz;
+// sonuçlar cihaz bazında FleetReport ile döner.
+//
+// Fleet'e eklenen cihazlar WithAutoReconnect(true) ile açılır; heartbeat ve
+// arka plandaki yeniden bağlanma döngüsü bağlantıları sıcak tutar.
+
+// DefaultFleetConcurrency, Fleet için varsayılan eşzamanlı işlem sayısıdır.
+const DefaultFleetConcurrency = 16
+
+// Fleet, DeviceID ile anahtarlanmış bir cihaz kümesidir. Tüm metotları
+// eşzamanlı kullanıma uygundur.
+//
+//	fleet := huidu.NewFleet(32, huidu.WithTimeout(5*time.Second))
+//	defer fleet.Close()
+//
+//	fleet.ConnectAll(ctx, []string{"10.0.0.11:10001", "10.0.0.12:10001"})
+//	report := fleet.SetBrightness(ctx, nil, 60)
+//	for _, r := range report.Failed() {
+//	    log.Printf("%s: %v", r.DeviceID, r.Err)
+//	}
+type Fleet struct {
+	// concurrency, aynı anda işlem yapılacak en fazla cihaz sayısıdır.
+	concurrency int
+
+	// options, Fleet'in açtığı her Device'a uygulanır.
+	options []DeviceOption
+
+	mu      sync.RWMutex
+	devices map[string]*Device
+	closed  bool
+}
+
+// NewFleet, boş bir Fleet oluşturur. concurrency toplu işlemlerde aynı anda
+// işlem yapılacak en fazla cihaz sayısıdır (<= 0 ise DefaultFleetConcurrency).
+// options, Add ve ConnectAll ile açılan her Device'a uygulanır.
+func NewFleet(concurrency int, options ...DeviceOption) *Fleet {
+	if concurrency <= 0 {
+		concurrency = DefaultFleetConcurrency
+	}
+	// Bağlantıları sıcak tut; kullanıcı seçenekleri bunu geçersiz kılabilir
+	opts := append([]DeviceOption{WithAutoReconnect(true)}, options...)
+	return &Fleet{
+		concurrency: concurrency,
+		options:     opts,
+		devices:     make(map[string]*Device),
+	}
+}
+
+// Add, host:port adresindeki cihaza bağlanır ve DeviceID ile filoya ekler.
+// Aynı kimlikte bir cihaz zaten varsa eski bağlantı kapatılır.
+func (f *Fleet) Add(ctx context.Context, host string, port int) (*Device, error) {
+	dev := NewDevice(host, port, f.options...)
+	if err := dev.ConnectContext(ctx); err != nil {
+		return nil, err
+	}
+	if err := f.AddDevice(dev); err != nil {
+		dev.Close()
+		return nil, err
+	}
+	return dev, nil
+}
+
+// AddDevice, bağlı bir Device'ı filoya ekler (ör. Server handler'ından gelen
+// cihazlar). Cihazın kimliği CachedDeviceInfo'dan alınır. Aynı kimlikte bir
+// cihaz zaten varsa eski bağlantı kapatılır. Eklenen cihazın sahipliği
+// Fleet'e geçer; Remove veya Close ile kapatılır.
+func (f *Fleet) AddDevice(dev *Device) error {
+	info := dev.CachedDeviceInfo()
+	if info == nil || info.DeviceID == "" {
+		return fmt.Errorf("cihaz kimliği bilinmiyor (%s), önce Connect() çağırın", dev.Host())
+	}
+
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return ErrClosed
+	}
+	old := f.devices[info.DeviceID]
+	f.devices[info.DeviceID] = dev
+	f.mu.Unlock()
+
+	if old != nil && old != dev {
+		old.Close()
+	}
+	return nil
+}
+
+// ConnectAll, verilen "host:port" adreslerine sınırlı eşzamanlılıkla bağlanır
+// ve başarılı olanları filoya ekler. Port verilmeyen adreslerde DefaultPort
+// kullanılır. Rapordaki sonuçlar adrese göredir; başarılı bağlantılarda
+// DeviceID de doldurulur.
+func (f *Fleet) ConnectAll(ctx context.Context, addrs []string) *FleetReport {
+	return f.run(ctx, len(addrs),
+		func(i int) FleetResult { return FleetResult{Addr: addrs[i]} },
+		func(i int, res *FleetResult) {
+			host, port, err := SplitHostPort(addrs[i])
+			if err != nil {
+				res.Err = err
+				return
+			}
+			dev, err := f.Add(ctx, host, port)
+			if err != nil {
+				res.Err = err
+				return
+			}
+			res.DeviceID = dev.CachedDeviceInfo().DeviceID
+		})
+}
+
+// Remove, cihazı filodan çıkarır ve bağlantısını kapatır.
+// Cihaz bulunamazsa false döner.
+func (f *Fleet) Remove(deviceID string) bool {
+	f.mu.Lock()
+	dev, ok := f.devices[deviceID]
+	delete(f.devices, deviceID)
+	f.mu.Unlock()
+
+	if ok {
+		dev.Close()
+	}
+	return ok
+}
+
+// Device, deviceID kimliğindeki cihazı döner (yoksa nil).
+func (f *Fleet) Device(deviceID string) *Device {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.devices[deviceID]
+}
+
+// IDs, filodaki cihaz kimliklerini sıralı olarak döner.
+func (f *Fleet) IDs() []string {
+	f.mu.RLock()
+	ids := make([]string, 0, len(f.devices))
+	for id := range f.devices {
+		ids = append(ids, id)
+	}
+	f.mu.RUnlock()
+	sort.Strings(ids)
+	return ids
+}
+
+// Len, filodaki cihaz sayısını döner.
+func (f *Fleet) Len() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return len(f.devices)
+}
+
+// Close, filodaki tüm cihazların bağlantısını kapatır.
+// Close'dan sonra cihaz eklenemez.
+func (f *Fleet) Close() error {
+	f.mu.Lock()
+	f.closed = true
+	devs := f.devices
+	f.devices = make(map[string]*Device)
+	f.mu.Unlock()
+
+	for _, dev := range devs {
+		dev.Close()
+	}
+	return nil
+}
+
+// ─── Toplu İşlemler ─────────────────────────────────────────────────────────────
+
+// Selector, toplu işlemlerde hangi cihazların seçileceğini belirler.
+// nil Selector filodaki tüm cihazları seçer.
+type Selector func(*Device) bool
+
+// SelectIDs, yalnızca verilen kimliklerdeki cihazları seçer.
+func SelectIDs(ids ...string) Selector {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return func(d *Device) bool {
+		info := d.CachedDeviceInfo()
+		return info != nil && set[info.DeviceID]
+	}
+}
+
+// SelectModel, verilen modeldeki cihazları seçer (ör. "C16L").
+func SelectModel(model string) Selector {
+	return func(d *Device) bool {
+		info := d.CachedDeviceInfo()
+		return info != nil && info.Model == model
+	}
+}
+
+// SelectConnected, yalnızca şu an bağlı olan cihazları seçer.
+func SelectConnected() Selector {
+	return func(d *Device) bool {
+		return d.IsConnected()
+	}
+}
+
+// Do, sel ile seçilen her cihaz için fn'i sınırlı eşzamanlılıkla çağırır.
+// Bir cihazdaki hata diğerlerini durdurmaz. ctx iptal edilirse henüz
+// başlamamış cihazlar ctx hatasıyla raporlanır; fn'e ctx'i kendisi
+// iletmelidir.
+//
+//	report := fleet.Do(ctx, huidu.SelectModel("C16L"), func(d *huidu.Device) error {
+//	    return d.OpenScreenContext(ctx)
+//	})
+func (f *Fleet) Do(ctx context.Context, sel Selector, fn func(*Device) error) *FleetReport {
+	ids, devs := f.selected(sel)
+	return f.run(ctx, len(devs),
+		func(i int) FleetResult { return FleetResult{DeviceID: ids[i], Addr: devs[i].addr()} },
+		func(i int, res *FleetResult) { res.Err = fn(devs[i]) })
+}
+
+// SendScreen, ekranı seçilen cihazlara gönderir.
+func (f *Fleet) SendScreen(ctx context.Context, sel Selector, screen *Screen) *FleetReport {
+	return f.Do(ctx, sel, func(d *Device) error {
+		return d.SendScreenContext(ctx, screen)
+	})
+}
+
+// SetBrightness, seçilen cihazların parlaklığını ayarlar (1-100).
+func (f *Fleet) SetBrightness(ctx context.Context, sel Selector, value int) *FleetReport {
+	return f.Do(ctx, sel, func(d *Device) error {
+		return d.SetBrightnessContext(ctx, value)
+	})
+}
+
+// selected, seçiciye uyan cihazları kimliğe göre sıralı döner.
+func (f *Fleet) selected(sel Selector) ([]string, []*Device) {
+	f.mu.RLock()
+	ids := make([]string, 0, len(f.devices))
+	for id, dev := range f.devices {
+		if sel == nil || sel(dev) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	devs := make([]*Device, len(ids))
+	for i, id := range ids {
+		devs[i] = f.devices[id]
+	}
+	f.mu.RUnlock()
+	return ids, devs
+}
+
+// run, n işi en fazla f.concurrency eşzamanlılıkla çalıştırır ve sonuçları
+// sırayı koruyarak raporlar. describe sonucun kimlik alanlarını doldurur,
+// work işi yapıp hatayı yazar.
+func (f *Fleet) run(ctx context.Context, n int, describe func(i int) FleetResult, work func(i int, res *FleetResult)) *FleetReport {
+	report := &FleetReport{Results: make([]FleetResult, n)}
+	sem := make(chan struct{}, f.concurrency)
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		report.Results[i] = describe(i)
+
+		// Boş yer ve iptal aynı anda hazırsa select rastgele seçer; iptal
+		// edilmiş ctx ile yeni iş başlatılmaması için önce ctx denetlenir.
+		started := ctx.Err() == nil
+		if started {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				started = false
+			}
+		}
+		if !started {
+			// Başlatılmamış işler iptal hatasıyla raporlanır
+			for j := i; j < n; j++ {
+				report.Results[j] = describe(j)
+				report.Results[j].Err = ctx.Err()
+			}
+			wg.Wait()
+			return report
+		}
+
+		wg.Add(1)
+		go func(res *FleetResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			start := time.Now()
+			work(i, res)
+			res.Duration = time.Since(start)
+		}(&report.Results[i])
+	}
+
+	wg.Wait()
+	return report
+}
+
+// ─── Toplu İşlem Raporu ─────────────────────────────────────────────────────────
+
+// FleetResult, toplu işlemin tek bir cihazdaki sonucudur.
+type FleetResult struct {
+	DeviceID string        // Cihaz kimliği (bağlanılamadıysa boş)
+	Addr     string        // Cihaz adresi ("host:port")
+	Err      error         // İşlem hatası (başarılıysa nil)
+	Duration time.Duration // İşlemin süresi
+}
+
+// FleetReport, toplu işlemin cihaz bazındaki sonuçlarıdır.
+type FleetReport struct {
+	// Results, cihaz başına bir sonuç içerir.
+	Results []FleetResult
+}
+
+// Failed, hata alan cihazların sonuçlarını döner.
+func (r *FleetReport) Failed() []FleetResult {
+	var failed []FleetResult
+	for _, res := range r.Results {
+		if res.Err != nil {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Succeeded, işlemin başarılı olduğu cihaz sayısını döner.
+func (r *FleetReport) Succeeded() int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Err, tüm cihazlar başarılıysa nil, değilse cihaz bazındaki hataları
+// birleştiren bir hata döner. errors.Is/As her cihaz hatasına ulaşır.
+func (r *FleetReport) Err() error {
+	var errs []error
+	for _, res := range r.Results {
+		if res.Err != nil {
+			name := res.DeviceID
+			if name == "" {
+				name = res.Addr
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", name, res.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ─── Yardımcılar ────────────────────────────────────────────────────────────────
+
+// addr, cihaz adresini "host:port" olarak döner.
+func (d *Device) addr() string {
+	return net.JoinHostPort(d.host, strconv.Itoa(d.port))
+}
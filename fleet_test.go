@@ -0,0 +1,189 @@
+package huidu_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+	"github.com/alparslanahmed/huidu-led/huidutest"
+)
+
+// newFleet, n simüle karttan oluşan bir filo kurar. Kartların kimlikleri
+// "C16-0", "C16-1", ... biçimindedir.
+func newFleet(t *testing.T, concurrency, n int) (*huidu.Fleet, []*huidutest.Controller) {
+	t.Helper()
+	fleet := huidu.NewFleet(concurrency)
+	t.Cleanup(func() { fleet.Close() })
+
+	ctrls := make([]*huidutest.Controller, n)
+	for i := range ctrls {
+		info := huidutest.DefaultDeviceInfo
+		info.DeviceID = "C16-" + strconv.Itoa(i)
+		ctrls[i] = huidutest.NewController(huidutest.WithDeviceInfo(info))
+		t.Cleanup(func() { ctrls[i].Close() })
+
+		dev := huidu.NewDeviceFromConn(ctrls[i].Pipe())
+		if err := dev.Connect(); err != nil {
+			t.Fatal(err)
+		}
+		if err := fleet.AddDevice(dev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return fleet, ctrls
+}
+
+func TestFleetDoConcurrencyLimit(t *testing.T) {
+	fleet, _ := newFleet(t, 2, 5)
+
+	var active, peak, calls atomic.Int32
+	report := fleet.Do(context.Background(), nil, func(*huidu.Device) error {
+		calls.Add(1)
+		n := active.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		active.Add(-1)
+		return nil
+	})
+
+	if got := peak.Load(); got != 2 {
+		t.Fatalf("en yüksek eşzamanlılık = %d, beklenen 2", got)
+	}
+	if calls.Load() != 5 || report.Succeeded() != 5 || report.Err() != nil {
+		t.Fatalf("%d çağrı, %d başarılı: %v", calls.Load(), report.Succeeded(), report.Err())
+	}
+	for i, res := range report.Results {
+		if want := "C16-" + strconv.Itoa(i); res.DeviceID != want || res.Duration <= 0 {
+			t.Errorf("Results[%d] = %+v, kimlik %s bekleniyordu", i, res, want)
+		}
+	}
+}
+
+func TestFleetPerDeviceErrors(t *testing.T) {
+	fleet, ctrls := newFleet(t, 4, 3)
+	before := ctrls[1].Luminance().DefaultValue
+	ctrls[1].Inject(huidutest.Fault{On: huidu.CmdSdkCmdAsk, Method: huidu.MethodSetLuminancePloy, ErrorAnswer: huidu.ErrInvalidParam})
+
+	report := fleet.SetBrightness(context.Background(), nil, 70)
+
+	failed := report.Failed()
+	if len(failed) != 1 || failed[0].DeviceID != "C16-1" || !errors.Is(failed[0].Err, huidu.ErrInvalidParam) {
+		t.Fatalf("Failed = %+v", failed)
+	}
+	if report.Succeeded() != 2 {
+		t.Fatalf("Succeeded = %d, beklenen 2", report.Succeeded())
+	}
+	err := report.Err()
+	if !errors.Is(err, huidu.ErrInvalidParam) || !strings.Contains(err.Error(), "C16-1:") {
+		t.Fatalf("Err = %v", err)
+	}
+	for i, ctrl := range ctrls {
+		want := 70
+		if i == 1 {
+			want = before
+		}
+		if got := ctrl.Luminance().DefaultValue; got != want {
+			t.Errorf("C16-%d parlaklığı = %d, beklenen %d", i, got, want)
+		}
+	}
+}
+
+func TestFleetDoCancelled(t *testing.T) {
+	t.Run("işlem sırasında iptal", func(t *testing.T) {
+		fleet, _ := newFleet(t, 1, 3)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var calls atomic.Int32
+		report := fleet.Do(ctx, nil, func(*huidu.Device) error {
+			calls.Add(1)
+			cancel()
+			return nil
+		})
+
+		if calls.Load() != 1 {
+			t.Fatalf("fn %d kez çağrıldı, beklenen 1", calls.Load())
+		}
+		if report.Results[0].Err != nil {
+			t.Errorf("başlamış işin sonucu = %v", report.Results[0].Err)
+		}
+		for i, res := range report.Results[1:] {
+			if !errors.Is(res.Err, context.Canceled) || res.DeviceID != fmt.Sprintf("C16-%d", i+1) {
+				t.Errorf("başlamamış iş = %+v, context.Canceled bekleniyordu", res)
+			}
+		}
+	})
+
+	t.Run("önceden iptal", func(t *testing.T) {
+		fleet, _ := newFleet(t, 4, 3)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var calls atomic.Int32
+		report := fleet.Do(ctx, nil, func(*huidu.Device) error {
+			calls.Add(1)
+			return nil
+		})
+		if calls.Load() != 0 {
+			t.Fatalf("iptal edilmiş ctx ile fn %d kez çağrıldı", calls.Load())
+		}
+		if len(report.Results) != 3 || report.Succeeded() != 0 || !errors.Is(report.Err(), context.Canceled) {
+			t.Fatalf("rapor = %+v", report.Results)
+		}
+	})
+}
+
+func TestFleetConnectAllPartial(t *testing.T) {
+	var addrs []string
+	for _, id := range []string{"C16-A", "C16-B"} {
+		info := huidutest.DefaultDeviceInfo
+		info.DeviceID = id
+		ctrl := huidutest.NewController(huidutest.WithDeviceInfo(info))
+		defer ctrl.Close()
+		if err := ctrl.Start(); err != nil {
+			t.Fatal(err)
+		}
+		addrs = append(addrs, ctrl.Addr())
+	}
+
+	// Kapalı bir port
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := ln.Addr().String()
+	ln.Close()
+
+	all := []string{addrs[0], closed, addrs[1], "127.0.0.1:port"}
+	fleet := huidu.NewFleet(4, huidu.WithTimeout(2*time.Second))
+	defer fleet.Close()
+	report := fleet.ConnectAll(context.Background(), all)
+
+	if len(report.Results) != len(all) {
+		t.Fatalf("%d sonuç, beklenen %d", len(report.Results), len(all))
+	}
+	wantIDs := []string{"C16-A", "", "C16-B", ""}
+	for i, res := range report.Results {
+		if res.Addr != all[i] || res.DeviceID != wantIDs[i] || (res.Err == nil) != (wantIDs[i] != "") {
+			t.Errorf("Results[%d] = %+v", i, res)
+		}
+	}
+	if fleet.Len() != 2 || fleet.Device("C16-A") == nil || fleet.Device("C16-B") == nil {
+		t.Fatalf("filo = %v", fleet.IDs())
+	}
+	if report.Succeeded() != 2 || len(report.Failed()) != 2 {
+		t.Fatalf("%d başarılı, %d hatalı", report.Succeeded(), len(report.Failed()))
+	}
+}
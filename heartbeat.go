@@ -0,0 +1,132 @@
+package huidu
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ─── Heartbeat ──────────────────────────────────────────────────────────────────
+//
+// Her bağlantı için heartbeatLoop, WithHeartbeatInterval aralığında
+// CmdHeartbeatAsk gönderir. Cihazın CmdHeartbeatAnswer yanıtı okuma
+// döngüsünde yakalanır ve gidiş-dönüş süresi (RTT) ölçülür.
+//
+// Bir sonraki tick'e kadar yanıt gelmezse heartbeat kaçırılmış sayılır.
+// Art arda WithHeartbeatMaxMissed kadar heartbeat kaçırılırsa TCP bağlantısı
+// yarı açık (ör. kablo çekilmiş, NAT kaydı düşmüş) kabul edilir ve
+// ErrHeartbeatTimeout ile kopuş işlemi başlatılır.
+
+// ErrHeartbeatTimeout, art arda WithHeartbeatMaxMissed kadar heartbeat
+// yanıtsız kaldığında kopuş nedeni olarak (Event.Err) raporlanır.
+var ErrHeartbeatTimeout = errors.New("huidu: heartbeat yanıtı alınamadı")
+
+// heartbeatState, bir bağlantının heartbeat ölçümleridir.
+type heartbeatState struct {
+	sent    time.Time     // son isteğin gönderildiği zaman
+	answer  time.Time     // son yanıtın alındığı zaman
+	rtt     time.Duration // son ölçülen gidiş-dönüş süresi
+	missed  int           // art arda yanıtsız kalan istek sayısı
+	waiting bool          // son isteğe henüz yanıt gelmedi
+}
+
+// heartbeatSent, bir heartbeat isteğinin gönderildiğini kaydeder.
+func (l *link) heartbeatSent(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hb.sent = now
+	l.hb.waiting = true
+}
+
+// heartbeatAnswered, heartbeat yanıtını kaydeder ve ölçülen RTT'yi döner.
+func (l *link) heartbeatAnswered(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hb.answer = now
+	if l.hb.waiting {
+		l.hb.rtt = now.Sub(l.hb.sent)
+		l.hb.waiting = false
+	}
+	l.hb.missed = 0
+	return l.hb.rtt
+}
+
+// heartbeatMissed, önceki istek yanıtsız kaldıysa kaçırılan sayısını artırır
+// ve güncel değeri döner.
+func (l *link) heartbeatMissed() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.hb.waiting {
+		l.hb.missed++
+	}
+	return l.hb.missed
+}
+
+// heartbeatLoop, periyodik heartbeat paketleri gönderen arka plan goroutine'idir.
+// Her başarılı bağlantıda l için yeniden başlatılır, Close() veya
+// bağlantının kopmasıyla durdurulur.
+func (d *Device) heartbeatLoop(l *link, stop chan struct{}) {
+	ticker := time.NewTicker(d.opts.heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-l.done:
+			return
+		case <-ticker.C:
+			d.mu.Lock()
+			if !d.connected || d.link != l {
+				d.mu.Unlock()
+				return
+			}
+			d.mu.Unlock()
+
+			if limit := d.opts.heartbeatMaxMissed; limit > 0 {
+				if missed := l.heartbeatMissed(); missed >= limit {
+					d.logf("Art arda %d heartbeat yanıtsız kaldı, bağlantı kapatılıyor", missed)
+					d.markBroken(l, ErrHeartbeatTimeout)
+					return
+				} else if missed > 0 {
+					d.logf("Heartbeat yanıtı gelmedi (%d/%d)", missed, limit)
+				}
+			}
+
+			pkt := buildHeartbeat()
+			l.heartbeatSent(time.Now())
+			if err := d.sendOn(context.Background(), l, pkt); err != nil {
+				d.logf("Heartbeat gönderilemedi: %v", err)
+				return
+			}
+			d.logf("Heartbeat gönderildi")
+		}
+	}
+}
+
+// HeartbeatStats, aktif bağlantının heartbeat ölçümlerini döner.
+// Bağlantı yoksa sıfır değer döner. Her yeniden bağlanmada ölçümler sıfırlanır.
+//
+//	stats := dev.HeartbeatStats()
+//	log.Printf("%s RTT=%s kaçırılan=%d", dev.Host(), stats.RTT, stats.Missed)
+func (d *Device) HeartbeatStats() HeartbeatStats {
+	l := d.activeLink()
+	if l == nil {
+		return HeartbeatStats{}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return HeartbeatStats{
+		RTT:        l.hb.rtt,
+		LastSent:   l.hb.sent,
+		LastAnswer: l.hb.answer,
+		Missed:     l.hb.missed,
+	}
+}
+
+// HeartbeatRTT, son ölçülen heartbeat gidiş-dönüş süresini döner.
+// Henüz ölçüm yapılmadıysa veya bağlantı yoksa 0 döner.
+func (d *Device) HeartbeatRTT() time.Duration {
+	return d.HeartbeatStats().RTT
+}
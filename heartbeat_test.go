@@ -0,0 +1,65 @@
+package huidu_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+	"github.com/alparslanahmed/huidu-led/huidutest"
+)
+
+// Yanıtlanan heartbeat'ler RTT ölçer; yanıtlar kesilince bağlantı
+// ErrHeartbeatTimeout ile kopmuş sayılır.
+func TestHeartbeatTimeout(t *testing.T) {
+	ctrl := huidutest.NewController()
+	defer ctrl.Close()
+
+	events := make(chan huidu.Event, 16)
+	dev := huidu.NewDeviceFromConn(ctrl.Pipe(),
+		huidu.WithHeartbeatInterval(20*time.Millisecond),
+		huidu.WithHeartbeatMaxMissed(2),
+		huidu.WithEventHandler(func(ev huidu.Event) { events <- ev }),
+	)
+	if err := dev.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer dev.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for dev.HeartbeatRTT() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	stats := dev.HeartbeatStats()
+	if stats.RTT <= 0 || stats.LastAnswer.IsZero() || stats.Missed != 0 {
+		t.Fatalf("yanıtlanan heartbeat istatistikleri = %+v", stats)
+	}
+
+	ctrl.Inject(huidutest.Fault{On: huidu.CmdHeartbeatAsk, Drop: true, Times: -1})
+	start := time.Now()
+	var ev huidu.Event
+	for ev.Type != huidu.EventDisconnected {
+		select {
+		case ev = <-events:
+		case <-time.After(2 * time.Second):
+			t.Fatal("EventDisconnected yayınlanmadı")
+		}
+	}
+	if !errors.Is(ev.Err, huidu.ErrHeartbeatTimeout) {
+		t.Fatalf("kopuş nedeni = %v, ErrHeartbeatTimeout bekleniyordu", ev.Err)
+	}
+	// İlk kaçırılan istek en erken bir aralık sonra fark edilir; sınır iki
+	// kaçırma olduğundan kopuş en az iki aralık sürer.
+	if elapsed := time.Since(start); elapsed < 2*20*time.Millisecond {
+		t.Errorf("kopuş %s sonra algılandı, en az 40ms bekleniyordu", elapsed)
+	}
+	if dev.IsConnected() {
+		t.Fatal("heartbeat zaman aşımından sonra IsConnected true")
+	}
+	if got := dev.HeartbeatStats(); got != (huidu.HeartbeatStats{}) {
+		t.Errorf("bağlantı yokken HeartbeatStats = %+v", got)
+	}
+	if _, err := dev.GetDeviceInfo(); err == nil {
+		t.Error("kopan bağlantıda komut başarılı oldu")
+	}
+}
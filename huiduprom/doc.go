@@ -0,0 +1,64 @@
+// Package huiduprom exports the health of Huidu LED controllers as
+// Prometheus metrics.
+//
+// An Exporter polls each registered Device at a fixed interval for
+// GetDeviceInfo, GetLuminanceInfo, GetSwitchTimeInfo and GetFileList, and
+// serves the latest values in the Prometheus text exposition format. It does
+// not depend on the Prometheus client library; any scraper that reads the
+// text format works.
+//
+// Command latency, transfer throughput, reconnects and retries are recorded
+// as they happen. For that the exporter must observe the Device, so create
+// devices with the options returned by DeviceOptions:
+//
+//	exp := huiduprom.NewExporter(huiduprom.WithInterval(30 * time.Second))
+//
+//	dev := huidu.NewDevice("10.0.0.11", 10001,
+//	    append(exp.DeviceOptions("lobby"), huidu.WithAutoReconnect(true))...)
+//	if err := dev.Connect(); err != nil {
+//	    log.Print(err)
+//	}
+//	exp.Add("lobby", dev)
+//
+//	go exp.Run(ctx)
+//	http.Handle("/metrics", exp)
+//
+// If the device already uses WithEventHandler or WithCommandObserver for
+// something else, call HandleEvent and ObserveCommand from those callbacks
+// instead.
+//
+// Every metric carries a "device" label with the name given to Add:
+//
+//	huidu_up                                    1 if the connection is up
+//	huidu_poll_success                          1 if every query of the last poll succeeded
+//	huidu_poll_duration_seconds                 duration of the last poll
+//	huidu_last_poll_timestamp_seconds           Unix time of the last poll
+//	huidu_device_info                           1, with device_id, model, cpu, firmware, fpga and kernel labels
+//	huidu_screen_width_pixels                   screen width
+//	huidu_screen_height_pixels                  screen height
+//	huidu_brightness_percent                    default brightness (1-100)
+//	huidu_brightness_mode                       0 default, 1 scheduled, 2 sensor
+//	huidu_screen_default_on                     1 if the screen is on outside scheduled periods
+//	huidu_screen_schedule_enabled               1 if the on/off schedule is enabled
+//	huidu_files                                 number of stored files
+//	huidu_file_storage_used_bytes               bytes stored on the controller
+//	huidu_file_storage_incomplete_bytes         bytes still missing from interrupted uploads
+//	huidu_heartbeat_rtt_seconds                 last heartbeat round-trip time
+//	huidu_heartbeat_missed                      consecutive unanswered heartbeats
+//	huidu_disconnects_total                     connection losses
+//	huidu_reconnects_total                      successful automatic reconnects
+//	huidu_retries_total                         commands retried after a busy answer
+//	huidu_command_duration_seconds              histogram per command (SDK method, UploadFile, DownloadFile)
+//	huidu_command_errors_total                  failed commands per command
+//	huidu_upload_bytes_total                    bytes uploaded
+//	huidu_upload_seconds_total                  time spent uploading
+//	huidu_upload_throughput_bytes_per_second    throughput of the last upload
+//
+// There is no free-storage metric because the controller cannot be asked
+// for one: none of the SDK methods reports the card's capacity, and GetFileList
+// only lists the files already stored. A full card shows up only when an
+// upload is rejected with huidu.ErrNotSpaceToSave, which is counted in
+// huidu_command_errors_total{command="UploadFile"}. Alert on that counter
+// together with huidu_file_storage_used_bytes. Query metrics are omitted when the query failed in the last
+// poll, so a stale value never hides an unreachable sign.
+package huiduprom
@@ -0,0 +1,305 @@
+package huiduprom
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+)
+
+// Varsayılan exporter değerleri.
+const (
+	DefaultInterval    = 30 * time.Second
+	DefaultPollTimeout = 20 * time.Second
+)
+
+// DefaultBuckets, huidu_command_duration_seconds histogramının varsayılan
+// üst sınırlarıdır (saniye). Dosya aktarımları için üst kovalar geniş tutulur.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// errNotConnected, bağlantısı olmayan cihazın sorgulanmadığını belirtir.
+var errNotConnected = errors.New("huiduprom: cihaz bağlı değil")
+
+// Exporter, kayıtlı cihazları periyodik olarak sorgular ve son değerleri
+// Prometheus metin formatında sunar. http.Handler arayüzünü uygular.
+// Tüm metotları eşzamanlı kullanıma uygundur.
+type Exporter struct {
+	interval    time.Duration
+	pollTimeout time.Duration
+	buckets     []float64
+
+	mu      sync.Mutex
+	targets map[string]*target
+}
+
+// Option, Exporter yapılandırma seçeneğidir.
+type Option func(*Exporter)
+
+// WithInterval, Run'ın sorgulama aralığını ayarlar (varsayılan: DefaultInterval).
+func WithInterval(d time.Duration) Option {
+	return func(e *Exporter) {
+		if d > 0 {
+			e.interval = d
+		}
+	}
+}
+
+// WithPollTimeout, tek bir cihazın sorgulanması için süre sınırını ayarlar
+// (varsayılan: DefaultPollTimeout).
+func WithPollTimeout(d time.Duration) Option {
+	return func(e *Exporter) {
+		if d > 0 {
+			e.pollTimeout = d
+		}
+	}
+}
+
+// WithBuckets, komut gecikme histogramının kova sınırlarını ayarlar.
+// Sınırlar artan sırada olmalıdır.
+func WithBuckets(buckets []float64) Option {
+	return func(e *Exporter) {
+		if len(buckets) > 0 {
+			e.buckets = append([]float64(nil), buckets...)
+			sort.Float64s(e.buckets)
+		}
+	}
+}
+
+// NewExporter, boş bir Exporter oluşturur. Cihazlar Add ile eklenir.
+func NewExporter(options ...Option) *Exporter {
+	e := &Exporter{
+		interval:    DefaultInterval,
+		pollTimeout: DefaultPollTimeout,
+		buckets:     DefaultBuckets,
+		targets:     make(map[string]*target),
+	}
+	for _, opt := range options {
+		opt(e)
+	}
+	return e
+}
+
+// ─── Cihaz Kaydı ────────────────────────────────────────────────────────────────
+
+// target, tek bir cihazın metrik durumudur.
+type target struct {
+	dev *huidu.Device
+
+	// Olay sayaçları
+	disconnects uint64
+	reconnects  uint64
+	retries     uint64
+
+	// Komut ölçümleri (Op → histogram)
+	commands map[string]*commandMetrics
+
+	// Yükleme ölçümleri
+	uploadBytes   int64
+	uploadSeconds float64
+	uploadRate    float64 // son yüklemenin hızı (byte/sn)
+
+	// Son sorgu sonuçları (başarısız sorgular nil)
+	polled       bool
+	lastPoll     time.Time
+	pollDuration time.Duration
+	pollErr      error
+	info         *huidu.DeviceInfo
+	luminance    *huidu.LuminanceInfo
+	switchTime   *huidu.SwitchTimeInfo
+	files        []huidu.FileInfo
+	filesOK      bool
+}
+
+// commandMetrics, tek bir komutun gecikme histogramı ve hata sayacıdır.
+type commandMetrics struct {
+	counts []uint64 // kova başına (kümülatif olmayan); son eleman +Inf
+	sum    float64
+	count  uint64
+	errors uint64
+}
+
+// target, adı verilen kaydı döner; yoksa oluşturur. mu tutulmalıdır.
+func (e *Exporter) target(name string) *target {
+	t := e.targets[name]
+	if t == nil {
+		t = &target{commands: make(map[string]*commandMetrics)}
+		e.targets[name] = t
+	}
+	return t
+}
+
+// Add, dev'i name adıyla sorgulanacak cihazlara ekler. Aynı adla kayıtlı
+// bir cihaz varsa değiştirilir; birikmiş sayaçlar korunur.
+func (e *Exporter) Add(name string, dev *huidu.Device) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.target(name).dev = dev
+}
+
+// Remove, name adlı cihazı ve metriklerini kaldırır. Cihaz kapatılmaz.
+func (e *Exporter) Remove(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.targets, name)
+}
+
+// DeviceOptions, name adlı cihazın olaylarını ve komut ölçümlerini bu
+// exporter'a bildiren seçenekleri döner. huidu.NewDevice'a verilmelidir.
+func (e *Exporter) DeviceOptions(name string) []huidu.DeviceOption {
+	return []huidu.DeviceOption{
+		huidu.WithEventHandler(func(ev huidu.Event) { e.HandleEvent(name, ev) }),
+		huidu.WithCommandObserver(func(s huidu.CommandStats) { e.ObserveCommand(name, s) }),
+	}
+}
+
+// HandleEvent, name adlı cihazın bağlantı olayını sayaçlara işler.
+// Cihaz WithEventHandler'ı başka bir amaçla kullanıyorsa oradan çağrılır.
+func (e *Exporter) HandleEvent(name string, ev huidu.Event) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	t := e.target(name)
+	switch ev.Type {
+	case huidu.EventDisconnected:
+		t.disconnects++
+	case huidu.EventReconnected:
+		t.reconnects++
+	case huidu.EventRetrying:
+		t.retries++
+	}
+}
+
+// ObserveCommand, name adlı cihazın tamamlanan komutunu histograma işler.
+// Cihaz WithCommandObserver'ı başka bir amaçla kullanıyorsa oradan çağrılır.
+func (e *Exporter) ObserveCommand(name string, s huidu.CommandStats) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	t := e.target(name)
+
+	m := t.commands[s.Op]
+	if m == nil {
+		m = &commandMetrics{counts: make([]uint64, len(e.buckets)+1)}
+		t.commands[s.Op] = m
+	}
+	sec := s.Duration.Seconds()
+	i := sort.SearchFloat64s(e.buckets, sec)
+	m.counts[i]++
+	m.sum += sec
+	m.count++
+	if s.Err != nil {
+		m.errors++
+		return
+	}
+
+	if s.Op == "UploadFile" && s.Bytes > 0 {
+		t.uploadBytes += s.Bytes
+		t.uploadSeconds += sec
+		if sec > 0 {
+			t.uploadRate = float64(s.Bytes) / sec
+		}
+	}
+}
+
+// ─── Sorgulama ──────────────────────────────────────────────────────────────────
+
+// Run, ctx iptal edilene kadar tüm cihazları WithInterval aralığıyla
+// sorgular. İlk sorgu hemen yapılır.
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		e.Poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll, tüm cihazları eşzamanlı olarak bir kez sorgular ve sonuçları
+// saklar. Bağlı olmayan cihazlar sorgulanmaz.
+func (e *Exporter) Poll(ctx context.Context) {
+	e.mu.Lock()
+	devs := make(map[string]*huidu.Device, len(e.targets))
+	for name, t := range e.targets {
+		if t.dev != nil {
+			devs[name] = t.dev
+		}
+	}
+	e.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for name, dev := range devs {
+		wg.Add(1)
+		go func(name string, dev *huidu.Device) {
+			defer wg.Done()
+			e.pollDevice(ctx, name, dev)
+		}(name, dev)
+	}
+	wg.Wait()
+}
+
+// pollDevice, tek bir cihazı sorgular.
+func (e *Exporter) pollDevice(ctx context.Context, name string, dev *huidu.Device) {
+	start := time.Now()
+	var (
+		res  target
+		errs []error
+	)
+
+	if !dev.IsConnected() {
+		errs = append(errs, errNotConnected)
+	} else {
+		ctx, cancel := context.WithTimeout(ctx, e.pollTimeout)
+		defer cancel()
+
+		var err error
+		if res.info, err = dev.GetDeviceInfoContext(ctx); err != nil {
+			res.info = nil
+			errs = append(errs, err)
+		}
+		if res.luminance, err = dev.GetLuminanceInfoContext(ctx); err != nil {
+			res.luminance = nil
+			errs = append(errs, err)
+		}
+		if res.switchTime, err = dev.GetSwitchTimeInfoContext(ctx); err != nil {
+			res.switchTime = nil
+			errs = append(errs, err)
+		}
+		if res.files, err = dev.GetFileListContext(ctx); err != nil {
+			errs = append(errs, err)
+		} else {
+			res.filesOK = true
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	t := e.targets[name]
+	if t == nil || t.dev != dev {
+		// Sorgu sürerken kaldırıldı veya değiştirildi
+		return
+	}
+	t.polled = true
+	t.lastPoll = start
+	t.pollDuration = time.Since(start)
+	t.pollErr = errors.Join(errs...)
+	t.info, t.luminance, t.switchTime = res.info, res.luminance, res.switchTime
+	t.files, t.filesOK = res.files, res.filesOK
+}
+
+// ─── HTTP ───────────────────────────────────────────────────────────────────────
+
+// contentType, Prometheus metin formatının içerik tipidir.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// ServeHTTP, son sorgu sonuçlarını ve sayaçları Prometheus metin formatında
+// yazar. Cihazlar istek sırasında sorgulanmaz.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	w.Write(e.Gather())
+}
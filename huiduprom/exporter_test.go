@@ -0,0 +1,126 @@
+package huiduprom_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+	"github.com/alparslanahmed/huidu-led/huiduprom"
+)
+
+// exposition, TestServeHTTPGolden'ın beklediği çıktıdır. Cihaz Add ile
+// eklenmediğinden sorgu ve bağlantı metrikleri yer almaz; yalnızca olay,
+// komut ve yükleme ölçümleri yazılır.
+const exposition = `# HELP huidu_up Whether the connection to the controller is up.
+# TYPE huidu_up gauge
+# HELP huidu_poll_success Whether every query of the last poll succeeded.
+# TYPE huidu_poll_success gauge
+# HELP huidu_poll_duration_seconds Duration of the last poll.
+# TYPE huidu_poll_duration_seconds gauge
+# HELP huidu_last_poll_timestamp_seconds Unix time of the last poll.
+# TYPE huidu_last_poll_timestamp_seconds gauge
+# HELP huidu_device_info Controller identity and firmware versions.
+# TYPE huidu_device_info gauge
+# HELP huidu_screen_width_pixels Screen width reported by the controller.
+# TYPE huidu_screen_width_pixels gauge
+# HELP huidu_screen_height_pixels Screen height reported by the controller.
+# TYPE huidu_screen_height_pixels gauge
+# HELP huidu_brightness_percent Default brightness (1-100).
+# TYPE huidu_brightness_percent gauge
+# HELP huidu_brightness_mode Brightness mode: 0 default, 1 scheduled, 2 sensor.
+# TYPE huidu_brightness_mode gauge
+# HELP huidu_screen_default_on Whether the screen is on outside scheduled periods.
+# TYPE huidu_screen_default_on gauge
+# HELP huidu_screen_schedule_enabled Whether the on/off schedule is enabled.
+# TYPE huidu_screen_schedule_enabled gauge
+# HELP huidu_files Number of files stored on the controller.
+# TYPE huidu_files gauge
+# HELP huidu_file_storage_used_bytes Bytes stored on the controller. Free space is not reported by the protocol.
+# TYPE huidu_file_storage_used_bytes gauge
+# HELP huidu_file_storage_incomplete_bytes Bytes still missing from interrupted uploads.
+# TYPE huidu_file_storage_incomplete_bytes gauge
+# HELP huidu_heartbeat_rtt_seconds Last heartbeat round-trip time.
+# TYPE huidu_heartbeat_rtt_seconds gauge
+# HELP huidu_heartbeat_missed Consecutive unanswered heartbeats.
+# TYPE huidu_heartbeat_missed gauge
+# HELP huidu_disconnects_total Connection losses.
+# TYPE huidu_disconnects_total counter
+huidu_disconnects_total{device="hall"} 0
+huidu_disconnects_total{device="lobby \"A\"\\1\nfloor"} 1
+# HELP huidu_reconnects_total Successful automatic reconnects.
+# TYPE huidu_reconnects_total counter
+huidu_reconnects_total{device="hall"} 0
+huidu_reconnects_total{device="lobby \"A\"\\1\nfloor"} 1
+# HELP huidu_retries_total Commands retried after the controller rejected them as busy.
+# TYPE huidu_retries_total counter
+huidu_retries_total{device="hall"} 0
+huidu_retries_total{device="lobby \"A\"\\1\nfloor"} 2
+# HELP huidu_command_duration_seconds Command latency including retries.
+# TYPE huidu_command_duration_seconds histogram
+huidu_command_duration_seconds_bucket{device="hall",command="GetDeviceInfo",le="0.1"} 0
+huidu_command_duration_seconds_bucket{device="hall",command="GetDeviceInfo",le="1"} 1
+huidu_command_duration_seconds_bucket{device="hall",command="GetDeviceInfo",le="+Inf"} 1
+huidu_command_duration_seconds_sum{device="hall",command="GetDeviceInfo"} 1
+huidu_command_duration_seconds_count{device="hall",command="GetDeviceInfo"} 1
+huidu_command_duration_seconds_bucket{device="lobby \"A\"\\1\nfloor",command="GetLuminancePloy",le="0.1"} 2
+huidu_command_duration_seconds_bucket{device="lobby \"A\"\\1\nfloor",command="GetLuminancePloy",le="1"} 3
+huidu_command_duration_seconds_bucket{device="lobby \"A\"\\1\nfloor",command="GetLuminancePloy",le="+Inf"} 4
+huidu_command_duration_seconds_sum{device="lobby \"A\"\\1\nfloor",command="GetLuminancePloy"} 2.65
+huidu_command_duration_seconds_count{device="lobby \"A\"\\1\nfloor",command="GetLuminancePloy"} 4
+huidu_command_duration_seconds_bucket{device="lobby \"A\"\\1\nfloor",command="UploadFile",le="0.1"} 0
+huidu_command_duration_seconds_bucket{device="lobby \"A\"\\1\nfloor",command="UploadFile",le="1"} 1
+huidu_command_duration_seconds_bucket{device="lobby \"A\"\\1\nfloor",command="UploadFile",le="+Inf"} 2
+huidu_command_duration_seconds_sum{device="lobby \"A\"\\1\nfloor",command="UploadFile"} 2.5
+huidu_command_duration_seconds_count{device="lobby \"A\"\\1\nfloor",command="UploadFile"} 2
+# HELP huidu_command_errors_total Failed commands.
+# TYPE huidu_command_errors_total counter
+huidu_command_errors_total{device="hall",command="GetDeviceInfo"} 1
+huidu_command_errors_total{device="lobby \"A\"\\1\nfloor",command="GetLuminancePloy"} 0
+huidu_command_errors_total{device="lobby \"A\"\\1\nfloor",command="UploadFile"} 1
+# HELP huidu_upload_bytes_total Bytes uploaded.
+# TYPE huidu_upload_bytes_total counter
+huidu_upload_bytes_total{device="hall"} 0
+huidu_upload_bytes_total{device="lobby \"A\"\\1\nfloor"} 1000
+# HELP huidu_upload_seconds_total Time spent uploading.
+# TYPE huidu_upload_seconds_total counter
+huidu_upload_seconds_total{device="hall"} 0
+huidu_upload_seconds_total{device="lobby \"A\"\\1\nfloor"} 2
+# HELP huidu_upload_throughput_bytes_per_second Throughput of the last upload.
+# TYPE huidu_upload_throughput_bytes_per_second gauge
+huidu_upload_throughput_bytes_per_second{device="lobby \"A\"\\1\nfloor"} 500
+`
+
+func TestServeHTTPGolden(t *testing.T) {
+	exp := huiduprom.NewExporter(huiduprom.WithBuckets([]float64{0.1, 1}))
+
+	// Etiket kaçışı: ters bölü, tırnak ve satır sonu
+	const name = "lobby \"A\"\\1\nfloor"
+	exp.HandleEvent(name, huidu.Event{Type: huidu.EventDisconnected})
+	exp.HandleEvent(name, huidu.Event{Type: huidu.EventReconnected})
+	exp.HandleEvent(name, huidu.Event{Type: huidu.EventRetrying})
+	exp.HandleEvent(name, huidu.Event{Type: huidu.EventRetrying})
+
+	// Kovalar kümülatif: 0.05 ≤ 0.1, 0.1 ≤ 0.1, 0.5 ≤ 1, 2 yalnızca +Inf
+	for _, d := range []time.Duration{50 * time.Millisecond, 100 * time.Millisecond, 500 * time.Millisecond, 2 * time.Second} {
+		exp.ObserveCommand(name, huidu.CommandStats{Op: "GetLuminancePloy", Duration: d})
+	}
+	exp.ObserveCommand(name, huidu.CommandStats{Op: "UploadFile", Duration: 2 * time.Second, Bytes: 1000})
+	// Dolu kart yalnızca yükleme hatası olarak görünür; bayt sayılmaz
+	exp.ObserveCommand(name, huidu.CommandStats{Op: "UploadFile", Duration: 500 * time.Millisecond, Err: huidu.ErrNotSpaceToSave})
+	exp.ObserveCommand("hall", huidu.CommandStats{Op: "GetDeviceInfo", Duration: time.Second, Err: errors.New("zaman aşımı")})
+
+	rec := httptest.NewRecorder()
+	exp.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if rec.Code != 200 {
+		t.Fatalf("durum = %d", rec.Code)
+	}
+	if got, want := rec.Header().Get("Content-Type"), "text/plain; version=0.0.4; charset=utf-8"; got != want {
+		t.Errorf("Content-Type = %q, beklenen %q", got, want)
+	}
+	if got := rec.Body.String(); got != exposition {
+		t.Errorf("çıktı farklı\n--- alınan ---\n%s\n--- beklenen ---\n%s", got, exposition)
+	}
+}
@@ -0,0 +1,268 @@
+package huiduprom
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+)
+
+// ─── Metin Formatı ──────────────────────────────────────────────────────────────
+//
+// Prometheus metin formatı (0.0.4): her metrik ailesi için bir # HELP ve
+// # TYPE satırı, ardından "ad{etiket="değer"} sayı" satırları.
+
+// expo, metin formatı yazıcısıdır.
+type expo struct {
+	buf bytes.Buffer
+}
+
+// family, bir metrik ailesinin başlığını yazar.
+func (x *expo) family(name, typ, help string) {
+	fmt.Fprintf(&x.buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample, tek bir örnek yazar. labels ad/değer çiftleridir.
+func (x *expo) sample(name string, value float64, labels ...string) {
+	x.buf.WriteString(name)
+	if len(labels) > 0 {
+		x.buf.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				x.buf.WriteByte(',')
+			}
+			x.buf.WriteString(labels[i])
+			x.buf.WriteString(`="`)
+			x.buf.WriteString(labelEscaper.Replace(labels[i+1]))
+			x.buf.WriteByte('"')
+		}
+		x.buf.WriteByte('}')
+	}
+	x.buf.WriteByte(' ')
+	x.buf.WriteString(formatValue(value))
+	x.buf.WriteByte('\n')
+}
+
+// labelEscaper, etiket değerlerindeki özel karakterleri kaçışlar.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatValue, sayıyı metin formatına çevirir.
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, +1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// boolValue, bool değeri 0/1'e çevirir.
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// ─── Metrik Üretimi ─────────────────────────────────────────────────────────────
+
+// liveStats, cihazdan doğrudan okunan anlık değerlerdir.
+type liveStats struct {
+	connected bool
+	heartbeat huidu.HeartbeatStats
+}
+
+// Gather, tüm metrikleri Prometheus metin formatında döner.
+func (e *Exporter) Gather() []byte {
+	// Cihaz kilitleri, olay callback'leri e.mu'yu beklerken tutulmuş
+	// olabileceğinden anlık değerler e.mu dışında okunur.
+	e.mu.Lock()
+	devs := make(map[string]*huidu.Device, len(e.targets))
+	for name, t := range e.targets {
+		devs[name] = t.dev
+	}
+	e.mu.Unlock()
+
+	live := make(map[string]liveStats, len(devs))
+	for name, dev := range devs {
+		if dev != nil {
+			live[name] = liveStats{connected: dev.IsConnected(), heartbeat: dev.HeartbeatStats()}
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	names := make([]string, 0, len(e.targets))
+	for name := range e.targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	x := &expo{}
+	each := func(fn func(name string, t *target)) {
+		for _, name := range names {
+			fn(name, e.targets[name])
+		}
+	}
+	gauge := func(metric, help string, fn func(name string, t *target) (float64, bool)) {
+		x.family(metric, "gauge", help)
+		each(func(name string, t *target) {
+			if v, ok := fn(name, t); ok {
+				x.sample(metric, v, "device", name)
+			}
+		})
+	}
+	counter := func(metric, help string, fn func(t *target) float64) {
+		x.family(metric, "counter", help)
+		each(func(name string, t *target) { x.sample(metric, fn(t), "device", name) })
+	}
+
+	// Bağlantı ve sorgu durumu
+	gauge("huidu_up", "Whether the connection to the controller is up.", func(name string, t *target) (float64, bool) {
+		s, ok := live[name]
+		return boolValue(s.connected), ok
+	})
+	gauge("huidu_poll_success", "Whether every query of the last poll succeeded.", func(_ string, t *target) (float64, bool) {
+		return boolValue(t.pollErr == nil), t.polled
+	})
+	gauge("huidu_poll_duration_seconds", "Duration of the last poll.", func(_ string, t *target) (float64, bool) {
+		return t.pollDuration.Seconds(), t.polled
+	})
+	gauge("huidu_last_poll_timestamp_seconds", "Unix time of the last poll.", func(_ string, t *target) (float64, bool) {
+		return float64(t.lastPoll.UnixNano()) / 1e9, t.polled
+	})
+
+	// GetDeviceInfo
+	x.family("huidu_device_info", "gauge", "Controller identity and firmware versions.")
+	each(func(name string, t *target) {
+		if i := t.info; i != nil {
+			x.sample("huidu_device_info", 1, "device", name, "device_id", i.DeviceID, "model", i.Model,
+				"cpu", i.CPU, "firmware", i.AppVersion, "fpga", i.FPGAVersion, "kernel", i.KernelVersion)
+		}
+	})
+	gauge("huidu_screen_width_pixels", "Screen width reported by the controller.", func(_ string, t *target) (float64, bool) {
+		if t.info == nil {
+			return 0, false
+		}
+		return float64(t.info.ScreenWidth), true
+	})
+	gauge("huidu_screen_height_pixels", "Screen height reported by the controller.", func(_ string, t *target) (float64, bool) {
+		if t.info == nil {
+			return 0, false
+		}
+		return float64(t.info.ScreenHeight), true
+	})
+
+	// GetLuminanceInfo
+	gauge("huidu_brightness_percent", "Default brightness (1-100).", func(_ string, t *target) (float64, bool) {
+		if t.luminance == nil {
+			return 0, false
+		}
+		return float64(t.luminance.DefaultValue), true
+	})
+	gauge("huidu_brightness_mode", "Brightness mode: 0 default, 1 scheduled, 2 sensor.", func(_ string, t *target) (float64, bool) {
+		if t.luminance == nil {
+			return 0, false
+		}
+		return float64(t.luminance.Mode), true
+	})
+
+	// GetSwitchTimeInfo
+	gauge("huidu_screen_default_on", "Whether the screen is on outside scheduled periods.", func(_ string, t *target) (float64, bool) {
+		if t.switchTime == nil {
+			return 0, false
+		}
+		return boolValue(t.switchTime.OpenEnabled), true
+	})
+	gauge("huidu_screen_schedule_enabled", "Whether the on/off schedule is enabled.", func(_ string, t *target) (float64, bool) {
+		if t.switchTime == nil {
+			return 0, false
+		}
+		return boolValue(t.switchTime.PloyEnabled), true
+	})
+
+	// GetFileList
+	gauge("huidu_files", "Number of files stored on the controller.", func(_ string, t *target) (float64, bool) {
+		return float64(len(t.files)), t.filesOK
+	})
+	gauge("huidu_file_storage_used_bytes", "Bytes stored on the controller. Free space is not reported by the protocol.", func(_ string, t *target) (float64, bool) {
+		var used int64
+		for _, f := range t.files {
+			used += f.ExistSize
+		}
+		return float64(used), t.filesOK
+	})
+	gauge("huidu_file_storage_incomplete_bytes", "Bytes still missing from interrupted uploads.", func(_ string, t *target) (float64, bool) {
+		var missing int64
+		for _, f := range t.files {
+			if f.ExistSize < f.Size {
+				missing += f.Size - f.ExistSize
+			}
+		}
+		return float64(missing), t.filesOK
+	})
+
+	// Heartbeat
+	gauge("huidu_heartbeat_rtt_seconds", "Last heartbeat round-trip time.", func(name string, t *target) (float64, bool) {
+		s, ok := live[name]
+		return s.heartbeat.RTT.Seconds(), ok && s.connected && s.heartbeat.RTT > 0
+	})
+	gauge("huidu_heartbeat_missed", "Consecutive unanswered heartbeats.", func(name string, t *target) (float64, bool) {
+		s, ok := live[name]
+		return float64(s.heartbeat.Missed), ok && s.connected
+	})
+
+	// Olaylar
+	counter("huidu_disconnects_total", "Connection losses.", func(t *target) float64 { return float64(t.disconnects) })
+	counter("huidu_reconnects_total", "Successful automatic reconnects.", func(t *target) float64 { return float64(t.reconnects) })
+	counter("huidu_retries_total", "Commands retried after the controller rejected them as busy.", func(t *target) float64 { return float64(t.retries) })
+
+	// Komut gecikmeleri
+	x.family("huidu_command_duration_seconds", "histogram", "Command latency including retries.")
+	each(func(name string, t *target) {
+		for _, op := range sortedOps(t) {
+			m := t.commands[op]
+			var cum uint64
+			for i, le := range e.buckets {
+				cum += m.counts[i]
+				x.sample("huidu_command_duration_seconds_bucket", float64(cum), "device", name, "command", op, "le", formatValue(le))
+			}
+			x.sample("huidu_command_duration_seconds_bucket", float64(m.count), "device", name, "command", op, "le", "+Inf")
+			x.sample("huidu_command_duration_seconds_sum", m.sum, "device", name, "command", op)
+			x.sample("huidu_command_duration_seconds_count", float64(m.count), "device", name, "command", op)
+		}
+	})
+	x.family("huidu_command_errors_total", "counter", "Failed commands.")
+	each(func(name string, t *target) {
+		for _, op := range sortedOps(t) {
+			x.sample("huidu_command_errors_total", float64(t.commands[op].errors), "device", name, "command", op)
+		}
+	})
+
+	// Yükleme
+	counter("huidu_upload_bytes_total", "Bytes uploaded.", func(t *target) float64 { return float64(t.uploadBytes) })
+	counter("huidu_upload_seconds_total", "Time spent uploading.", func(t *target) float64 { return t.uploadSeconds })
+	gauge("huidu_upload_throughput_bytes_per_second", "Throughput of the last upload.", func(_ string, t *target) (float64, bool) {
+		return t.uploadRate, t.uploadRate > 0
+	})
+
+	return x.buf.Bytes()
+}
+
+// sortedOps, hedefin komut adlarını sıralı döner.
+func sortedOps(t *target) []string {
+	ops := make([]string, 0, len(t.commands))
+	for op := range t.commands {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	return ops
+}
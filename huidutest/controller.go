@@ -0,0 +1,572 @@
+package huidutest
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+)
+
+// ─── Sahte Kontrol Kartı ────────────────────────────────────────────────────────
+
+// Controller, bellek içi durum tutan sahte bir Huidu kontrol kartıdır.
+// Aynı anda birden fazla bağlantıya hizmet verebilir; tüm bağlantılar
+// aynı durumu paylaşır (gerçek kartta olduğu gibi).
+type Controller struct {
+	mu sync.Mutex
+
+	// info, GetDeviceInfo ile döndürülen cihaz bilgisidir.
+	info huidu.DeviceInfo
+
+	// settings, Set<X>/Get<X> metot çiftlerinin iç XML'leridir (anahtar: X).
+	settings map[string]string
+
+	// programs, ekrandaki programların ham XML'leridir (sırayla).
+	programs []program
+
+	// realtime, oynatılan realtime programın GUID'idir (yoksa boş).
+	// SetPlayTypeToNormal ile temizlenir.
+	realtime string
+
+	// screenOn, OpenScreen/CloseScreen durumudur.
+	screenOn bool
+
+	// files, yüklenmiş (veya yarım kalmış) dosyalardır.
+	files map[string]*File
+
+	// requests, alınan tüm SDK isteklerinin kaydıdır.
+	requests []Request
+
+	// sessions, GetIFVersion ile verilen GUID sayacıdır.
+	sessions int
+
+	// faults, Inject ile eklenen hata kurallarıdır (bkz. Fault).
+	faults []*faultRule
+
+	ln     net.Listener
+	conns  map[net.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// program, ekrandaki tek bir programın kaydıdır.
+type program struct {
+	guid     string
+	realtime bool // flag="realtime"
+	xml      string
+}
+
+// File, sahte karta yüklenmiş bir dosyadır.
+type File struct {
+	Name     string         // Dosya adı
+	Type     huidu.FileType // Yükleme sırasında bildirilen dosya tipi
+	MD5      string         // Bildirilen MD5 (hex)
+	Size     int64          // Bildirilen toplam boyut
+	Data     []byte         // Alınan içerik
+	Complete bool           // CmdFileEndAsk ile tamamlandı ve MD5 doğrulandı mı
+}
+
+// Request, sahte kartın aldığı bir SDK isteğidir.
+type Request struct {
+	Method   string    // Metot adı (ör. "SetLuminancePloy")
+	GUID     string    // İstekteki oturum GUID'i
+	InnerXML string    // <in> elemanının iç XML'i
+	RawXML   string    // İsteğin tamamı
+	Time     time.Time // Alınma zamanı
+}
+
+// Option, Controller yapılandırma seçeneğidir.
+type Option func(*Controller)
+
+// WithDeviceInfo, GetDeviceInfo ile döndürülecek cihaz bilgisini ayarlar.
+func WithDeviceInfo(info huidu.DeviceInfo) Option {
+	return func(c *Controller) {
+		c.info = info
+	}
+}
+
+// WithSetting, bir Get<key> metodunun döndüreceği iç XML'i önceden ayarlar.
+//
+//	huidutest.WithSetting("Eth0Info", `<eth valid="true"><enable value="true"/></eth>`)
+func WithSetting(key, innerXML string) Option {
+	return func(c *Controller) {
+		c.settings[key] = innerXML
+	}
+}
+
+// DefaultDeviceInfo, seçenek verilmediğinde kullanılan cihaz bilgisidir.
+var DefaultDeviceInfo = huidu.DeviceInfo{
+	CPU:           "Simulator",
+	Model:         "HD-SIM",
+	DeviceID:      "SIM-0000001",
+	DeviceName:    "huidutest",
+	FPGAVersion:   "1.0",
+	AppVersion:    "7.0.0.0",
+	KernelVersion: "4.0",
+	ScreenWidth:   128,
+	ScreenHeight:  64,
+}
+
+// NewController, varsayılan durumla yeni bir sahte kart oluşturur.
+// Bağlantı kabul etmek için Start, Pipe veya Serve kullanılmalıdır.
+func NewController(options ...Option) *Controller {
+	c := &Controller{
+		info:     DefaultDeviceInfo,
+		settings: make(map[string]string),
+		screenOn: true,
+		files:    make(map[string]*File),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	c.settings["LuminancePloy"] = `<mode value="default"/><default value="100"/><ploy></ploy><sensor min="1" max="100" time="10"/>`
+	c.settings["SwitchTime"] = `<open enable="true"/><ploy enable="false"/>`
+	for _, opt := range options {
+		opt(c)
+	}
+	return c
+}
+
+// ─── Bağlantı Yönetimi ──────────────────────────────────────────────────────────
+
+// Start, 127.0.0.1 üzerinde rastgele bir TCP portunu dinlemeye başlar.
+// Adres Host/Port/Addr ile alınır.
+func (c *Controller) Start() error {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("dinleme başlatılamadı: %w", err)
+	}
+
+	c.mu.Lock()
+	c.ln = ln
+	c.mu.Unlock()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			c.goServe(conn)
+		}
+	}()
+	return nil
+}
+
+// Addr, Start ile açılan dinleme adresini "host:port" olarak döner.
+func (c *Controller) Addr() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ln == nil {
+		return ""
+	}
+	return c.ln.Addr().String()
+}
+
+// Host, Start ile açılan dinleme adresinin IP'sini döner.
+func (c *Controller) Host() string {
+	host, _, _ := net.SplitHostPort(c.Addr())
+	return host
+}
+
+// Port, Start ile açılan dinleme portunu döner.
+func (c *Controller) Port() int {
+	_, port, _ := net.SplitHostPort(c.Addr())
+	p, _ := strconv.Atoi(port)
+	return p
+}
+
+// Pipe, net.Pipe ile bellek içi bir bağlantı oluşturur; bir ucu sahte kart
+// tarafından servis edilir, diğer ucu döner. huidu.NewDeviceFromConn ile
+// kullanılır.
+func (c *Controller) Pipe() net.Conn {
+	client, server := net.Pipe()
+	c.goServe(server)
+	return client
+}
+
+// Serve, verilen bağlantıya kapanana kadar sahte kart olarak hizmet verir.
+// Özel taşıma katmanlarıyla test için kullanılır; bağlantı kapanınca döner.
+func (c *Controller) Serve(conn net.Conn) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		conn.Close()
+		return
+	}
+	c.conns[conn] = struct{}{}
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.conns, conn)
+		c.mu.Unlock()
+		conn.Close()
+	}()
+
+	s := &session{c: c, conn: conn}
+	s.run()
+}
+
+// goServe, Serve'ü arka planda başlatır. Kart kapatıldıysa bağlantıyı
+// kapatır; böylece Close'un beklediği goroutine sayısı artmaz.
+func (c *Controller) goServe(conn net.Conn) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		conn.Close()
+		return
+	}
+	c.wg.Add(1)
+	c.mu.Unlock()
+	go func() {
+		defer c.wg.Done()
+		c.Serve(conn)
+	}()
+}
+
+// DropConnections, açık tüm bağlantıları kapatır (kablo çekilmiş gibi).
+// Dinleme devam eder; istemci yeniden bağlanabilir.
+func (c *Controller) DropConnections() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for conn := range c.conns {
+		conn.Close()
+	}
+}
+
+// Close, dinlemeyi ve tüm bağlantıları kapatır.
+func (c *Controller) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	if c.ln != nil {
+		c.ln.Close()
+	}
+	for conn := range c.conns {
+		conn.Close()
+	}
+	c.mu.Unlock()
+
+	c.wg.Wait()
+	return nil
+}
+
+// ─── Durum Erişimi ──────────────────────────────────────────────────────────────
+
+// DeviceInfo, kartın döndürdüğü cihaz bilgisidir.
+func (c *Controller) DeviceInfo() huidu.DeviceInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.info
+}
+
+// SetDeviceInfo, GetDeviceInfo ile döndürülecek cihaz bilgisini değiştirir.
+func (c *Controller) SetDeviceInfo(info huidu.DeviceInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.info = info
+}
+
+// Setting, Set<key> ile yazılmış (veya varsayılan) iç XML'i döner.
+// Örn. Setting("Eth0Info"), SetEth0Info ile gönderilen XML'dir.
+func (c *Controller) Setting(key string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.settings[key]
+}
+
+// SetSetting, Get<key> metodunun döndüreceği iç XML'i ayarlar.
+func (c *Controller) SetSetting(key, innerXML string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.settings[key] = innerXML
+}
+
+// Luminance, kartın güncel parlaklık ayarlarını döner.
+func (c *Controller) Luminance() huidu.LuminanceInfo {
+	var doc struct {
+		Mode struct {
+			Value string `xml:"value,attr"`
+		} `xml:"mode"`
+		Default struct {
+			Value int `xml:"value,attr"`
+		} `xml:"default"`
+		Items []struct {
+			Enable  bool   `xml:"enable,attr"`
+			Start   string `xml:"start,attr"`
+			Percent int    `xml:"percent,attr"`
+		} `xml:"ploy>item"`
+		Sensor struct {
+			Min  int `xml:"min,attr"`
+			Max  int `xml:"max,attr"`
+			Time int `xml:"time,attr"`
+		} `xml:"sensor"`
+	}
+	unmarshalInner(c.Setting("LuminancePloy"), &doc)
+
+	info := huidu.LuminanceInfo{
+		DefaultValue: doc.Default.Value,
+		SensorMin:    doc.Sensor.Min,
+		SensorMax:    doc.Sensor.Max,
+		SensorTime:   doc.Sensor.Time,
+	}
+	switch doc.Mode.Value {
+	case "ploys":
+		info.Mode = 1
+	case "sensor":
+		info.Mode = 2
+	}
+	for _, it := range doc.Items {
+		info.CustomItems = append(info.CustomItems, huidu.LuminanceItem{
+			Enabled: it.Enable,
+			Start:   it.Start,
+			Percent: it.Percent,
+		})
+	}
+	return info
+}
+
+// SwitchTime, kartın güncel zamanlı açma/kapama ayarlarını döner.
+func (c *Controller) SwitchTime() huidu.SwitchTimeInfo {
+	var doc struct {
+		Open struct {
+			Enable bool `xml:"enable,attr"`
+		} `xml:"open"`
+		Ploy struct {
+			Enable bool `xml:"enable,attr"`
+			Items  []struct {
+				Enable bool   `xml:"enable,attr"`
+				Start  string `xml:"start,attr"`
+				End    string `xml:"end,attr"`
+			} `xml:"item"`
+		} `xml:"ploy"`
+	}
+	unmarshalInner(c.Setting("SwitchTime"), &doc)
+
+	info := huidu.SwitchTimeInfo{
+		OpenEnabled: doc.Open.Enable,
+		PloyEnabled: doc.Ploy.Enable,
+	}
+	for _, it := range doc.Ploy.Items {
+		info.Items = append(info.Items, huidu.SwitchTimeItem{
+			Enabled: it.Enable,
+			Start:   it.Start,
+			End:     it.End,
+		})
+	}
+	return info
+}
+
+// ScreenOn, ekranın açık olup olmadığını döner (OpenScreen/CloseScreen).
+func (c *Controller) ScreenOn() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.screenOn
+}
+
+// Screen, ekrandaki programlardan oluşan güncel <screen> XML'ini döner.
+// AddProgram ekranı değiştirir, UpdateProgram aynı GUID'li programı
+// günceller, DeleteProgram programları kaldırır.
+func (c *Controller) Screen() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.screenXMLLocked()
+}
+
+// ProgramGUIDs, ekrandaki programların GUID'lerini sırasıyla döner.
+func (c *Controller) ProgramGUIDs() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	guids := make([]string, len(c.programs))
+	for i, p := range c.programs {
+		guids[i] = p.guid
+	}
+	return guids
+}
+
+// PlayingGUID, GetCurrentPlayProgramGUID ile döndürülecek GUID'dir:
+// realtime bir program gönderildiyse o, değilse ilk program. Ekran boşsa
+// boş döner.
+func (c *Controller) PlayingGUID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.playingLocked()
+}
+
+// File, adı verilen dosyanın bir kopyasını döner.
+func (c *Controller) File(name string) (File, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f, ok := c.files[name]
+	if !ok {
+		return File{}, false
+	}
+	cp := *f
+	cp.Data = append([]byte(nil), f.Data...)
+	return cp, true
+}
+
+// Files, karttaki tüm dosyaların kopyalarını döner (yarım kalanlar dahil).
+func (c *Controller) Files() map[string]File {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make(map[string]File, len(c.files))
+	for name, f := range c.files {
+		cp := *f
+		cp.Data = append([]byte(nil), f.Data...)
+		out[name] = cp
+	}
+	return out
+}
+
+// PutFile, karta tamamlanmış bir dosya ekler (ör. GetFiles testleri için).
+func (c *Controller) PutFile(name string, data []byte, fileType huidu.FileType) {
+	sum := md5.Sum(data)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.files[name] = &File{
+		Name:     name,
+		Type:     fileType,
+		MD5:      hex.EncodeToString(sum[:]),
+		Size:     int64(len(data)),
+		Data:     append([]byte(nil), data...),
+		Complete: true,
+	}
+}
+
+// Requests, alınan tüm SDK isteklerini sırasıyla döner.
+func (c *Controller) Requests() []Request {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Request(nil), c.requests...)
+}
+
+// LastRequest, verilen metoda ait son isteği döner.
+func (c *Controller) LastRequest(method huidu.SdkMethod) (Request, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := len(c.requests) - 1; i >= 0; i-- {
+		if c.requests[i].Method == string(method) {
+			return c.requests[i], true
+		}
+	}
+	return Request{}, false
+}
+
+// ClearRequests, istek kaydını temizler.
+func (c *Controller) ClearRequests() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = nil
+}
+
+// ─── Kendiliğinden Gönderilen Paketler ──────────────────────────────────────────
+
+// PushGPS, GPS raporlaması açıksa (SetGpsRespondEnable) konumu açık tüm
+// bağlantılara CmdGPSInfoAnswer paketi olarak gönderir ve paketin
+// gönderildiği bağlantı sayısını döner. Raporlama kapalıysa hiçbir şey
+// gönderilmez (gerçek kartta olduğu gibi).
+func (c *Controller) PushGPS(pos huidu.GPSPosition) int {
+	c.mu.Lock()
+	enabled := strings.Contains(c.settings["GpsRespondEnable"], `value="true"`)
+	conns := make([]net.Conn, 0, len(c.conns))
+	for conn := range c.conns {
+		conns = append(conns, conn)
+	}
+	c.mu.Unlock()
+
+	if !enabled {
+		return 0
+	}
+	pkt := frame(huidu.CmdGPSInfoAnswer, gpsPayload(pos))
+	sent := 0
+	for _, conn := range conns {
+		if _, err := conn.Write(pkt); err == nil {
+			sent++
+		}
+	}
+	return sent
+}
+
+// gpsPayload, konumu CmdGPSInfoAnswer veri bölümü olarak kodlar
+// (format: huidu.ParseGPSPosition).
+func gpsPayload(pos huidu.GPSPosition) []byte {
+	p := make([]byte, 35)
+	p[0], p[1] = 'E', 'N'
+	lon, lat := pos.Longitude, pos.Latitude
+	if lon < 0 {
+		p[0], lon = 'W', -lon
+	}
+	if lat < 0 {
+		p[1], lat = 'S', -lat
+	}
+	binary.LittleEndian.PutUint64(p[2:10], math.Float64bits(lon))
+	binary.LittleEndian.PutUint64(p[10:18], math.Float64bits(lat))
+	binary.LittleEndian.PutUint32(p[18:22], math.Float32bits(float32(pos.Speed)))
+	binary.LittleEndian.PutUint32(p[22:26], math.Float32bits(float32(pos.Course)))
+	if !pos.Time.IsZero() {
+		t := pos.Time.UTC()
+		binary.LittleEndian.PutUint16(p[26:28], uint16(t.Year()))
+		p[28], p[29], p[30] = byte(t.Month()), byte(t.Day()), byte(t.Hour())
+		p[31], p[32] = byte(t.Minute()), byte(t.Second())
+	}
+	if pos.Fix {
+		p[33] = 1
+	}
+	p[34] = byte(pos.Satellites)
+	return p
+}
+
+// ─── Dahili Yardımcılar ─────────────────────────────────────────────────────────
+
+// screenXMLLocked, programlardan <screen> XML'i oluşturur (mu tutulurken).
+func (c *Controller) screenXMLLocked() string {
+	var b strings.Builder
+	b.WriteString("<screen>")
+	for _, p := range c.programs {
+		b.WriteString(p.xml)
+	}
+	b.WriteString("</screen>")
+	return b.String()
+}
+
+// playingLocked, oynatılan programın GUID'ini döner (mu tutulurken).
+func (c *Controller) playingLocked() string {
+	if c.realtime != "" {
+		return c.realtime
+	}
+	if len(c.programs) > 0 {
+		return c.programs[0].guid
+	}
+	return ""
+}
+
+// noteRealtimeLocked, gelen programlar arasında realtime olanı oynatmaya alır
+// ve realtime program kalmadıysa kaydı temizler (mu tutulurken).
+func (c *Controller) noteRealtimeLocked(progs []program) {
+	for _, p := range progs {
+		if p.realtime {
+			c.realtime = p.guid
+		}
+	}
+	for _, p := range c.programs {
+		if p.guid == c.realtime {
+			return
+		}
+	}
+	c.realtime = ""
+}
+
+// unmarshalInner, kök elemanı olmayan iç XML'i v'ye ayrıştırır.
+func unmarshalInner(inner string, v interface{}) {
+	xml.Unmarshal([]byte("<in>"+inner+"</in>"), v)
+}
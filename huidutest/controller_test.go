@@ -0,0 +1,214 @@
+package huidutest_test
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+	"github.com/alparslanahmed/huidu-led/huidutest"
+)
+
+// connect, ctrl'a net.Pipe üzerinden bağlı bir Device döner.
+func connect(t *testing.T, ctrl *huidutest.Controller) *huidu.Device {
+	t.Helper()
+	dev := huidu.NewDeviceFromConn(ctrl.Pipe())
+	if err := dev.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { dev.Close() })
+	return dev
+}
+
+// methods, kaydedilen isteklerin metot adlarını döner.
+func methods(reqs []huidutest.Request) []string {
+	names := make([]string, len(reqs))
+	for i, r := range reqs {
+		names[i] = r.Method
+	}
+	return names
+}
+
+func TestConnectHandshake(t *testing.T) {
+	info := huidutest.DefaultDeviceInfo
+	info.DeviceID = "C16-TEST-42"
+	info.ScreenWidth, info.ScreenHeight = 256, 32
+	ctrl := huidutest.NewController(huidutest.WithDeviceInfo(info))
+	defer ctrl.Close()
+
+	dev := connect(t, ctrl)
+
+	if got := dev.CachedDeviceInfo(); got == nil || *got != info {
+		t.Fatalf("CachedDeviceInfo = %+v, beklenen %+v", got, info)
+	}
+	if dev.GUID() == "" {
+		t.Fatal("handshake sonrası GUID boş")
+	}
+
+	want := []string{"GetIFVersion", "GetDeviceInfo"}
+	if got := methods(ctrl.Requests()); !reflect.DeepEqual(got, want) {
+		t.Fatalf("istekler = %v, beklenen %v", got, want)
+	}
+}
+
+func TestConnectOverTCP(t *testing.T) {
+	ctrl := huidutest.NewController()
+	defer ctrl.Close()
+	if err := ctrl.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	dev := huidu.NewDevice(ctrl.Host(), ctrl.Port())
+	if err := dev.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer dev.Close()
+
+	got, err := dev.GetDeviceInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != huidutest.DefaultDeviceInfo {
+		t.Fatalf("GetDeviceInfo = %+v", got)
+	}
+}
+
+func TestSetBrightnessRecordsRequest(t *testing.T) {
+	ctrl := huidutest.NewController()
+	defer ctrl.Close()
+	dev := connect(t, ctrl)
+	ctrl.ClearRequests()
+
+	if err := dev.SetBrightness(40); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ctrl.Luminance().DefaultValue; got != 40 {
+		t.Fatalf("kart parlaklığı = %d, beklenen 40", got)
+	}
+	req, ok := ctrl.LastRequest(huidu.MethodSetLuminancePloy)
+	if !ok {
+		t.Fatalf("SetLuminancePloy kaydedilmedi: %v", methods(ctrl.Requests()))
+	}
+	if req.GUID != dev.GUID() {
+		t.Errorf("istek GUID = %q, beklenen %q", req.GUID, dev.GUID())
+	}
+	if !strings.Contains(req.InnerXML, `<default value="40"/>`) {
+		t.Errorf("InnerXML = %s", req.InnerXML)
+	}
+
+	info, err := dev.GetLuminanceInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.DefaultValue != 40 {
+		t.Fatalf("GetLuminanceInfo.DefaultValue = %d, beklenen 40", info.DefaultValue)
+	}
+}
+
+func TestSettingsRoundTrip(t *testing.T) {
+	ctrl := huidutest.NewController(huidutest.WithSetting("Eth0Info",
+		`<eth valid="true"><enable value="true"/><dhcp auto="false"/><address ip="10.0.0.5" netmask="255.255.255.0" gateway="10.0.0.1" dns="8.8.8.8"/></eth>`))
+	defer ctrl.Close()
+	dev := connect(t, ctrl)
+
+	eth, err := dev.GetEthernetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := huidu.EthernetInfo{Enabled: true, IP: "10.0.0.5", Netmask: "255.255.255.0", Gateway: "10.0.0.1", DNS: "8.8.8.8"}
+	if *eth != want {
+		t.Fatalf("GetEthernetInfo = %+v, beklenen %+v", *eth, want)
+	}
+
+	eth.IP = "10.0.0.6"
+	if err := dev.SetEthernetInfo(eth); err != nil {
+		t.Fatal(err)
+	}
+	if s := ctrl.Setting("Eth0Info"); !strings.Contains(s, `ip="10.0.0.6"`) {
+		t.Fatalf("kart ayarı güncellenmedi: %s", s)
+	}
+
+	sw := &huidu.SwitchTimeInfo{
+		OpenEnabled: true,
+		PloyEnabled: true,
+		Items:       []huidu.SwitchTimeItem{{Enabled: true, Start: "08:00:00", End: "22:00:00"}},
+	}
+	if err := dev.SetSwitchTimeInfo(sw); err != nil {
+		t.Fatal(err)
+	}
+	if got := ctrl.SwitchTime(); !reflect.DeepEqual(got, *sw) {
+		t.Fatalf("kart zamanlaması = %+v, beklenen %+v", got, *sw)
+	}
+
+	if err := dev.CloseScreen(); err != nil {
+		t.Fatal(err)
+	}
+	if ctrl.ScreenOn() {
+		t.Fatal("CloseScreen sonrası ekran açık")
+	}
+}
+
+func TestUploadDownloadAndFileList(t *testing.T) {
+	ctrl := huidutest.NewController()
+	defer ctrl.Close()
+	dev := connect(t, ctrl)
+
+	// Birden fazla içerik paketi gerektiren bir dosya
+	data := bytes.Repeat([]byte("huidutest-"), 2*huidu.MaxContentLength/10+7)
+	sum := md5.Sum(data)
+	if err := dev.UploadFileData("clip.mp4", data, huidu.FileTypeAuto); err != nil {
+		t.Fatalf("UploadFileData: %v", err)
+	}
+
+	f, ok := ctrl.File("clip.mp4")
+	if !ok || !f.Complete {
+		t.Fatalf("dosya tamamlanmadı: %+v", f)
+	}
+	if !bytes.Equal(f.Data, data) || f.MD5 != hex.EncodeToString(sum[:]) || f.Type != huidu.FileTypeVideo {
+		t.Fatalf("kartta dosya = %d byte, MD5 %s, tip %d", len(f.Data), f.MD5, f.Type)
+	}
+
+	files, err := dev.GetFileList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name != "clip.mp4" || files[0].Size != int64(len(data)) ||
+		files[0].ExistSize != int64(len(data)) || !strings.EqualFold(files[0].MD5, f.MD5) {
+		t.Fatalf("GetFileList = %+v", files)
+	}
+
+	var buf bytes.Buffer
+	if err := dev.DownloadFile("clip.mp4", &buf); err != nil {
+		t.Fatalf("DownloadFile: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatalf("indirilen içerik farklı (%d/%d byte)", buf.Len(), len(data))
+	}
+
+	if err := dev.DeleteFiles("clip.mp4"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ctrl.File("clip.mp4"); ok {
+		t.Fatal("DeleteFiles sonrası dosya duruyor")
+	}
+}
+
+func TestPutFileIsListed(t *testing.T) {
+	ctrl := huidutest.NewController()
+	defer ctrl.Close()
+	ctrl.PutFile("logo.png", []byte("png"), huidu.FileTypeImage)
+	dev := connect(t, ctrl)
+
+	files, err := dev.GetFileList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := md5.Sum([]byte("png"))
+	if len(files) != 1 || files[0].Name != "logo.png" || !strings.EqualFold(files[0].MD5, hex.EncodeToString(sum[:])) {
+		t.Fatalf("GetFileList = %+v", files)
+	}
+}
@@ -0,0 +1,33 @@
+// Package huidutest provides an in-process fake Huidu LED controller for
+// testing code built on the huidu package without real hardware.
+//
+// The Controller speaks the real binary protocol: transport version
+// negotiation (0x2001), fragmented SDK XML commands (0x2003/0x2004),
+// heartbeats (0x005f/0x0060) and the file upload sequence
+// (0x8001/0x8003/0x8005). It keeps in-memory state for device info,
+// brightness, switch times, other Set*/Get* settings, uploaded files and the
+// current screen, and records every SDK request so tests can assert what
+// was sent.
+//
+// It runs over loopback TCP or net.Pipe:
+//
+//	ctrl := huidutest.NewController()
+//	defer ctrl.Close()
+//
+//	// Over loopback TCP
+//	if err := ctrl.Start(); err != nil {
+//	    t.Fatal(err)
+//	}
+//	dev := huidu.NewDevice(ctrl.Host(), ctrl.Port())
+//
+//	// Or over net.Pipe, with no sockets at all
+//	dev = huidu.NewDeviceFromConn(ctrl.Pipe())
+//
+//	if err := dev.Connect(); err != nil {
+//	    t.Fatal(err)
+//	}
+//	dev.SetBrightness(40)
+//	if got := ctrl.Luminance().DefaultValue; got != 40 {
+//	    t.Fatalf("brightness = %d", got)
+//	}
+package huidutest
@@ -0,0 +1,173 @@
+package huidutest
+
+import (
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+)
+
+// ─── Hata Enjeksiyonu ───────────────────────────────────────────────────────────
+//
+// Sahada görülen hatalar, sahte karta eklenen Fault kurallarıyla
+// deterministik olarak tekrar üretilir: paket kaybı, gecikme, header
+// ortasından bölünmüş TCP segmentleri, sırası bozulmuş SDK parçaları,
+// CmdErrorAnswer kodları, zorlanmış resume offset'leri ve yükleme
+// sırasında kopan bağlantılar.
+//
+// Gelen her paket için kurallar eklenme sırasıyla denenir; eşleşen ve
+// hakkı bitmemiş ilk kural uygulanır. SDK komutlarında eşleştirme parçalar
+// birleştirildikten sonra, metot adı bilinirken yapılır.
+//
+//	ctrl.Inject(
+//	    // İlk SetLuminancePloy isteği cevapsız kalsın (istemci zaman aşımı)
+//	    huidutest.Fault{On: huidu.CmdSdkCmdAsk, Method: huidu.MethodSetLuminancePloy, Drop: true},
+//	    // Üçüncü içerik paketinde bağlantı kopsun
+//	    huidutest.Fault{On: huidu.CmdFileContentAsk, Skip: 2, Disconnect: true},
+//	)
+
+// Fault, tek bir hata enjeksiyonu kuralıdır. Eşleştirme alanları kuralın
+// hangi pakete uygulanacağını, diğer alanlar ne yapılacağını belirler.
+// Sıfır değerli alanlar etkisizdir.
+type Fault struct {
+	// On, kuralı tetikleyen gelen komut tipidir (0: herhangi bir komut).
+	On huidu.CmdType
+
+	// Method, CmdSdkCmdAsk için metot filtresidir (boş: herhangi bir metot).
+	Method huidu.SdkMethod
+
+	// Skip, kural uygulanmadan önce atlanacak eşleşme sayısıdır.
+	// Örn. Skip: 2 ile kural üçüncü eşleşen pakette devreye girer.
+	Skip int
+
+	// Times, kuralın kaç kez uygulanacağıdır (0: bir kez, <0: sınırsız).
+	Times int
+
+	// Disconnect, paket işlenmeden bağlantıyı kapatır.
+	Disconnect bool
+
+	// Drop, paketi işlemeden ve yanıtlamadan yutar. Kart durumu değişmez.
+	Drop bool
+
+	// Delay, yanıttan önce beklenecek süredir.
+	Delay time.Duration
+
+	// ErrorAnswer, paket işlenmeden CmdErrorAnswer ile bu kod döner
+	// (ör. huidu.ErrDeviceOccupied).
+	ErrorAnswer huidu.ErrorCode
+
+	// FileError, CmdFileStartAnswer/CmdFileEndAnswer/CmdReadFileAnswer
+	// yanıtının hata alanına yazılacak koddur (ör. huidu.ErrNotSpaceToSave,
+	// huidu.ErrReadFileExcessive). Kod sıfırdan farklıysa dosya durumu değişmez.
+	FileError huidu.ErrorCode
+
+	// ResumeAt, CmdFileStartAnswer'da bildirilecek mevcut bayt sayısıdır
+	// (0: gerçek değer). Karttaki yarım veri bu uzunluğa kısaltılır; daha
+	// kısaysa olduğu gibi bırakılır ve eksik kalan dosya kFileEndAnswer'da
+	// huidu.ErrFileNotFinish ile reddedilir.
+	ResumeAt int64
+
+	// SdkResult, SDK isteği işlenmeden yanıtın result değeri olarak döner
+	// (ör. "kInvalidXmlIndex").
+	SdkResult string
+
+	// SplitAt, yanıt çerçevelerini bu bayt konumundan iki ayrı yazmaya böler.
+	// 2 verilirse uzunluk alanı ile komut tipi ayrı segmentlerde gelir.
+	SplitAt int
+
+	// SplitGap, bölünmüş parçalar arasındaki beklemedir (0: 5ms).
+	SplitGap time.Duration
+
+	// FragmentSize, SDK yanıtının parça boyutudur (0: huidu.MaxContentLength).
+	FragmentSize int
+
+	// Reorder, SDK yanıt parçalarını ters sırada gönderir.
+	Reorder bool
+}
+
+// defaultSplitGap, bölünmüş segmentlerin ayrı okunması için beklenen süredir.
+const defaultSplitGap = 5 * time.Millisecond
+
+// faultRule, bir kuralın çalışma zamanı sayaçlarıdır.
+type faultRule struct {
+	Fault
+	seen  int
+	fired int
+}
+
+// exhausted, kuralın uygulama hakkının bitip bitmediğini döner.
+func (r *faultRule) exhausted() bool {
+	limit := r.Times
+	if limit == 0 {
+		limit = 1
+	}
+	return limit > 0 && r.fired >= limit
+}
+
+// WithFaults, kontrol kartını baştan verilen hata kurallarıyla oluşturur.
+func WithFaults(faults ...Fault) Option {
+	return func(c *Controller) {
+		c.addFaultsLocked(faults)
+	}
+}
+
+// Inject, hata kurallarını mevcut planın sonuna ekler.
+// Açık bağlantılar dahil sonraki tüm paketlere uygulanır.
+func (c *Controller) Inject(faults ...Fault) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.addFaultsLocked(faults)
+}
+
+// ClearFaults, tüm hata kurallarını kaldırır.
+func (c *Controller) ClearFaults() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.faults = nil
+}
+
+// PendingFaults, hakkı henüz bitmemiş kural sayısını döner. Sınırsız
+// kurallar her zaman bekleyen sayılır. Testin sonunda tüm kuralların
+// tetiklendiğini doğrulamak için kullanılır.
+func (c *Controller) PendingFaults() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, r := range c.faults {
+		if !r.exhausted() {
+			n++
+		}
+	}
+	return n
+}
+
+// addFaultsLocked, kuralları plana ekler (mu tutulurken).
+func (c *Controller) addFaultsLocked(faults []Fault) {
+	for _, f := range faults {
+		c.faults = append(c.faults, &faultRule{Fault: f})
+	}
+}
+
+// matchFault, gelen pakete uygulanacak kuralı döner (yoksa nil).
+func (c *Controller) matchFault(cmd huidu.CmdType, method string) *Fault {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, r := range c.faults {
+		if r.exhausted() {
+			continue
+		}
+		if r.On != 0 && r.On != cmd {
+			continue
+		}
+		if r.Method != "" && string(r.Method) != method {
+			continue
+		}
+		r.seen++
+		if r.seen <= r.Skip {
+			continue
+		}
+		r.fired++
+		f := r.Fault
+		return &f
+	}
+	return nil
+}
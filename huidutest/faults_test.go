@@ -0,0 +1,652 @@
+package huidutest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+	"github.com/alparslanahmed/huidu-led/huidutest"
+)
+
+// tapConn, istemcinin okuduğu tüm byte'ları kaydeder; böylece kartın
+// gönderdiği çerçeveler test içinde incelenebilir.
+type tapConn struct {
+	net.Conn
+	mu   sync.Mutex
+	read bytes.Buffer
+}
+
+func (c *tapConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.mu.Lock()
+	c.read.Write(p[:n])
+	c.mu.Unlock()
+	return n, err
+}
+
+// sdkFrames, okunan CmdSdkCmdAnswer çerçevelerinin toplam XML boyutunu ve
+// offset'lerini geliş sırasıyla döner ve kaydı sıfırlar.
+func (c *tapConn) sdkFrames() (total uint32, offsets []uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b := c.read.Bytes()
+	for len(b) >= 4 {
+		n := int(binary.LittleEndian.Uint16(b[0:2]))
+		if n < 4 || n > len(b) {
+			break
+		}
+		if huidu.CmdType(binary.LittleEndian.Uint16(b[2:4])) == huidu.CmdSdkCmdAnswer {
+			total = binary.LittleEndian.Uint32(b[4:8])
+			offsets = append(offsets, binary.LittleEndian.Uint32(b[8:12]))
+		}
+		b = b[n:]
+	}
+	c.read.Reset()
+	return total, offsets
+}
+
+// faultDevice, ctrl'a net.Pipe üzerinden bağlanan (yeniden bağlanabilen)
+// bir Device döner. Son açılan bağlantı tap ile izlenir.
+type faultDevice struct {
+	*huidu.Device
+
+	mu     sync.Mutex
+	tap    *tapConn
+	events []huidu.Event
+}
+
+func newFaultDevice(t *testing.T, ctrl *huidutest.Controller, options ...huidu.DeviceOption) *faultDevice {
+	t.Helper()
+	fd := &faultDevice{}
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		tap := &tapConn{Conn: ctrl.Pipe()}
+		fd.mu.Lock()
+		fd.tap = tap
+		fd.mu.Unlock()
+		return tap, nil
+	}
+	opts := []huidu.DeviceOption{
+		huidu.WithDialer(dial),
+		huidu.WithTimeout(2 * time.Second),
+		huidu.WithReconnectBackoff(time.Millisecond, 10*time.Millisecond),
+		huidu.WithEventHandler(func(ev huidu.Event) {
+			fd.mu.Lock()
+			fd.events = append(fd.events, ev)
+			fd.mu.Unlock()
+		}),
+	}
+	fd.Device = huidu.NewDevice("sim", huidu.DefaultPort, append(opts, options...)...)
+	if err := fd.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { fd.Close() })
+	ctrl.ClearRequests()
+	fd.currentTap().sdkFrames()
+	return fd
+}
+
+func (fd *faultDevice) currentTap() *tapConn {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	return fd.tap
+}
+
+// countEvents, typ tipindeki olay sayısını döner. Olaylar ayrı bir
+// goroutine'de iletildiğinden want'a ulaşılana kadar kısa süre beklenir.
+func (fd *faultDevice) countEvents(typ huidu.EventType, want int) int {
+	deadline := time.Now().Add(time.Second)
+	for {
+		fd.mu.Lock()
+		n := 0
+		for _, ev := range fd.events {
+			if ev.Type == typ {
+				n++
+			}
+		}
+		fd.mu.Unlock()
+		if n >= want || time.Now().After(deadline) {
+			return n
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// countRequests, kartın method için kaydettiği istek sayısını döner.
+func countRequests(ctrl *huidutest.Controller, method huidu.SdkMethod) int {
+	n := 0
+	for _, r := range ctrl.Requests() {
+		if r.Method == string(method) {
+			n++
+		}
+	}
+	return n
+}
+
+// fastRetry, testlerde beklemeyi kısaltan tekrar deneme politikasıdır.
+func fastRetry(attempts int) huidu.DeviceOption {
+	return huidu.WithRetryPolicy(huidu.RetryPolicy{
+		MaxAttempts:    attempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	})
+}
+
+// putFiles, yanıtı birden fazla SDK parçasına bölünecek kadar dosya ekler.
+func putFiles(ctrl *huidutest.Controller, n int) {
+	for i := 0; i < n; i++ {
+		ctrl.PutFile(fmt.Sprintf("asset-%03d.mp4", i), []byte(fmt.Sprintf("data-%d", i)), huidu.FileTypeVideo)
+	}
+}
+
+// sortedFiles, dosya listesini ada göre sıralar (kart sırası sabit değildir).
+func sortedFiles(files []huidu.FileInfo) []huidu.FileInfo {
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files
+}
+
+func TestFaultSkipAndTimes(t *testing.T) {
+	tests := []struct {
+		name        string
+		skip, times int
+		wantFail    []bool // her GetLuminanceInfo çağrısı için
+		wantPending int
+	}{
+		{"ilk eşleşme", 0, 0, []bool{true, false, false}, 0},
+		{"Skip ikinciyi seçer", 1, 0, []bool{false, true, false}, 0},
+		{"Times iki kez", 0, 2, []bool{true, true, false}, 0},
+		{"Skip ve Times", 1, 2, []bool{false, true, true, false}, 0},
+		{"sınırsız", 0, -1, []bool{true, true, true}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := huidutest.NewController()
+			defer ctrl.Close()
+			dev := newFaultDevice(t, ctrl)
+
+			ctrl.Inject(huidutest.Fault{
+				On:          huidu.CmdSdkCmdAsk,
+				Method:      huidu.MethodGetLuminancePloy,
+				Skip:        tt.skip,
+				Times:       tt.times,
+				ErrorAnswer: huidu.ErrProcessError,
+			})
+			// Başka metotlar kuralı tüketmez
+			if _, err := dev.GetDeviceInfo(); err != nil {
+				t.Fatalf("GetDeviceInfo: %v", err)
+			}
+
+			for i, wantFail := range tt.wantFail {
+				_, err := dev.GetLuminanceInfo()
+				if failed := err != nil; failed != wantFail {
+					t.Fatalf("çağrı %d: hata = %v, hata bekleniyor = %v", i+1, err, wantFail)
+				}
+				if err != nil && !errors.Is(err, huidu.ErrProcessError) {
+					t.Fatalf("çağrı %d: hata = %v, ErrProcessError bekleniyordu", i+1, err)
+				}
+			}
+			if got := ctrl.PendingFaults(); got != tt.wantPending {
+				t.Fatalf("PendingFaults = %d, beklenen %d", got, tt.wantPending)
+			}
+		})
+	}
+}
+
+func TestFaultErrorAnswer(t *testing.T) {
+	tests := []struct {
+		name         string
+		fault        huidutest.Fault
+		attempts     int
+		call         func(dev *huidu.Device) error
+		method       huidu.SdkMethod
+		wantErr      error // nil: başarı
+		wantRequests int
+		wantRetries  int
+	}{
+		{
+			name:     "tekrar denemesiz meşgul",
+			fault:    huidutest.Fault{On: huidu.CmdSdkCmdAsk, Method: huidu.MethodGetLuminancePloy, ErrorAnswer: huidu.ErrDeviceOccupied},
+			attempts: 1,
+			call:     func(dev *huidu.Device) error { _, err := dev.GetLuminanceInfo(); return err },
+			method:   huidu.MethodGetLuminancePloy, wantErr: huidu.ErrDeviceOccupied, wantRequests: 1,
+		},
+		{
+			name:     "meşgul sorgu tekrar denenir",
+			fault:    huidutest.Fault{On: huidu.CmdSdkCmdAsk, Method: huidu.MethodGetLuminancePloy, ErrorAnswer: huidu.ErrDeviceOccupied, Times: 2},
+			attempts: 3,
+			call:     func(dev *huidu.Device) error { _, err := dev.GetLuminanceInfo(); return err },
+			method:   huidu.MethodGetLuminancePloy, wantRequests: 3, wantRetries: 2,
+		},
+		{
+			name:     "denemeler tükenir",
+			fault:    huidutest.Fault{On: huidu.CmdSdkCmdAsk, Method: huidu.MethodGetLuminancePloy, ErrorAnswer: huidu.ErrDeviceOccupied, Times: -1},
+			attempts: 3,
+			call:     func(dev *huidu.Device) error { _, err := dev.GetLuminanceInfo(); return err },
+			method:   huidu.MethodGetLuminancePloy, wantErr: huidu.ErrDeviceOccupied, wantRequests: 3, wantRetries: 2,
+		},
+		{
+			name:     "Set komutu tekrarlanmaz",
+			fault:    huidutest.Fault{On: huidu.CmdSdkCmdAsk, Method: huidu.MethodSetLuminancePloy, ErrorAnswer: huidu.ErrDeviceOccupied},
+			attempts: 3,
+			call:     func(dev *huidu.Device) error { return dev.SetBrightness(10) },
+			method:   huidu.MethodSetLuminancePloy, wantErr: huidu.ErrDeviceOccupied, wantRequests: 1,
+		},
+		{
+			name:     "kod listesinde olmayan hata",
+			fault:    huidutest.Fault{On: huidu.CmdSdkCmdAsk, Method: huidu.MethodGetLuminancePloy, ErrorAnswer: huidu.ErrInvalidParam},
+			attempts: 3,
+			call:     func(dev *huidu.Device) error { _, err := dev.GetLuminanceInfo(); return err },
+			method:   huidu.MethodGetLuminancePloy, wantErr: huidu.ErrInvalidParam, wantRequests: 1,
+		},
+		{
+			name:     "dosya başlatma meşgul",
+			fault:    huidutest.Fault{On: huidu.CmdFileStartAsk, ErrorAnswer: huidu.ErrDeviceOccupied},
+			attempts: 2,
+			call: func(dev *huidu.Device) error {
+				return dev.UploadFileData("logo.png", []byte("png"), huidu.FileTypeImage)
+			},
+			wantRetries: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := huidutest.NewController()
+			defer ctrl.Close()
+			dev := newFaultDevice(t, ctrl, fastRetry(tt.attempts))
+			ctrl.Inject(tt.fault)
+
+			err := tt.call(dev.Device)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("hata = %v, başarı bekleniyordu", err)
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Fatalf("hata = %v, %v bekleniyordu", err, tt.wantErr)
+			}
+			if tt.method != "" {
+				if got := countRequests(ctrl, tt.method); got != tt.wantRequests {
+					t.Errorf("%s isteği = %d, beklenen %d", tt.method, got, tt.wantRequests)
+				}
+			}
+			if got := dev.countEvents(huidu.EventRetrying, tt.wantRetries); got != tt.wantRetries {
+				t.Errorf("EventRetrying = %d, beklenen %d", got, tt.wantRetries)
+			}
+		})
+	}
+}
+
+func TestFaultSdkResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     string
+		times      int
+		policy     huidu.RetryPolicy
+		wantCode   error // nil: başarı veya eşlenmemiş sonuç
+		wantResult string
+		wantTries  int
+	}{
+		{"eşlenen sonuç", "kParseXmlFailed", 0, huidu.RetryPolicy{MaxAttempts: 1}, huidu.ErrParseXmlFailed, "kParseXmlFailed", 1},
+		{"eşlenmeyen sonuç", "kVendorSpecific", 0, huidu.RetryPolicy{MaxAttempts: 1}, nil, "kVendorSpecific", 1},
+		{"meşgul sonucu tekrar denenir", "kDeviceOccupied", 1, huidu.RetryPolicy{MaxAttempts: 3}, nil, "", 2},
+		{"Results ile tekrar", "kVendorBusy", 2, huidu.RetryPolicy{MaxAttempts: 3, Results: []string{"kVendorBusy"}}, nil, "", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := huidutest.NewController()
+			defer ctrl.Close()
+			tt.policy.InitialBackoff = time.Millisecond
+			dev := newFaultDevice(t, ctrl, huidu.WithRetryPolicy(tt.policy))
+			ctrl.Inject(huidutest.Fault{On: huidu.CmdSdkCmdAsk, Method: huidu.MethodGetLuminancePloy, SdkResult: tt.result, Times: tt.times})
+
+			info, err := dev.GetLuminanceInfo()
+			if tt.wantResult == "" {
+				if err != nil {
+					t.Fatalf("hata = %v, başarı bekleniyordu", err)
+				}
+				if info.DefaultValue != 100 {
+					t.Fatalf("DefaultValue = %d", info.DefaultValue)
+				}
+			} else {
+				var re *huidu.ResultError
+				if !errors.As(err, &re) {
+					t.Fatalf("hata = %v (%T), *ResultError bekleniyordu", err, err)
+				}
+				if re.Result != tt.wantResult || re.Method != string(huidu.MethodGetLuminancePloy) {
+					t.Fatalf("ResultError = %+v", re)
+				}
+				if tt.wantCode != nil && !errors.Is(err, tt.wantCode) {
+					t.Fatalf("errors.Is(%v, %v) = false", err, tt.wantCode)
+				}
+				if tt.wantCode == nil && re.Unwrap() != nil {
+					t.Fatalf("eşlenmeyen sonuç %v koduna açıldı", re.Unwrap())
+				}
+			}
+			if got := countRequests(ctrl, huidu.MethodGetLuminancePloy); got != tt.wantTries {
+				t.Errorf("istek sayısı = %d, beklenen %d", got, tt.wantTries)
+			}
+		})
+	}
+}
+
+func TestFaultFragmentation(t *testing.T) {
+	tests := []struct {
+		name    string
+		fault   huidutest.Fault
+		reorder bool
+	}{
+		{"küçük parçalar", huidutest.Fault{FragmentSize: 64}, false},
+		{"orta boy parçalar", huidutest.Fault{FragmentSize: 1000}, false},
+		{"ters sıra", huidutest.Fault{FragmentSize: 64, Reorder: true}, true},
+		{"tek parça ters sıra", huidutest.Fault{Reorder: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := huidutest.NewController()
+			defer ctrl.Close()
+			putFiles(ctrl, 40)
+			dev := newFaultDevice(t, ctrl)
+
+			want, err := dev.GetFileList()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, single := dev.currentTap().sdkFrames(); len(single) != 1 {
+				t.Fatalf("hatasız yanıt %v parça, 1 bekleniyordu", single)
+			}
+
+			f := tt.fault
+			f.On, f.Method = huidu.CmdSdkCmdAsk, huidu.MethodGetFiles
+			ctrl.Inject(f)
+			got, err := dev.GetFileList()
+			if err != nil {
+				t.Fatalf("GetFileList: %v", err)
+			}
+			if !reflect.DeepEqual(sortedFiles(got), sortedFiles(want)) {
+				t.Fatalf("birleştirilen liste farklı:\n got %+v\nwant %+v", got, want)
+			}
+
+			// Parçaların gerçekten ayrı çerçeveler halinde ve istenen sırada geldiğini doğrula
+			total, offsets := dev.currentTap().sdkFrames()
+			size := uint32(f.FragmentSize)
+			if size == 0 {
+				size = huidu.MaxContentLength
+			}
+			if want := int((total + size - 1) / size); len(offsets) != want {
+				t.Fatalf("%d byte'lık yanıt %d parça geldi, %d bekleniyordu", total, len(offsets), want)
+			}
+			sorted := sort.SliceIsSorted(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+			reversed := sort.SliceIsSorted(offsets, func(i, j int) bool { return offsets[i] > offsets[j] })
+			if tt.reorder && !reversed || !tt.reorder && !sorted {
+				t.Fatalf("parça offset'leri = %v (ters sıra: %v)", offsets, tt.reorder)
+			}
+			if ctrl.PendingFaults() != 0 {
+				t.Fatal("kural tetiklenmedi")
+			}
+		})
+	}
+}
+
+func TestFaultSplitAt(t *testing.T) {
+	tests := []struct {
+		name    string
+		splitAt int
+		gap     time.Duration
+	}{
+		{"uzunluk alanı ortası", 1, 0},
+		{"uzunluk ve komut ayrı", 2, 0},
+		{"komut alanı ortası", 3, 0},
+		{"SDK header'ı ortası", 9, 20 * time.Millisecond},
+		{"XML ortası", 40, 0},
+		{"çerçeveden uzun", 1 << 16, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := huidutest.NewController()
+			defer ctrl.Close()
+			dev := newFaultDevice(t, ctrl)
+			ctrl.Inject(huidutest.Fault{On: huidu.CmdSdkCmdAsk, Method: huidu.MethodGetDeviceInfo, SplitAt: tt.splitAt, SplitGap: tt.gap})
+
+			start := time.Now()
+			info, err := dev.GetDeviceInfo()
+			if err != nil {
+				t.Fatalf("GetDeviceInfo: %v", err)
+			}
+			if *info != huidutest.DefaultDeviceInfo {
+				t.Fatalf("GetDeviceInfo = %+v", info)
+			}
+			if tt.gap > 0 && time.Since(start) < tt.gap {
+				t.Fatalf("yanıt %s içinde geldi, bölünme beklemesi %s", time.Since(start), tt.gap)
+			}
+		})
+	}
+}
+
+func TestFaultDropAndDelay(t *testing.T) {
+	tests := []struct {
+		name        string
+		fault       huidutest.Fault
+		timeout     time.Duration
+		wantErr     error
+		minDuration time.Duration
+	}{
+		{"Drop zaman aşımı", huidutest.Fault{Drop: true}, 100 * time.Millisecond, huidu.ErrTimeout, 100 * time.Millisecond},
+		{"kısa Delay", huidutest.Fault{Delay: 50 * time.Millisecond}, time.Second, nil, 50 * time.Millisecond},
+		{"uzun Delay", huidutest.Fault{Delay: 300 * time.Millisecond}, 100 * time.Millisecond, huidu.ErrTimeout, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := huidutest.NewController()
+			defer ctrl.Close()
+			dev := newFaultDevice(t, ctrl, huidu.WithTimeout(tt.timeout))
+			f := tt.fault
+			f.On, f.Method = huidu.CmdSdkCmdAsk, huidu.MethodGetLuminancePloy
+			ctrl.Inject(f)
+
+			start := time.Now()
+			_, err := dev.GetLuminanceInfo()
+			elapsed := time.Since(start)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("hata = %v, beklenen %v", err, tt.wantErr)
+			}
+			if elapsed < tt.minDuration {
+				t.Fatalf("komut %s sürdü, en az %s bekleniyordu", elapsed, tt.minDuration)
+			}
+
+			// Zaman aşımı bağlantıyı koparmaz; geç yanıt atılır
+			time.Sleep(tt.fault.Delay)
+			if !dev.IsConnected() {
+				t.Fatal("bağlantı kapandı")
+			}
+			if _, err := dev.GetDeviceInfo(); err != nil {
+				t.Fatalf("sonraki komut: %v", err)
+			}
+		})
+	}
+}
+
+func TestFaultDisconnect(t *testing.T) {
+	tests := []struct {
+		name          string
+		autoReconnect bool
+		method        huidu.SdkMethod
+		call          func(dev *huidu.Device) error
+		wantLost      bool
+		wantRequests  int
+		wantReconnect int
+	}{
+		{
+			name: "sorgu yeniden bağlanıp tekrarlanır", autoReconnect: true,
+			method:       huidu.MethodGetLuminancePloy,
+			call:         func(dev *huidu.Device) error { _, err := dev.GetLuminanceInfo(); return err },
+			wantRequests: 2, wantReconnect: 1,
+		},
+		{
+			name: "Set komutu tekrarlanmaz", autoReconnect: true,
+			method:   huidu.MethodSetLuminancePloy,
+			call:     func(dev *huidu.Device) error { return dev.SetBrightness(10) },
+			wantLost: true, wantRequests: 1, wantReconnect: 1,
+		},
+		{
+			name:     "otomatik yeniden bağlanma kapalı",
+			method:   huidu.MethodGetLuminancePloy,
+			call:     func(dev *huidu.Device) error { _, err := dev.GetLuminanceInfo(); return err },
+			wantLost: true, wantRequests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := huidutest.NewController()
+			defer ctrl.Close()
+			dev := newFaultDevice(t, ctrl, huidu.WithAutoReconnect(tt.autoReconnect))
+			ctrl.Inject(huidutest.Fault{On: huidu.CmdSdkCmdAsk, Method: tt.method, Disconnect: true})
+
+			err := tt.call(dev.Device)
+			if tt.wantLost != errors.Is(err, huidu.ErrConnectionLost) {
+				t.Fatalf("hata = %v, ErrConnectionLost bekleniyor = %v", err, tt.wantLost)
+			}
+			if !tt.wantLost && err != nil {
+				t.Fatalf("hata = %v", err)
+			}
+			if got := countRequests(ctrl, tt.method); got != tt.wantRequests {
+				t.Errorf("%s isteği = %d, beklenen %d", tt.method, got, tt.wantRequests)
+			}
+			// Kural paket işlenmeden uygulandığından kart durumu değişmez
+			if got := ctrl.Luminance().DefaultValue; got != 100 {
+				t.Errorf("kart parlaklığı = %d, değişmemeliydi", got)
+			}
+			if got := dev.countEvents(huidu.EventDisconnected, 1); got != 1 {
+				t.Errorf("EventDisconnected = %d, beklenen 1", got)
+			}
+			if got := dev.countEvents(huidu.EventReconnected, tt.wantReconnect); got != tt.wantReconnect {
+				t.Errorf("EventReconnected = %d, beklenen %d", got, tt.wantReconnect)
+			}
+		})
+	}
+}
+
+func TestFaultFileError(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 3*huidu.MaxContentLength)
+	tests := []struct {
+		name     string
+		fault    huidutest.Fault
+		attempts int
+		download bool
+		wantErr  error
+	}{
+		{"başlatmada yer yok", huidutest.Fault{On: huidu.CmdFileStartAsk, FileError: huidu.ErrNotSpaceToSave}, 1, false, huidu.ErrNotSpaceToSave},
+		{"bitişte içerik hatası", huidutest.Fault{On: huidu.CmdFileEndAsk, FileError: huidu.ErrFileContentError}, 1, false, huidu.ErrFileContentError},
+		{"okuma sınırı", huidutest.Fault{On: huidu.CmdReadFileAsk, FileError: huidu.ErrReadFileExcessive}, 1, true, huidu.ErrReadFileExcessive},
+		{"okuma sınırı tekrar denenir", huidutest.Fault{On: huidu.CmdReadFileAsk, FileError: huidu.ErrReadFileExcessive, Skip: 1}, 2, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := huidutest.NewController()
+			defer ctrl.Close()
+			ctrl.PutFile("clip.mp4", data, huidu.FileTypeVideo)
+			dev := newFaultDevice(t, ctrl, fastRetry(tt.attempts))
+			ctrl.Inject(tt.fault)
+
+			var err error
+			var buf bytes.Buffer
+			if tt.download {
+				err = dev.DownloadFile("clip.mp4", &buf)
+			} else {
+				err = dev.UploadFileData("new.mp4", data, huidu.FileTypeVideo)
+			}
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("hata = %v", err)
+				}
+				if tt.download && !bytes.Equal(buf.Bytes(), data) {
+					t.Fatalf("indirilen %d byte farklı", buf.Len())
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("hata = %v, %v bekleniyordu", err, tt.wantErr)
+			}
+			if f, ok := ctrl.File("new.mp4"); ok && f.Complete {
+				t.Fatal("hatalı yükleme tamamlanmış görünüyor")
+			}
+		})
+	}
+}
+
+func TestFaultResumeAt(t *testing.T) {
+	data := make([]byte, 5*huidu.MaxContentLength+123)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	const stored = 2 * huidu.MaxContentLength // ilk denemede kartta kalan
+
+	tests := []struct {
+		name          string
+		resumeAt      int64
+		wantFirstSent int64 // ilerleme callback'inin ilk bildirdiği değer
+		wantErr       error
+	}{
+		{"gerçek offset", 0, stored + huidu.MaxContentLength, nil},
+		{"parça sınırı", huidu.MaxContentLength, 2 * huidu.MaxContentLength, nil},
+		{"parça ortası", 5, 5 + huidu.MaxContentLength, nil},
+		{"kartta olandan fazla", stored + 100, stored + 100 + huidu.MaxContentLength, huidu.ErrFileNotFinish},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := huidutest.NewController()
+			defer ctrl.Close()
+
+			var mu sync.Mutex
+			var progress []int64
+			dev := newFaultDevice(t, ctrl, huidu.WithProgressCallback(func(p huidu.UploadProgress) {
+				mu.Lock()
+				progress = append(progress, p.SentBytes)
+				mu.Unlock()
+			}))
+
+			// İlk deneme üçüncü içerik paketinde kopar
+			ctrl.Inject(huidutest.Fault{On: huidu.CmdFileContentAsk, Skip: 2, Disconnect: true})
+			if err := dev.UploadFileData("clip.mp4", data, huidu.FileTypeVideo); !errors.Is(err, huidu.ErrConnectionLost) {
+				t.Fatalf("ilk deneme hatası = %v, ErrConnectionLost bekleniyordu", err)
+			}
+			if f, _ := ctrl.File("clip.mp4"); len(f.Data) != stored {
+				t.Fatalf("kartta %d byte kaldı, beklenen %d", len(f.Data), stored)
+			}
+			if err := dev.Connect(); err != nil {
+				t.Fatal(err)
+			}
+
+			mu.Lock()
+			progress = nil
+			mu.Unlock()
+			if tt.resumeAt > 0 {
+				ctrl.Inject(huidutest.Fault{On: huidu.CmdFileStartAsk, ResumeAt: tt.resumeAt})
+			}
+			err := dev.UploadFileData("clip.mp4", data, huidu.FileTypeVideo)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(progress) == 0 || progress[0] != tt.wantFirstSent {
+				t.Fatalf("ilerleme = %v, ilk değer %d bekleniyordu", progress, tt.wantFirstSent)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("hata = %v, %v bekleniyordu", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("devam eden yükleme: %v", err)
+			}
+			if f, _ := ctrl.File("clip.mp4"); !f.Complete || !bytes.Equal(f.Data, data) {
+				t.Fatalf("dosya tamamlanmadı (%d/%d byte)", len(f.Data), len(data))
+			}
+		})
+	}
+}
@@ -0,0 +1,642 @@
+package huidutest
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+)
+
+// ─── Protokol Oturumu ───────────────────────────────────────────────────────────
+//
+// session, tek bir TCP bağlantısı üzerindeki kart tarafı protokol
+// durumudur: parçalı SDK komutlarının birleştirilmesi ve devam eden dosya
+// yüklemesi. Kalıcı durum Controller'da tutulur.
+
+// transportVersion, kartın CmdServiceAnswer ile bildirdiği protokol versiyonudur.
+const transportVersion uint32 = 0x1000005
+
+// sdkVersion, GetIFVersion yanıtında bildirilen SDK versiyonudur.
+const sdkVersion = "1000000"
+
+type session struct {
+	c    *Controller
+	conn net.Conn
+
+	// sdkBuf, parçalı gelen SDK komutunun birleştirme tamponudur.
+	sdkBuf []byte
+
+	// upload, CmdFileStartAsk ile başlatılan dosyadır (yoksa nil).
+	upload *File
+
+	// fault, işlenmekte olan pakete uygulanan hata kuralıdır (yoksa nil).
+	fault *Fault
+}
+
+// errFaultDisconnect, Fault.Disconnect ile kapatılan oturumun sonlanma nedenidir.
+var errFaultDisconnect = errors.New("hata planı gereği bağlantı kapatıldı")
+
+// run, bağlantı kapanana kadar paketleri okuyup yanıtlar.
+func (s *session) run() {
+	for {
+		pkt, cmd, err := readPacket(s.conn)
+		if err != nil {
+			return
+		}
+		if err := s.handle(cmd, pkt); err != nil {
+			return
+		}
+	}
+}
+
+// handle, tek bir paketi işler ve gerekirse yanıt yazar.
+func (s *session) handle(cmd huidu.CmdType, pkt []byte) error {
+	// SDK komutlarında kural, parçalar birleştirildikten sonra eşleştirilir
+	if cmd == huidu.CmdSdkCmdAsk {
+		return s.handleSdkFragment(pkt)
+	}
+
+	s.fault = s.c.matchFault(cmd, "")
+	defer func() { s.fault = nil }()
+	if handled, err := s.preempt(); handled {
+		return err
+	}
+
+	switch cmd {
+	case huidu.CmdServiceAsk:
+		ans := make([]byte, 8)
+		binary.LittleEndian.PutUint32(ans[4:8], transportVersion)
+		return s.write(huidu.CmdServiceAnswer, ans[4:])
+
+	case huidu.CmdHeartbeatAsk:
+		return s.write(huidu.CmdHeartbeatAnswer, nil)
+
+	case huidu.CmdFileStartAsk:
+		return s.handleFileStart(pkt)
+
+	case huidu.CmdFileContentAsk:
+		return s.handleFileContent(pkt)
+
+	case huidu.CmdFileEndAsk:
+		return s.handleFileEnd()
+
+	case huidu.CmdReadFileAsk:
+		return s.handleReadFile(pkt)
+
+	default:
+		return s.writeError(huidu.ErrProcessError)
+	}
+}
+
+// ─── SDK Komutları ──────────────────────────────────────────────────────────────
+
+// handleSdkFragment, SDK komut parçasını birleştirir; komut tamamlanınca
+// işler ve yanıtı parçalayarak gönderir.
+func (s *session) handleSdkFragment(pkt []byte) error {
+	if len(pkt) < 12 {
+		return s.writeError(huidu.ErrInvalidPacketLen)
+	}
+	total := binary.LittleEndian.Uint32(pkt[4:8])
+	offset := binary.LittleEndian.Uint32(pkt[8:12])
+	chunk := pkt[12:]
+
+	if offset == 0 || uint32(len(s.sdkBuf)) != total {
+		s.sdkBuf = make([]byte, total)
+	}
+	if uint64(offset)+uint64(len(chunk)) > uint64(total) {
+		s.sdkBuf = nil
+		return s.writeError(huidu.ErrInvalidXmlIndex)
+	}
+	copy(s.sdkBuf[offset:], chunk)
+	if offset+uint32(len(chunk)) < total {
+		return nil
+	}
+
+	raw := string(s.sdkBuf)
+	s.sdkBuf = nil
+
+	req := s.c.recordRequest(raw)
+	s.fault = s.c.matchFault(huidu.CmdSdkCmdAsk, req.Method)
+	defer func() { s.fault = nil }()
+	if handled, err := s.preempt(); handled {
+		return err
+	}
+	return s.writeSdk(s.c.execSdk(req, s.fault))
+}
+
+// preempt, paketin işlenmesinden önce uygulanan hataları uygular.
+// Paket bu aşamada tüketildiyse handled=true döner.
+func (s *session) preempt() (handled bool, err error) {
+	f := s.fault
+	if f == nil {
+		return false, nil
+	}
+	if f.Disconnect {
+		s.conn.Close()
+		return true, errFaultDisconnect
+	}
+	if f.Drop {
+		return true, nil
+	}
+	if f.Delay > 0 {
+		time.Sleep(f.Delay)
+	}
+	if f.ErrorAnswer != huidu.ErrSuccess {
+		return true, s.writeError(f.ErrorAnswer)
+	}
+	return false, nil
+}
+
+// writeSdk, yanıt XML'ini MaxContentLength'lik (veya Fault.FragmentSize'lık)
+// parçalar halinde gönderir. Fault.Reorder ile parçalar ters sırada gider.
+func (s *session) writeSdk(respXML string) error {
+	size := huidu.MaxContentLength
+	if s.fault != nil && s.fault.FragmentSize > 0 {
+		size = s.fault.FragmentSize
+	}
+
+	data := []byte(respXML)
+	total := uint32(len(data))
+	var payloads [][]byte
+	for off := 0; ; off += size {
+		end := off + size
+		if end > len(data) {
+			end = len(data)
+		}
+		payload := make([]byte, 8+end-off)
+		binary.LittleEndian.PutUint32(payload[0:4], total)
+		binary.LittleEndian.PutUint32(payload[4:8], uint32(off))
+		copy(payload[8:], data[off:end])
+		payloads = append(payloads, payload)
+		if end >= len(data) {
+			break
+		}
+	}
+
+	if s.fault != nil && s.fault.Reorder {
+		for i, j := 0, len(payloads)-1; i < j; i, j = i+1, j-1 {
+			payloads[i], payloads[j] = payloads[j], payloads[i]
+		}
+	}
+	for _, payload := range payloads {
+		if err := s.write(huidu.CmdSdkCmdAnswer, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordRequest, tam bir SDK istek XML'ini ayrıştırıp kayda ekler.
+func (c *Controller) recordRequest(raw string) Request {
+	req := Request{
+		Method:   attrValue(raw, "method"),
+		GUID:     attrValue(raw, "guid"),
+		InnerXML: innerOf(raw, "in"),
+		RawXML:   raw,
+		Time:     time.Now(),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req)
+	return req
+}
+
+// execSdk, SDK isteğini işler ve yanıt XML'ini döner. f.SdkResult
+// verilmişse istek işlenmeden bu sonuç kodu döner.
+func (c *Controller) execSdk(req Request, f *Fault) string {
+	if f != nil && f.SdkResult != "" {
+		return buildResponse(req.GUID, req.Method, f.SdkResult, "")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	guid := req.GUID
+	result, out := c.execLocked(req.Method, req.InnerXML)
+	if req.Method == string(huidu.MethodGetIFVersion) && (guid == "" || guid == "##GUID") {
+		c.sessions++
+		guid = fmt.Sprintf("huidutest-%04d", c.sessions)
+	}
+	return buildResponse(guid, req.Method, result, out)
+}
+
+// execLocked, metodu kart durumuna uygular ve sonuç kodu ile iç XML'i döner.
+func (c *Controller) execLocked(method, inner string) (result, out string) {
+	switch huidu.SdkMethod(method) {
+	case huidu.MethodGetIFVersion:
+		return "kSuccess", `<version value="` + sdkVersion + `"/>`
+
+	case huidu.MethodGetDeviceInfo:
+		i := c.info
+		return "kSuccess", fmt.Sprintf(
+			`<device cpu="%s" model="%s" id="%s" name="%s"/><version fpga="%s" app="%s" kernel="%s"/><screen width="%d" height="%d" rotation="%d"/>`,
+			esc(i.CPU), esc(i.Model), esc(i.DeviceID), esc(i.DeviceName),
+			esc(i.FPGAVersion), esc(i.AppVersion), esc(i.KernelVersion),
+			i.ScreenWidth, i.ScreenHeight, i.ScreenRotation)
+
+	case huidu.MethodAddProgram:
+		c.programs = parsePrograms(inner)
+		c.noteRealtimeLocked(c.programs)
+		return "kSuccess", ""
+
+	case huidu.MethodUpdateProgram:
+		for _, p := range parsePrograms(inner) {
+			replaced := false
+			for i := range c.programs {
+				if c.programs[i].guid == p.guid {
+					c.programs[i] = p
+					replaced = true
+				}
+			}
+			if !replaced {
+				c.programs = append(c.programs, p)
+			}
+		}
+		c.noteRealtimeLocked(parsePrograms(inner))
+		return "kSuccess", ""
+
+	case huidu.MethodDeleteProgram:
+		del := parsePrograms(inner)
+		if len(del) == 0 {
+			c.programs = nil
+			c.realtime = ""
+			return "kSuccess", ""
+		}
+		kept := c.programs[:0]
+		for _, p := range c.programs {
+			remove := false
+			for _, d := range del {
+				remove = remove || d.guid == p.guid
+			}
+			if !remove {
+				kept = append(kept, p)
+			}
+		}
+		c.programs = kept
+		c.noteRealtimeLocked(nil)
+		return "kSuccess", ""
+
+	case huidu.MethodGetProgram:
+		return "kSuccess", c.screenXMLLocked()
+
+	case huidu.MethodGetCurrentPlayProgramGUID:
+		return "kSuccess", `<program guid="` + esc(c.playingLocked()) + `"/>`
+
+	case huidu.MethodSetPlayTypeToNormal:
+		c.realtime = ""
+		return "kSuccess", ""
+
+	case huidu.MethodOpenScreen:
+		c.screenOn = true
+		return "kSuccess", ""
+
+	case huidu.MethodCloseScreen:
+		c.screenOn = false
+		return "kSuccess", ""
+
+	case huidu.MethodGetFiles:
+		var b strings.Builder
+		b.WriteString("<files>")
+		for _, f := range c.files {
+			existSize := int64(len(f.Data))
+			fmt.Fprintf(&b, `<file name="%s" size="%d" existSize="%d" md5="%s" type="%d"/>`,
+				esc(f.Name), f.Size, existSize, f.MD5, int(f.Type))
+		}
+		b.WriteString("</files>")
+		return "kSuccess", b.String()
+
+	case huidu.MethodDeleteFiles:
+		for _, name := range fileNames(inner) {
+			delete(c.files, name)
+		}
+		return "kSuccess", ""
+
+	case huidu.MethodSetBootLogoName:
+		c.settings["BootLogo"] = inner
+		return "kSuccess", ""
+
+	case huidu.MethodClearBootLogo:
+		c.settings["BootLogo"] = `<logo exist="false" name="" md5=""/>`
+		return "kSuccess", ""
+	}
+
+	// Genel Set<X>/Get<X> çiftleri: Set iç XML'i saklar, Get geri döner.
+	switch {
+	case strings.HasPrefix(method, "Set"):
+		c.settings[strings.TrimPrefix(method, "Set")] = inner
+		return "kSuccess", ""
+	case strings.HasPrefix(method, "Get"):
+		return "kSuccess", c.settings[strings.TrimPrefix(method, "Get")]
+	}
+	return "kSuccess", ""
+}
+
+// buildResponse, SDK yanıt XML'ini oluşturur.
+func buildResponse(guid, method, result, inner string) string {
+	return `<?xml version="1.0" encoding="utf-8"?>` + "\r\n" +
+		`<sdk guid="` + esc(guid) + `"><out method="` + esc(method) + `" result="` + result + `">` +
+		inner + `</out></sdk>`
+}
+
+// ─── Dosya Transferi ────────────────────────────────────────────────────────────
+
+// handleFileStart, CmdFileStartAsk paketini işler. Aynı ad ve MD5 ile yarım
+// kalmış bir dosya varsa mevcut boyut existBytes olarak bildirilir (resume).
+func (s *session) handleFileStart(pkt []byte) error {
+	if len(pkt) < 48 {
+		return s.writeError(huidu.ErrInvalidPacketLen)
+	}
+	md5Hash := strings.TrimRight(string(pkt[4:36]), "\x00")
+	size := int64(binary.LittleEndian.Uint32(pkt[37:41]))
+	fileType := huidu.FileType(binary.LittleEndian.Uint16(pkt[45:47]))
+	name := string(pkt[47:])
+	if i := strings.IndexByte(name, 0); i >= 0 {
+		name = name[:i]
+	}
+
+	if s.fault != nil && s.fault.FileError != huidu.ErrSuccess {
+		s.upload = nil
+		return s.writeFileStartAnswer(s.fault.FileError, 0)
+	}
+
+	s.c.mu.Lock()
+	f, ok := s.c.files[name]
+	if !ok || f.MD5 != md5Hash || f.Size != size {
+		f = &File{Name: name, Type: fileType, MD5: md5Hash, Size: size}
+		s.c.files[name] = f
+	}
+	f.Complete = false
+	if s.fault != nil && s.fault.ResumeAt > 0 && int64(len(f.Data)) > s.fault.ResumeAt {
+		f.Data = f.Data[:s.fault.ResumeAt]
+	}
+	exist := uint32(len(f.Data))
+	if s.fault != nil && s.fault.ResumeAt > 0 {
+		exist = uint32(s.fault.ResumeAt)
+	}
+	s.c.mu.Unlock()
+
+	s.upload = f
+	return s.writeFileStartAnswer(huidu.ErrSuccess, exist)
+}
+
+// writeFileStartAnswer, CmdFileStartAnswer gönderir: [2B hata][4B existBytes].
+func (s *session) writeFileStartAnswer(code huidu.ErrorCode, exist uint32) error {
+	payload := make([]byte, 6)
+	binary.LittleEndian.PutUint16(payload[0:2], uint16(code))
+	binary.LittleEndian.PutUint32(payload[2:6], exist)
+	return s.write(huidu.CmdFileStartAnswer, payload)
+}
+
+// handleFileContent, dosya içeriğini ekler. Gerçek kart gibi onay göndermez.
+func (s *session) handleFileContent(pkt []byte) error {
+	if s.upload == nil {
+		return s.writeError(huidu.ErrProcessError)
+	}
+	s.c.mu.Lock()
+	s.upload.Data = append(s.upload.Data, pkt[4:]...)
+	s.c.mu.Unlock()
+	return nil
+}
+
+// handleFileEnd, yüklemeyi bitirir; boyut ve MD5 doğrulanır.
+func (s *session) handleFileEnd() error {
+	f := s.upload
+	s.upload = nil
+	if f == nil {
+		return s.writeFileEndAnswer(huidu.ErrProcessError)
+	}
+	if s.fault != nil && s.fault.FileError != huidu.ErrSuccess {
+		return s.writeFileEndAnswer(s.fault.FileError)
+	}
+
+	s.c.mu.Lock()
+	sum := md5.Sum(f.Data)
+	code := huidu.ErrSuccess
+	switch {
+	case int64(len(f.Data)) < f.Size:
+		code = huidu.ErrFileNotFinish
+	case int64(len(f.Data)) > f.Size || !strings.EqualFold(hex.EncodeToString(sum[:]), f.MD5):
+		code = huidu.ErrFileContentError
+		delete(s.c.files, f.Name)
+	default:
+		f.Complete = true
+	}
+	s.c.mu.Unlock()
+
+	return s.writeFileEndAnswer(code)
+}
+
+// writeFileEndAnswer, CmdFileEndAnswer gönderir: [2B hata].
+func (s *session) writeFileEndAnswer(code huidu.ErrorCode) error {
+	payload := make([]byte, 2)
+	binary.LittleEndian.PutUint16(payload, uint16(code))
+	return s.write(huidu.CmdFileEndAnswer, payload)
+}
+
+// handleReadFile, CmdReadFileAsk paketini işler: istenen offset'ten en fazla
+// istenen uzunlukta veriyi dosyanın toplam boyutuyla birlikte döner.
+func (s *session) handleReadFile(pkt []byte) error {
+	if len(pkt) < 13 {
+		return s.writeError(huidu.ErrInvalidPacketLen)
+	}
+	offset := int64(binary.LittleEndian.Uint32(pkt[4:8]))
+	length := int64(binary.LittleEndian.Uint32(pkt[8:12]))
+	name := string(pkt[12:])
+	if i := strings.IndexByte(name, 0); i >= 0 {
+		name = name[:i]
+	}
+
+	if s.fault != nil && s.fault.FileError != huidu.ErrSuccess {
+		return s.writeReadFileAnswer(s.fault.FileError, 0, 0, nil)
+	}
+
+	s.c.mu.Lock()
+	f, ok := s.c.files[name]
+	var size int64
+	var chunk []byte
+	if ok {
+		size = int64(len(f.Data))
+		if offset < size {
+			end := offset + length
+			if end > size {
+				end = size
+			}
+			chunk = append([]byte(nil), f.Data[offset:end]...)
+		}
+	}
+	s.c.mu.Unlock()
+
+	if !ok {
+		return s.writeReadFileAnswer(huidu.ErrFileNotFound, 0, 0, nil)
+	}
+	return s.writeReadFileAnswer(huidu.ErrSuccess, size, offset, chunk)
+}
+
+// writeReadFileAnswer, CmdReadFileAnswer gönderir:
+// [2B hata][4B toplam boyut][4B offset][veri].
+func (s *session) writeReadFileAnswer(code huidu.ErrorCode, size, offset int64, chunk []byte) error {
+	payload := make([]byte, 10+len(chunk))
+	binary.LittleEndian.PutUint16(payload[0:2], uint16(code))
+	binary.LittleEndian.PutUint32(payload[2:6], uint32(size))
+	binary.LittleEndian.PutUint32(payload[6:10], uint32(offset))
+	copy(payload[10:], chunk)
+	return s.write(huidu.CmdReadFileAnswer, payload)
+}
+
+// ─── Çerçeveleme ────────────────────────────────────────────────────────────────
+
+// write, [2B uzunluk][2B komut][payload] çerçevesi yazar.
+// Fault.SplitAt verilmişse çerçeve iki ayrı yazmayla gönderilir.
+func (s *session) write(cmd huidu.CmdType, payload []byte) error {
+	pkt := frame(cmd, payload)
+	if f := s.fault; f != nil && f.SplitAt > 0 && f.SplitAt < len(pkt) {
+		if _, err := s.conn.Write(pkt[:f.SplitAt]); err != nil {
+			return err
+		}
+		gap := f.SplitGap
+		if gap <= 0 {
+			gap = defaultSplitGap
+		}
+		time.Sleep(gap)
+		pkt = pkt[f.SplitAt:]
+	}
+	_, err := s.conn.Write(pkt)
+	return err
+}
+
+// writeError, CmdErrorAnswer gönderir: [2B hata kodu].
+func (s *session) writeError(code huidu.ErrorCode) error {
+	payload := make([]byte, 2)
+	binary.LittleEndian.PutUint16(payload, uint16(code))
+	return s.write(huidu.CmdErrorAnswer, payload)
+}
+
+// frame, payload'ı protokol çerçevesine sarar.
+func frame(cmd huidu.CmdType, payload []byte) []byte {
+	pkt := make([]byte, 4+len(payload))
+	binary.LittleEndian.PutUint16(pkt[0:2], uint16(len(pkt)))
+	binary.LittleEndian.PutUint16(pkt[2:4], uint16(cmd))
+	copy(pkt[4:], payload)
+	return pkt
+}
+
+// readPacket, r'den tek bir tam çerçeve okur.
+func readPacket(r io.Reader) ([]byte, huidu.CmdType, error) {
+	head := make([]byte, 4)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return nil, 0, err
+	}
+	n := int(binary.LittleEndian.Uint16(head[0:2]))
+	if n < 4 {
+		return nil, 0, fmt.Errorf("geçersiz paket uzunluğu: %d", n)
+	}
+	pkt := make([]byte, n)
+	copy(pkt, head)
+	if _, err := io.ReadFull(r, pkt[4:]); err != nil {
+		return nil, 0, err
+	}
+	return pkt, huidu.CmdType(binary.LittleEndian.Uint16(head[2:4])), nil
+}
+
+// ─── XML Yardımcıları ───────────────────────────────────────────────────────────
+
+// attrValue, XML içindeki ilk name="..." attribute değerini döner.
+func attrValue(raw, name string) string {
+	key := name + `="`
+	i := strings.Index(raw, key)
+	if i < 0 {
+		return ""
+	}
+	rest := raw[i+len(key):]
+	j := strings.IndexByte(rest, '"')
+	if j < 0 {
+		return ""
+	}
+	return rest[:j]
+}
+
+// innerOf, <tag ...>...</tag> elemanının iç XML'ini döner.
+func innerOf(raw, tag string) string {
+	i := strings.Index(raw, "<"+tag)
+	if i < 0 {
+		return ""
+	}
+	j := strings.IndexByte(raw[i:], '>')
+	if j < 0 || raw[i+j-1] == '/' {
+		return ""
+	}
+	start := i + j + 1
+	end := strings.LastIndex(raw, "</"+tag+">")
+	if end < start {
+		return ""
+	}
+	return strings.TrimSpace(raw[start:end])
+}
+
+// parsePrograms, <screen> veya tek başına <program> XML'lerinden programları çıkarır.
+func parsePrograms(inner string) []program {
+	var progs []program
+	dec := xml.NewDecoder(strings.NewReader(inner))
+	for {
+		start := dec.InputOffset()
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != "program" {
+			continue
+		}
+		if err := dec.Skip(); err != nil {
+			break
+		}
+		p := program{xml: strings.TrimSpace(inner[start:dec.InputOffset()])}
+		for _, a := range se.Attr {
+			switch a.Name.Local {
+			case "guid":
+				p.guid = a.Value
+			case "flag":
+				p.realtime = a.Value == "realtime"
+			}
+		}
+		progs = append(progs, p)
+	}
+	return progs
+}
+
+// fileNames, <files><file name="..."/></files> XML'inden dosya adlarını çıkarır.
+func fileNames(inner string) []string {
+	var names []string
+	dec := xml.NewDecoder(strings.NewReader(inner))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "file" {
+			for _, a := range se.Attr {
+				if a.Name.Local == "name" {
+					names = append(names, a.Value)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// esc, attribute değerini XML için kaçışlar.
+func esc(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
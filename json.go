@@ -0,0 +1,462 @@
+package huidu
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ─── Screen JSON/YAML Temsili ───────────────────────────────────────────────────
+//
+// Screen, Program, Area ve AreaItem; JSON (encoding/json) ve YAML
+// (gopkg.in/yaml.v2, gopkg.in/yaml.v3) ile doğrudan serileştirilebilir.
+// MarshalYAML ve fonksiyon parametreli UnmarshalYAML her iki sürüm
+// tarafından tanınır. Paket YAML kütüphanelerine bağımlı değildir; temsil
+// tiplerinin yaml etiketleri json etiketleriyle aynıdır, böylece iki format
+// aynı anahtarları yazar. YAML'ı JSON'a çeviren kütüphaneler (ör.
+// sigs.k8s.io/yaml) bu metotları çağırmaz, MarshalJSON/UnmarshalJSON
+// üzerinden çalışır.
+//
+// Format sürümlüdür; kök nesne "version" alanını taşır:
+//
+//	{
+//	  "version": 1,
+//	  "programs": [{
+//	    "guid": "…", "name": "Vitrin", "type": "normal", "playCount": 3,
+//	    "areas": [{
+//	      "guid": "…", "x": 0, "y": 0, "width": 64, "height": 32, "alpha": 255,
+//	      "items": [
+//	        {"kind": "text", "guid": "…", "text": "Merhaba", "config": {"fontSize": 12, "color": "#ff0000"}},
+//	        {"kind": "image", "guid": "…", "fileName": "logo.png", "config": {"fit": "stretch"}}
+//	      ]
+//	    }]
+//	  }]
+//	}
+//
+// Öğe tipi "kind" alanıyla (ItemKind) belirlenir ve "config" nesnesinin
+// içeriği buna göre TextConfig, ImageConfig, VideoConfig veya ClockConfig
+// olarak okunur. Kütüphanenin modellemediği öğe tipleri (GetScreen ile
+// okunan firmware'e özgü öğeler) "config" yerine "rawXML" alanında ham XML
+// olarak taşınır. Okuma sırasında boş bırakılan GUID'ler için yeni GUID
+// üretilir ve Add* fonksiyonlarının varsayılanları uygulanır; böylece elle
+// yazılmış şablonlar doğrudan SendScreen ile gönderilebilir.
+
+// ScreenSchemaVersion, Screen JSON/YAML formatının güncel sürümüdür.
+// Daha yeni sürümlü belgeler reddedilir.
+const ScreenSchemaVersion = 1
+
+// screenJSON ve alt tipleri, JSON/YAML temsilinin alanlarıdır.
+type screenJSON struct {
+	Version  int        `json:"version" yaml:"version"`
+	Programs []*Program `json:"programs" yaml:"programs"`
+}
+
+type programJSON struct {
+	GUID      string      `json:"guid,omitempty" yaml:"guid,omitempty"`
+	ID        int         `json:"id" yaml:"id"`
+	Name      string      `json:"name" yaml:"name"`
+	Type      ProgramType `json:"type,omitempty" yaml:"type,omitempty"`
+	Realtime  bool        `json:"realtime,omitempty" yaml:"realtime,omitempty"`
+	PlayCount int         `json:"playCount,omitempty" yaml:"playCount,omitempty"`
+	Duration  string      `json:"duration,omitempty" yaml:"duration,omitempty"`
+	Disabled  bool        `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	Areas     []*Area     `json:"areas" yaml:"areas"`
+}
+
+type areaJSON struct {
+	GUID   string     `json:"guid,omitempty" yaml:"guid,omitempty"`
+	Name   string     `json:"name,omitempty" yaml:"name,omitempty"`
+	X      int        `json:"x" yaml:"x"`
+	Y      int        `json:"y" yaml:"y"`
+	Width  int        `json:"width" yaml:"width"`
+	Height int        `json:"height" yaml:"height"`
+	Alpha  *int       `json:"alpha,omitempty" yaml:"alpha,omitempty"`
+	Items  []AreaItem `json:"items" yaml:"items"`
+}
+
+// itemJSON, öğenin ortak alanlarıdır; Config, Kind'a göre ilgili
+// yapılandırma tipinin işaretçisidir.
+type itemJSON struct {
+	Kind     ItemKind    `json:"kind" yaml:"kind"`
+	GUID     string      `json:"guid,omitempty" yaml:"guid,omitempty"`
+	Name     string      `json:"name,omitempty" yaml:"name,omitempty"`
+	Text     string      `json:"text,omitempty" yaml:"text,omitempty"`
+	FileName string      `json:"fileName,omitempty" yaml:"fileName,omitempty"`
+	Config   interface{} `json:"config,omitempty" yaml:"config,omitempty"`
+	RawXML   string      `json:"rawXML,omitempty" yaml:"rawXML,omitempty"`
+}
+
+// ─── Screen ─────────────────────────────────────────────────────────────────────
+
+// MarshalJSON, json.Marshaler arayüzünü uygular.
+//
+//	data, err := json.Marshal(screen)
+func (s Screen) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.doc())
+}
+
+// UnmarshalJSON, json.Unmarshaler arayüzünü uygular. Okunan Screen,
+// NewScreen gibi yeni bir ekran olarak işaretlenir.
+//
+//	var screen huidu.Screen
+//	err := json.Unmarshal(data, &screen)
+//	err = dev.SendScreen(&screen)
+func (s *Screen) UnmarshalJSON(data []byte) error {
+	return s.decode(jsonDecoder(data))
+}
+
+// MarshalYAML, YAML kütüphanelerinin Marshaler arayüzünü uygular.
+func (s Screen) MarshalYAML() (interface{}, error) {
+	return s.doc(), nil
+}
+
+// UnmarshalYAML, YAML kütüphanelerinin Unmarshaler arayüzünü uygular
+// (bkz. UnmarshalJSON).
+func (s *Screen) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return s.decode(unmarshal)
+}
+
+// doc, Screen'in serileştirilecek temsilini döner.
+func (s Screen) doc() *screenJSON {
+	programs := s.Programs
+	if programs == nil {
+		programs = []*Program{}
+	}
+	return &screenJSON{Version: ScreenSchemaVersion, Programs: programs}
+}
+
+// decode, temsili okuyup Screen'e aktarır.
+func (s *Screen) decode(unmarshal func(interface{}) error) error {
+	var doc screenJSON
+	if err := unmarshal(&doc); err != nil {
+		return err
+	}
+	if doc.Version < 1 || doc.Version > ScreenSchemaVersion {
+		return fmt.Errorf("desteklenmeyen ekran şema sürümü: %d (desteklenen: 1-%d)", doc.Version, ScreenSchemaVersion)
+	}
+	for i, p := range doc.Programs {
+		if p == nil {
+			return fmt.Errorf("program %d boş", i)
+		}
+	}
+
+	*s = Screen{Programs: doc.Programs, isNew: true}
+	return nil
+}
+
+// ─── Program ────────────────────────────────────────────────────────────────────
+
+// MarshalJSON, json.Marshaler arayüzünü uygular.
+func (p Program) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.doc())
+}
+
+// UnmarshalJSON, json.Unmarshaler arayüzünü uygular.
+// GUID boşsa yeni GUID üretilir, tip boşsa ProgramNormal kullanılır.
+func (p *Program) UnmarshalJSON(data []byte) error {
+	return p.decode(jsonDecoder(data))
+}
+
+// MarshalYAML, YAML kütüphanelerinin Marshaler arayüzünü uygular.
+func (p Program) MarshalYAML() (interface{}, error) {
+	return p.doc(), nil
+}
+
+// UnmarshalYAML, YAML kütüphanelerinin Unmarshaler arayüzünü uygular.
+func (p *Program) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return p.decode(unmarshal)
+}
+
+func (p Program) doc() *programJSON {
+	areas := p.Areas
+	if areas == nil {
+		areas = []*Area{}
+	}
+	return &programJSON{
+		GUID:      p.GUID,
+		ID:        p.ID,
+		Name:      p.Name,
+		Type:      p.Type,
+		Realtime:  p.Realtime,
+		PlayCount: p.PlayCount,
+		Duration:  p.Duration,
+		Disabled:  p.Disabled,
+		Areas:     areas,
+	}
+}
+
+func (p *Program) decode(unmarshal func(interface{}) error) error {
+	var doc programJSON
+	if err := unmarshal(&doc); err != nil {
+		return err
+	}
+	for i, a := range doc.Areas {
+		if a == nil {
+			return fmt.Errorf("program %q: alan %d boş", doc.Name, i)
+		}
+	}
+
+	*p = Program{
+		Type:      doc.Type,
+		ID:        doc.ID,
+		GUID:      doc.GUID,
+		Name:      doc.Name,
+		Areas:     doc.Areas,
+		Realtime:  doc.Realtime,
+		PlayCount: doc.PlayCount,
+		Duration:  doc.Duration,
+		Disabled:  doc.Disabled,
+	}
+	if p.Type == "" {
+		p.Type = ProgramNormal
+	}
+	if p.GUID == "" {
+		p.GUID = uuid.New().String()
+	}
+	return nil
+}
+
+// ─── Area ───────────────────────────────────────────────────────────────────────
+
+// MarshalJSON, json.Marshaler arayüzünü uygular. Öğeler Items sırasıyla
+// yazılır.
+func (a Area) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.doc())
+}
+
+// UnmarshalJSON, json.Unmarshaler arayüzünü uygular.
+// GUID boşsa yeni GUID üretilir, alpha verilmezse 255 kullanılır.
+func (a *Area) UnmarshalJSON(data []byte) error {
+	return a.decode(jsonDecoder(data))
+}
+
+// MarshalYAML, YAML kütüphanelerinin Marshaler arayüzünü uygular.
+func (a Area) MarshalYAML() (interface{}, error) {
+	return a.doc(), nil
+}
+
+// UnmarshalYAML, YAML kütüphanelerinin Unmarshaler arayüzünü uygular.
+func (a *Area) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return a.decode(unmarshal)
+}
+
+func (a Area) doc() *areaJSON {
+	alpha := a.Alpha
+	return &areaJSON{
+		GUID:   a.GUID,
+		Name:   a.Name,
+		X:      a.X,
+		Y:      a.Y,
+		Width:  a.Width,
+		Height: a.Height,
+		Alpha:  &alpha,
+		Items:  a.Items(),
+	}
+}
+
+func (a *Area) decode(unmarshal func(interface{}) error) error {
+	var doc areaJSON
+	if err := unmarshal(&doc); err != nil {
+		return err
+	}
+
+	*a = Area{
+		GUID:   doc.GUID,
+		Name:   doc.Name,
+		X:      doc.X,
+		Y:      doc.Y,
+		Width:  doc.Width,
+		Height: doc.Height,
+		Alpha:  255,
+	}
+	if doc.Alpha != nil {
+		a.Alpha = *doc.Alpha
+	}
+	if a.GUID == "" {
+		a.GUID = uuid.New().String()
+	}
+	for _, it := range doc.Items {
+		a.items = append(a.items, it.item())
+	}
+	return nil
+}
+
+// ─── AreaItem ───────────────────────────────────────────────────────────────────
+
+// MarshalJSON, json.Marshaler arayüzünü uygular. Kind'a ait yapılandırma
+// "config" alanına yazılır.
+func (it AreaItem) MarshalJSON() ([]byte, error) {
+	doc, err := it.doc()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON, json.Unmarshaler arayüzünü uygular. "config" alanı
+// "kind" alanına göre okunur; bilinmeyen tipler hata döner.
+func (it *AreaItem) UnmarshalJSON(data []byte) error {
+	return it.decode(jsonDecoder(data))
+}
+
+// MarshalYAML, YAML kütüphanelerinin Marshaler arayüzünü uygular.
+func (it AreaItem) MarshalYAML() (interface{}, error) {
+	return it.doc()
+}
+
+// UnmarshalYAML, YAML kütüphanelerinin Unmarshaler arayüzünü uygular.
+func (it *AreaItem) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return it.decode(unmarshal)
+}
+
+func (it AreaItem) doc() (*itemJSON, error) {
+	doc := &itemJSON{
+		Kind:     it.Kind,
+		GUID:     it.GUID,
+		Name:     it.Name,
+		Text:     it.Text,
+		FileName: it.FileName,
+	}
+
+	// Ad, öğenin üst seviye "name" alanında tutulur
+	switch it.Kind {
+	case ItemText:
+		c := TextConfig{}
+		if it.TextConfig != nil {
+			c = *it.TextConfig
+		}
+		c.Name = ""
+		doc.Config = &c
+	case ItemImage:
+		c := ImageConfig{}
+		if it.ImageConfig != nil {
+			c = *it.ImageConfig
+		}
+		c.Name = ""
+		doc.Config = &c
+	case ItemVideo:
+		c := VideoConfig{}
+		if it.VideoConfig != nil {
+			c = *it.VideoConfig
+		}
+		c.Name = ""
+		doc.Config = &c
+	case ItemClock:
+		c := ClockConfig{}
+		if it.ClockConfig != nil {
+			c = *it.ClockConfig
+		}
+		c.Name = ""
+		doc.Config = &c
+	default:
+		if it.RawXML == "" {
+			return nil, fmt.Errorf("bilinmeyen öğe tipi: %q", it.Kind)
+		}
+		doc.RawXML = it.RawXML
+	}
+	return doc, nil
+}
+
+func (it *AreaItem) decode(unmarshal func(interface{}) error) error {
+	// Önce ortak alanlar ve tip okunur, ardından config ilgili
+	// yapılandırma tipine çözülür
+	var head itemJSON
+	if err := unmarshal(&head); err != nil {
+		return err
+	}
+
+	*it = AreaItem{
+		Kind:     head.Kind,
+		GUID:     head.GUID,
+		Name:     head.Name,
+		Text:     head.Text,
+		FileName: head.FileName,
+	}
+
+	switch head.Kind {
+	case ItemText:
+		var body struct {
+			Config TextConfig `json:"config" yaml:"config"`
+		}
+		if err := unmarshal(&body); err != nil {
+			return err
+		}
+		it.TextConfig = &body.Config
+	case ItemImage:
+		var body struct {
+			Config ImageConfig `json:"config" yaml:"config"`
+		}
+		if err := unmarshal(&body); err != nil {
+			return err
+		}
+		it.ImageConfig = &body.Config
+	case ItemVideo:
+		var body struct {
+			Config VideoConfig `json:"config" yaml:"config"`
+		}
+		if err := unmarshal(&body); err != nil {
+			return err
+		}
+		it.VideoConfig = &body.Config
+	case ItemClock:
+		var body struct {
+			Config ClockConfig `json:"config" yaml:"config"`
+		}
+		if err := unmarshal(&body); err != nil {
+			return err
+		}
+		it.ClockConfig = &body.Config
+	default:
+		if head.RawXML == "" {
+			return fmt.Errorf("bilinmeyen öğe tipi: %q", head.Kind)
+		}
+		it.RawXML = head.RawXML
+	}
+
+	// Ad, öğenin üst seviye "name" alanından alınır (bkz. Items)
+	switch {
+	case it.TextConfig != nil:
+		it.TextConfig.Name = it.Name
+	case it.ImageConfig != nil:
+		it.ImageConfig.Name = it.Name
+	case it.VideoConfig != nil:
+		it.VideoConfig.Name = it.Name
+	case it.ClockConfig != nil:
+		it.ClockConfig.Name = it.Name
+	}
+	return nil
+}
+
+// item, görünümden alan öğesi oluşturur. GUID boşsa yeni GUID üretilir;
+// Add* fonksiyonlarının varsayılanları uygulanır. Kind decode sırasında
+// doğrulandığından yapılandırma işaretçisi (tanınmayan tiplerde RawXML)
+// doludur.
+func (it AreaItem) item() areaItem {
+	guid := it.GUID
+	if guid == "" {
+		guid = uuid.New().String()
+	}
+
+	switch it.Kind {
+	case ItemText:
+		return newTextItem(guid, it.Text, *it.TextConfig)
+	case ItemImage:
+		return newImageItem(guid, it.FileName, *it.ImageConfig)
+	case ItemVideo:
+		return newVideoItem(guid, it.FileName, *it.VideoConfig)
+	case ItemClock:
+		return newClockItem(guid, *it.ClockConfig)
+	default:
+		// Ham XML olduğu gibi yazılır; GUID'i XML'in içindedir
+		return &rawItem{kind: it.Kind, guid: it.GUID, name: it.Name, xml: it.RawXML}
+	}
+}
+
+// jsonDecoder, data'yı çözen bir unmarshal fonksiyonu döner; JSON ve YAML
+// okuma aynı decode fonksiyonlarını paylaşır.
+func jsonDecoder(data []byte) func(interface{}) error {
+	return func(v interface{}) error {
+		return json.Unmarshal(data, v)
+	}
+}
@@ -0,0 +1,259 @@
+package huidu_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+)
+
+// loadScreen, testdata/screen.xml'i okur.
+func loadScreen(t *testing.T) *huidu.Screen {
+	t.Helper()
+	data, err := os.ReadFile("testdata/screen.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen, err := huidu.ParseScreenXML(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return screen
+}
+
+// screenXML, karşılaştırma için ekranın XML çıktısını döner.
+func screenXML(t *testing.T, s *huidu.Screen) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := s.WriteXML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+// yamlMarshaler, gopkg.in/yaml.v2 ve yaml.v3'ün tanıdığı Marshaler arayüzüdür.
+type yamlMarshaler interface {
+	MarshalYAML() (interface{}, error)
+}
+
+// yamlTree, v'yi YAML kütüphanelerinin izlediği yoldan (önce MarshalYAML,
+// sonra yaml etiketleri) genel bir ağaca çevirir. Her alanın yaml etiketi
+// json etiketiyle aynı olmalıdır; aksi halde iki format farklı anahtarlar
+// yazar ve YAML okuması JSON'la aynı decode yolunu kullanamaz.
+func yamlTree(t *testing.T, v reflect.Value) interface{} {
+	t.Helper()
+	if !v.IsValid() {
+		return nil
+	}
+	if (v.Kind() != reflect.Ptr || !v.IsNil()) && v.CanInterface() {
+		if m, ok := v.Interface().(yamlMarshaler); ok {
+			out, err := m.MarshalYAML()
+			if err != nil {
+				t.Fatalf("%s.MarshalYAML: %v", v.Type(), err)
+			}
+			return yamlTree(t, reflect.ValueOf(out))
+		}
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return yamlTree(t, v.Elem())
+	case reflect.Slice:
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = yamlTree(t, v.Index(i))
+		}
+		return out
+	case reflect.Struct:
+		out := map[string]interface{}{}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			tag := f.Tag.Get("yaml")
+			if js := f.Tag.Get("json"); tag != js {
+				t.Errorf("%s.%s: yaml etiketi %q, json etiketi %q", v.Type(), f.Name, tag, js)
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			if name == "-" || opts == "omitempty" && v.Field(i).IsZero() {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(f.Name)
+			}
+			out[name] = yamlTree(t, v.Field(i))
+		}
+		return out
+	default:
+		return v.Interface()
+	}
+}
+
+// yamlDecoder, YAML kütüphanelerinin UnmarshalYAML'a verdiği fonksiyonu
+// taklit eder. Etiketler aynı olduğundan belge JSON olarak çözülebilir.
+func yamlDecoder(doc string) func(interface{}) error {
+	return func(v interface{}) error {
+		return json.Unmarshal([]byte(doc), v)
+	}
+}
+
+func TestScreenSerialisationRoundTrip(t *testing.T) {
+	screen := loadScreen(t)
+	want := screenXML(t, screen)
+
+	data, err := json.Marshal(screen)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	for _, key := range []string{"version", "kind", "config"} {
+		if !strings.Contains(string(data), key) {
+			t.Fatalf("çıktıda %q yok:\n%s", key, data)
+		}
+	}
+
+	var back huidu.Screen
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("Unmarshal: %v\n%s", err, data)
+	}
+	if got := screenXML(t, &back); got != want {
+		t.Fatalf("geri okunan ekran farklı:\n%s\nbeklenen:\n%s", got, want)
+	}
+}
+
+// YAML kütüphaneleri import edilmeden, MarshalYAML/UnmarshalYAML ve yaml
+// etiketleri üzerinden YAML çıktısının JSON'la aynı yapıda olduğu doğrulanır.
+func TestScreenYAMLCompatibility(t *testing.T) {
+	screen := loadScreen(t)
+	want := screenXML(t, screen)
+
+	tree, err := json.Marshal(yamlTree(t, reflect.ValueOf(screen)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	direct, err := json.Marshal(screen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, exp interface{}
+	if err := json.Unmarshal(tree, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(direct, &exp); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Fatalf("YAML yapısı JSON'dan farklı:\n%s\nbeklenen:\n%s", tree, direct)
+	}
+
+	var back huidu.Screen
+	if err := back.UnmarshalYAML(yamlDecoder(string(tree))); err != nil {
+		t.Fatalf("UnmarshalYAML: %v", err)
+	}
+	if got := screenXML(t, &back); got != want {
+		t.Fatalf("geri okunan ekran farklı:\n%s\nbeklenen:\n%s", got, want)
+	}
+}
+
+func TestAreaItemKind(t *testing.T) {
+	tests := []struct {
+		name string
+		item string
+		kind huidu.ItemKind
+		ok   func(huidu.AreaItem) bool
+	}{
+		{"metin", `{"kind":"text","text":"Merhaba","config":{"fontSize":20,"color":"#00ff00"}}`, huidu.ItemText,
+			func(it huidu.AreaItem) bool {
+				return it.Text == "Merhaba" && it.TextConfig != nil && it.TextConfig.FontSize == 20 && it.ImageConfig == nil
+			}},
+		{"görsel", `{"kind":"image","fileName":"logo.png","config":{"fit":"center"}}`, huidu.ItemImage,
+			func(it huidu.AreaItem) bool {
+				return it.FileName == "logo.png" && it.ImageConfig != nil && it.ImageConfig.Fit == huidu.ImageFitCenter && it.TextConfig == nil
+			}},
+		{"video", `{"kind":"video","fileName":"a.mp4","config":{"aspectRatio":true}}`, huidu.ItemVideo,
+			func(it huidu.AreaItem) bool { return it.VideoConfig != nil && it.VideoConfig.AspectRatio }},
+		{"saat", `{"kind":"clock","name":"yerel","config":{"type":"dial"}}`, huidu.ItemClock,
+			func(it huidu.AreaItem) bool {
+				return it.ClockConfig != nil && it.ClockConfig.Type == huidu.ClockDial && it.ClockConfig.Name == "yerel"
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var it huidu.AreaItem
+			if err := json.Unmarshal([]byte(tt.item), &it); err != nil {
+				t.Fatal(err)
+			}
+			if it.Kind != tt.kind || !tt.ok(it) {
+				t.Fatalf("öğe = %+v", it)
+			}
+
+			// Yazılan "kind" tekrar aynı tipe okunmalı
+			data, err := json.Marshal(it)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var back huidu.AreaItem
+			if err := json.Unmarshal(data, &back); err != nil {
+				t.Fatal(err)
+			}
+			if back.Kind != tt.kind || !tt.ok(back) {
+				t.Fatalf("geri okunan öğe = %+v (%s)", back, data)
+			}
+		})
+	}
+
+	for _, bad := range []string{
+		`{"kind":"marquee","config":{}}`,
+		`{"text":"tipsiz"}`,
+		`{"kind":"text","config":{"fontSize":"büyük"}}`,
+	} {
+		var it huidu.AreaItem
+		if err := json.Unmarshal([]byte(bad), &it); err == nil {
+			t.Errorf("%s kabul edildi: %+v", bad, it)
+		}
+	}
+}
+
+func TestScreenSchemaVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		ok   bool
+	}{
+		{"güncel", `{"version":1,"programs":[]}`, true},
+		{"sürümsüz", `{"programs":[]}`, false},
+		{"sıfır", `{"version":0,"programs":[]}`, false},
+		{"daha yeni", `{"version":2,"programs":[]}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s huidu.Screen
+			err := json.Unmarshal([]byte(tt.doc), &s)
+			if tt.ok && err != nil {
+				t.Fatalf("hata: %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Fatal("kabul edildi")
+			}
+		})
+	}
+
+	var s huidu.Screen
+	if err := s.UnmarshalYAML(yamlDecoder(`{"version":2,"programs":[]}`)); err == nil {
+		t.Fatal("YAML: daha yeni sürüm kabul edildi")
+	}
+
+	data, err := json.Marshal(huidu.NewScreen())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"version":1`) {
+		t.Fatalf("çıktıda sürüm yok: %s", data)
+	}
+}
@@ -0,0 +1,501 @@
+package huidu
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ─── Okuma Döngüsü ve Paket Yönlendirme ─────────────────────────────────────────
+//
+// Her TCP bağlantısı için tek bir okuma goroutine'i (readLoop) çalışır.
+// Komutlar soketi kendileri okumaz; istek gönderdikten sonra okuma
+// döngüsünün kendilerine yönlendireceği yanıtı beklerler.
+//
+// Yönlendirme kuralları:
+//   - CmdSdkCmdAnswer: parçalar birleştirilir, tamamlanan yanıt Method'u
+//     bekleyen komutla eşleşiyorsa ona iletilir; eşleşmiyorsa (zaman aşımına
+//     uğramış bir komutun geç yanıtı) atılır.
+//   - CmdServiceAnswer, CmdFileStartAnswer, CmdFileEndAnswer, CmdReadFileAnswer:
+//     bu tipleri bekleyen alışverişe (handshake, dosya transferi) iletilir.
+//   - CmdErrorAnswer: bekleyen alışveriş hangisiyse ona iletilir.
+//   - CmdHeartbeatAnswer: heartbeat takibi için kaydedilir.
+//   - CmdHeartbeatAsk, CmdServiceAsk: cihaz heartbeat veya versiyon anlaşması
+//     başlatırsa (ör. Server'a bağlanan cihazlar) yanıt replyLoop'a sıralanır.
+//     Okuma döngüsü sokete yazmaz; böylece süren büyük bir yazma (ör. dosya
+//     içeriği) paket yönlendirmesini bekletmez.
+//   - Diğer tüm paketler (ör. CmdGPSInfoAnswer): Subscribe ile kaydolan
+//     dinleyicilere dağıtılır.
+//
+// Protokolde istek kimliği bulunmadığından aynı anda yalnızca bir alışveriş
+// yürütülür; Device.cmdSem bu sıralamayı sağlar.
+
+// ErrTimeout, cihaz WithTimeout (veya ctx deadline) süresi içinde yanıt
+// vermediğinde döner. Bağlantı açık kalır; geç gelen yanıt atılır.
+var ErrTimeout = errors.New("huidu: yanıt zaman aşımı")
+
+// link, tek bir TCP bağlantısını ve onun okuma döngüsünü temsil eder.
+type link struct {
+	conn net.Conn
+
+	// done, okuma döngüsü sona erdiğinde kapatılır.
+	done chan struct{}
+
+	// err, okuma döngüsünün sona erme nedenidir (done kapandıktan sonra geçerli).
+	err error
+
+	// mu, pending alanını korur.
+	mu sync.Mutex
+
+	// pending, şu an yanıt bekleyen alışveriştir (yoksa nil).
+	pending *exchange
+
+	// hb, bu bağlantının heartbeat durumudur (mu ile korunur).
+	hb heartbeatState
+
+	// replies, okuma döngüsünün cihaza göndereceği yanıtlardır
+	// (heartbeat ve versiyon yanıtları). replyLoop tarafından yazılır.
+	replies chan []byte
+}
+
+// inbound, okuma döngüsünün bekleyen alışverişe ilettiği pakettir.
+type inbound struct {
+	cmd  CmdType
+	data []byte
+
+	// resp, cmd == CmdSdkCmdAnswer ise birleştirilmiş ve ayrıştırılmış yanıttır.
+	resp *SdkResponse
+}
+
+// exchange, yanıt bekleyen tek bir istek-yanıt alışverişidir.
+type exchange struct {
+	// method, beklenen SDK yanıtının metot adıdır (SDK komutu değilse boş).
+	method string
+
+	// accept, bu alışverişe yönlendirilecek paket tipleridir.
+	accept map[CmdType]bool
+
+	ch chan inbound
+}
+
+// newLink, conn için yeni bir link oluşturur ve okuma döngüsünü başlatır.
+func (d *Device) newLink(conn net.Conn) *link {
+	l := &link{
+		conn:    conn,
+		done:    make(chan struct{}),
+		replies: make(chan []byte, replyQueueSize),
+	}
+	go d.readLoop(l)
+	go d.replyLoop(l)
+	return l
+}
+
+// replyQueueSize, okuma döngüsünün sıraya koyabileceği yanıt sayısıdır.
+// Kuyruk doluysa yeni yanıtlar atılır; cihaz heartbeat'i tekrar sorar.
+const replyQueueSize = 8
+
+// expect, l üzerinde cmdTypes tiplerindeki paketleri bekleyen bir alışveriş
+// kaydeder. method boş değilse yalnızca bu metoda ait SDK yanıtları kabul edilir.
+// Alışveriş bitince l.release çağrılmalıdır.
+func (l *link) expect(method string, cmdTypes ...CmdType) *exchange {
+	x := &exchange{
+		method: method,
+		accept: make(map[CmdType]bool, len(cmdTypes)),
+		ch:     make(chan inbound, 4),
+	}
+	for _, c := range cmdTypes {
+		x.accept[c] = true
+	}
+	l.mu.Lock()
+	l.pending = x
+	l.mu.Unlock()
+	return x
+}
+
+// release, x hâlâ bekleyen alışverişse kaydını siler.
+// Bu noktadan sonra gelen yanıtlar geç yanıt olarak atılır.
+func (l *link) release(x *exchange) {
+	l.mu.Lock()
+	if l.pending == x {
+		l.pending = nil
+	}
+	l.mu.Unlock()
+}
+
+// deliver, paketi bekleyen alışverişe iletir. Paketi alan olmadıysa false döner.
+func (l *link) deliver(in inbound) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	x := l.pending
+	if x == nil || !x.accept[in.cmd] {
+		return false
+	}
+	if in.resp != nil && x.method != "" && in.resp.Method != "" &&
+		!strings.EqualFold(in.resp.Method, x.method) {
+		return false
+	}
+
+	select {
+	case x.ch <- in:
+		return true
+	default:
+		return false
+	}
+}
+
+// readLoop, bağlantıdan paketleri okuyup dispatch'e iletir.
+// Okuma hatası (EOF dahil) bağlantıyı kopmuş sayar.
+func (d *Device) readLoop(l *link) {
+	var asm sdkAssembler
+	for {
+		data, cmdType, err := readFrame(l.conn)
+		if err != nil {
+			l.err = err
+			close(l.done)
+			d.markBroken(l, err)
+			return
+		}
+		d.dispatch(l, &asm, cmdType, data)
+	}
+}
+
+// dispatch, okunan paketi tipine göre ilgili alıcıya yönlendirir.
+func (d *Device) dispatch(l *link, asm *sdkAssembler, cmdType CmdType, data []byte) {
+	switch cmdType {
+	case CmdHeartbeatAnswer:
+		rtt := l.heartbeatAnswered(time.Now())
+		d.logf("Heartbeat yanıtı alındı (RTT: %s)", rtt)
+
+	case CmdHeartbeatAsk:
+		d.queueReply(l, buildHeartbeatAnswer())
+
+	case CmdServiceAsk:
+		d.queueReply(l, buildVersionAnswer())
+
+	case CmdSdkCmdAnswer:
+		resp, complete, err := asm.add(data)
+		if err != nil {
+			d.logf("SDK yanıtı birleştirilemedi: %v", err)
+			return
+		}
+		if !complete {
+			return
+		}
+		if !l.deliver(inbound{cmd: cmdType, data: data, resp: resp}) {
+			d.logf("Geç veya beklenmeyen SDK yanıtı atıldı: %s (%s)", resp.Method, resp.Result)
+		}
+
+	case CmdServiceAnswer, CmdErrorAnswer, CmdFileStartAnswer, CmdFileEndAnswer, CmdReadFileAnswer:
+		if !l.deliver(inbound{cmd: cmdType, data: data}) {
+			d.logf("Beklenmeyen yanıt atıldı: %s", cmdType)
+		}
+
+	case CmdFileContentAnswer:
+		// İçerik parçaları onay beklenmeden gönderilir; bu yanıtlar yok sayılır.
+
+	default:
+		if !d.publish(cmdType, data) {
+			d.logf("Dinleyicisi olmayan paket atıldı: %s", cmdType)
+		}
+	}
+}
+
+// queueReply, pkt'yi replyLoop'un göndermesi için sıraya koyar.
+// Okuma döngüsünü bekletmemek için kuyruk doluysa paket atılır.
+func (d *Device) queueReply(l *link, pkt []byte) {
+	select {
+	case l.replies <- pkt:
+	default:
+		d.logf("Yanıt kuyruğu dolu, %s atıldı", CmdType(binary.LittleEndian.Uint16(pkt[2:4])))
+	}
+}
+
+// replyLoop, okuma döngüsünün sıraya koyduğu yanıtları l bağlantısı
+// kapanana kadar gönderir.
+func (d *Device) replyLoop(l *link) {
+	for {
+		select {
+		case <-l.done:
+			return
+		case pkt := <-l.replies:
+			if err := d.sendOn(context.Background(), l, pkt); err != nil {
+				d.logf("%s gönderilemedi: %v", CmdType(binary.LittleEndian.Uint16(pkt[2:4])), err)
+			}
+		}
+	}
+}
+
+// readFrame, r'den bir tam paket okur.
+// Huidu protokolünün sticky packet handling mantığını uygular:
+//  1. İlk 2 byte okunur → paket uzunluğu
+//  2. Kalan byte'lar okunur
+//  3. Tam paket döner
+//
+// Bu fonksiyon, birden fazla paketin tek bir TCP segment'inde
+// gelmesi durumunu doğru şekilde ele alır.
+func readFrame(r io.Reader) ([]byte, CmdType, error) {
+	// İlk 2 byte: paket uzunluğu
+	lenBuf := make([]byte, 2)
+	if _, err := io.ReadFull(r, lenBuf); err != nil {
+		return nil, 0, fmt.Errorf("paket uzunluğu okunamadı: %w", err)
+	}
+
+	pktLen := int(binary.LittleEndian.Uint16(lenBuf))
+	if pktLen < tcpHeaderLength {
+		return nil, 0, protocolErrorf(0, "geçersiz paket uzunluğu: %d", pktLen)
+	}
+
+	// Kalan veriyi oku
+	pkt := make([]byte, pktLen)
+	copy(pkt[0:2], lenBuf)
+	if _, err := io.ReadFull(r, pkt[2:]); err != nil {
+		return nil, 0, fmt.Errorf("paket verisi okunamadı: %w", err)
+	}
+
+	cmdType := CmdType(binary.LittleEndian.Uint16(pkt[2:4]))
+	return pkt, cmdType, nil
+}
+
+// ─── SDK Yanıt Birleştirme ──────────────────────────────────────────────────────
+
+// sdkAssembler, parçalı gelen CmdSdkCmdAnswer paketlerini birleştirir.
+// Büyük XML yanıtları birden fazla pakette gelebilir; her parça toplam
+// XML boyutunu ve kendi offset'ini taşır. Parçalar sırasız gelebilir;
+// yanıt, toplam boyut kadar veri alındığında tamamlanır.
+type sdkAssembler struct {
+	buf      []byte
+	received uint32
+
+	// haveFirst, mevcut yanıtın offset 0 parçasının alınıp alınmadığıdır.
+	haveFirst bool
+}
+
+// add, bir yanıt parçasını ekler. Tüm parçalar alındığında ayrıştırılmış
+// yanıtı complete=true ile döner.
+func (a *sdkAssembler) add(data []byte) (resp *SdkResponse, complete bool, err error) {
+	totalLen, offset, ok := parseSdkCmdHeader(data)
+	if !ok {
+		return nil, false, protocolErrorf(CmdSdkCmdAnswer, "SDK yanıt header'ı çözümlenemedi")
+	}
+
+	// Yeni bir yanıt başladığında buffer oluştur. İkinci bir offset 0
+	// parçası, önceki yanıtın yarım kaldığını gösterir.
+	if a.buf == nil || uint32(len(a.buf)) != totalLen || (offset == 0 && a.haveFirst) {
+		a.buf = make([]byte, totalLen)
+		a.received = 0
+		a.haveFirst = false
+	}
+	if offset == 0 {
+		a.haveFirst = true
+	}
+
+	// XML verisini kopyala
+	xmlChunk := data[sdkCmdHeaderLength:]
+	if uint64(offset)+uint64(len(xmlChunk)) > uint64(totalLen) {
+		a.buf = nil
+		return nil, false, protocolErrorf(CmdSdkCmdAnswer, "SDK yanıt parçası sınır dışı: offset=%d boyut=%d toplam=%d", offset, len(xmlChunk), totalLen)
+	}
+	copy(a.buf[offset:], xmlChunk)
+	a.received += uint32(len(xmlChunk))
+
+	// Tüm parçalar alındı mı kontrol et
+	if a.received < totalLen {
+		return nil, false, nil
+	}
+
+	// XML'i temizle ve ayrıştır
+	xmlStr := cleanXML(a.buf)
+	a.buf = nil
+	a.received = 0
+	a.haveFirst = false
+	resp, err = parseSdkResponse(xmlStr)
+	return resp, err == nil, err
+}
+
+// ─── Alışveriş Yardımcıları ─────────────────────────────────────────────────────
+
+// acquire, cihaz üzerinde istek-yanıt alışverişi yapma hakkını alır.
+// Protokol aynı anda tek alışverişe izin verdiğinden eşzamanlı komutlar
+// burada sıraya girer. Dönen release fonksiyonu mutlaka çağrılmalıdır.
+func (d *Device) acquire(ctx context.Context) (release func(), err error) {
+	select {
+	case d.cmdSem <- struct{}{}:
+		return func() { <-d.cmdSem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// beginTransfer, çok adımlı bir alışveriş (ör. dosya transferi) için
+// alışveriş hakkını alır ve aktif bağlantıyı döner. Transfer bitince
+// release çağrılmalıdır.
+func (d *Device) beginTransfer(ctx context.Context) (l *link, release func(), err error) {
+	release, err = d.acquire(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	l = d.activeLink()
+	if l == nil {
+		release()
+		return nil, nil, ErrConnectionLost
+	}
+	return l, release, nil
+}
+
+// roundTrip, l üzerinde accept tiplerinden birinde yanıt bekleyen bir
+// alışveriş kaydeder, pkts paketlerini gönderir ve yanıtı bekler.
+// Çağıran acquire ile alışveriş hakkını almış olmalıdır.
+func (d *Device) roundTrip(ctx context.Context, l *link, method string, pkts [][]byte, accept ...CmdType) (inbound, error) {
+	x := l.expect(method, accept...)
+	defer l.release(x)
+
+	for _, pkt := range pkts {
+		if err := d.sendOn(ctx, l, pkt); err != nil {
+			return inbound{}, err
+		}
+	}
+	return d.await(ctx, l, x)
+}
+
+// await, x için bir yanıt gelene, ctx iptal edilene, süre dolana veya
+// bağlantı kopana kadar bekler.
+func (d *Device) await(ctx context.Context, l *link, x *exchange) (inbound, error) {
+	timer := time.NewTimer(time.Until(d.ioDeadline(ctx)))
+	defer timer.Stop()
+
+	select {
+	case in := <-x.ch:
+		return in, nil
+	case <-ctx.Done():
+		return inbound{}, ctx.Err()
+	case <-timer.C:
+		return inbound{}, ErrTimeout
+	case <-l.done:
+		return inbound{}, fmt.Errorf("%w: %w", ErrConnectionLost, l.err)
+	}
+}
+
+// sendOn, ham byte verisini l bağlantısına yazar.
+// Yazma deadline'ı WithTimeout ile ctx'in deadline'ından erken olanıdır;
+// ctx iptal edilirse bekleyen yazma hemen sonlandırılır.
+//
+// Bir paket yarım yazılmışken ctx iptal edilirse bağlantı kapatılır,
+// çünkü yarım kalan çerçeve sonraki tüm paketlerin sınırlarını bozar.
+func (d *Device) sendOn(ctx context.Context, l *link, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.writeMu.Lock()
+	conn := l.conn
+	conn.SetWriteDeadline(d.ioDeadline(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetWriteDeadline(aLongTimeAgo)
+	})
+	n, err := conn.Write(data)
+	stop()
+	d.writeMu.Unlock()
+
+	if err != nil && ctx.Err() != nil {
+		if n > 0 && n < len(data) {
+			d.logf("Yazma iptal edildi, yarım paket nedeniyle bağlantı kapatılıyor")
+			conn.Close()
+		}
+		return ctx.Err()
+	}
+	if err != nil {
+		// markBroken olay işleyicisini tetikler; yazma kilidi bırakılmış olmalıdır.
+		return d.markBroken(l, err)
+	}
+	return nil
+}
+
+// ─── Abonelikler ────────────────────────────────────────────────────────────────
+
+// Subscribe, cihazın kendiliğinden gönderdiği (bekleyen bir isteğe yanıt
+// olmayan) cmdType tipindeki paketler için fn'i kaydeder. fn, paketin
+// 4 byte'lık başlığı dahil tamamını alır.
+//
+// fn okuma goroutine'inde çağrılır: bloklamamalı ve aynı Device üzerinde
+// senkron komut çağırmamalıdır (komutun yanıtı fn dönene kadar okunamaz).
+// Abonelikler yeniden bağlanmalarda korunur.
+//
+//	unsubscribe := dev.Subscribe(huidu.CmdGPSInfoAnswer, func(data []byte) {
+//	    log.Printf("GPS paketi: %x", data)
+//	})
+//	defer unsubscribe()
+func (d *Device) Subscribe(cmdType CmdType, fn func(data []byte)) (unsubscribe func()) {
+	d.subMu.Lock()
+	defer d.subMu.Unlock()
+
+	if d.subs == nil {
+		d.subs = make(map[CmdType]map[int]func([]byte))
+	}
+	if d.subs[cmdType] == nil {
+		d.subs[cmdType] = make(map[int]func([]byte))
+	}
+	d.nextSubID++
+	id := d.nextSubID
+	d.subs[cmdType][id] = fn
+
+	return func() {
+		d.subMu.Lock()
+		defer d.subMu.Unlock()
+		delete(d.subs[cmdType], id)
+	}
+}
+
+// SubscribeGPS, kartın gönderdiği GPS konumları için fn'i kaydeder.
+// CmdGPSInfoAnswer paketleri ParseGPSPosition ile çözülür; çözülemeyen
+// paketler loglanıp atılır. Kart konum göndermeye SetGPSReporting(true)
+// ile başlar.
+//
+// Deneysel: paket düzeni Huidu tarafından belgelenmemiştir (bkz.
+// ParseGPSPosition). Farklı düzende gönderen firmware'lerde fn hiç
+// çağrılmayabilir; bu durumda Subscribe ile ham paketler incelenebilir.
+//
+// fn, Subscribe'daki gibi okuma goroutine'inde çağrılır: bloklamamalı ve
+// aynı Device üzerinde senkron komut çağırmamalıdır. Konumlar bir kanala
+// aktarılacaksa tampon dolduğunda atlanmalıdır:
+//
+//	positions := make(chan huidu.GPSPosition, 16)
+//	stop := dev.SubscribeGPS(func(pos huidu.GPSPosition) {
+//	    select {
+//	    case positions <- pos:
+//	    default: // tüketici yetişemiyor; konum atlanır
+//	    }
+//	})
+//	defer stop()
+func (d *Device) SubscribeGPS(fn func(GPSPosition)) (unsubscribe func()) {
+	return d.Subscribe(CmdGPSInfoAnswer, func(data []byte) {
+		pos, err := ParseGPSPosition(data)
+		if err != nil {
+			d.logf("GPS paketi çözülemedi: %v", err)
+			return
+		}
+		fn(pos)
+	})
+}
+
+// publish, paketi cmdType için kayıtlı dinleyicilere dağıtır.
+// En az bir dinleyici varsa true döner.
+func (d *Device) publish(cmdType CmdType, data []byte) bool {
+	d.subMu.Lock()
+	fns := make([]func([]byte), 0, len(d.subs[cmdType]))
+	for _, fn := range d.subs[cmdType] {
+		fns = append(fns, fn)
+	}
+	d.subMu.Unlock()
+
+	for _, fn := range fns {
+		fn(data)
+	}
+	return len(fns) > 0
+}
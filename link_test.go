@@ -0,0 +1,79 @@
+package huidu_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+	"github.com/alparslanahmed/huidu-led/huidutest"
+)
+
+// brokenWriteConn, fail ayarlandığında her yazmayı reddeden bağlantıdır.
+type brokenWriteConn struct {
+	net.Conn
+	fail *atomic.Bool
+}
+
+func (c brokenWriteConn) Write(p []byte) (int, error) {
+	if c.fail.Load() {
+		return 0, errors.New("yazma reddedildi")
+	}
+	return c.Conn.Write(p)
+}
+
+// Yazma hatasıyla kopan bağlantının olay işleyicisi, cihaz üzerinde komut
+// çalıştırabilmelidir (ör. yeniden bağlanma). Olaylar kilit tutulurken
+// senkron iletildiğinde bu senaryo kilitlenir.
+func TestEventHandlerMayReconnectAfterWriteError(t *testing.T) {
+	ctrl := huidutest.NewController()
+	defer ctrl.Close()
+
+	var fail atomic.Bool
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return brokenWriteConn{Conn: ctrl.Pipe(), fail: &fail}, nil
+	}
+
+	var dev *huidu.Device
+	reconnected := make(chan error, 1)
+	dev = huidu.NewDevice("sim", huidu.DefaultPort,
+		huidu.WithDialer(dial),
+		huidu.WithTimeout(2*time.Second),
+		huidu.WithEventHandler(func(ev huidu.Event) {
+			if ev.Type != huidu.EventDisconnected {
+				return
+			}
+			fail.Store(false)
+			if err := dev.Connect(); err != nil {
+				reconnected <- err
+				return
+			}
+			_, err := dev.GetDeviceInfo()
+			reconnected <- err
+		}),
+	)
+	if err := dev.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer dev.Close()
+
+	fail.Store(true)
+	if _, err := dev.GetLuminanceInfo(); !errors.Is(err, huidu.ErrConnectionLost) {
+		t.Fatalf("GetLuminanceInfo hatası = %v, ErrConnectionLost bekleniyordu", err)
+	}
+
+	select {
+	case err := <-reconnected:
+		if err != nil {
+			t.Fatalf("olay işleyicisindeki komut başarısız: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("olay işleyicisi kilitlendi")
+	}
+	if !dev.IsConnected() {
+		t.Fatal("yeniden bağlanma sonrası cihaz bağlı değil")
+	}
+}
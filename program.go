@@ -1,7 +1,10 @@
 package huidu
 
 import (
+	"context"
+	"encoding/xml"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -81,6 +84,16 @@ func (s *Screen) AddProgramWithConfig(config ProgramConfig) *Program {
 	return p
 }
 
+// ProgramByGUID, GUID'i verilen programı döner (yoksa nil).
+func (s *Screen) ProgramByGUID(guid string) *Program {
+	for _, p := range s.Programs {
+		if p.GUID == guid {
+			return p
+		}
+	}
+	return nil
+}
+
 // toXML, Screen'i SDK XML formatına dönüştürür.
 func (s *Screen) toXML() string {
 	var screenAttrs []string
@@ -88,7 +101,11 @@ func (s *Screen) toXML() string {
 		ts := time.Now().UnixMilli()
 		screenAttrs = append(screenAttrs, "timeStamps", fmt.Sprintf("%d", ts))
 	}
+	return s.xmlWithAttrs(screenAttrs)
+}
 
+// xmlWithAttrs, Screen'i verilen <screen> attribute'larıyla XML'e dönüştürür.
+func (s *Screen) xmlWithAttrs(screenAttrs []string) string {
 	var children []string
 	for _, p := range s.Programs {
 		children = append(children, p.toXML())
@@ -97,6 +114,86 @@ func (s *Screen) toXML() string {
 	return xmlElementWithChildren("screen", screenAttrs, children...)
 }
 
+// ─── Screen XML Dosyaları ───────────────────────────────────────────────────────
+//
+// Program tanımları, cihaza gönderilen SDK XML formatında dosyalara
+// yazılıp tekrar okunabilir. Yazılan XML'e timeStamps eklenmez; böylece
+// aynı Screen her zaman aynı çıktıyı üretir ve dosyalar kod incelemesinde
+// karşılaştırılabilir.
+//
+// Okuma sırasında program, alan ve öğe GUID'leri, oynatma kontrolü, alan
+// dikdörtgenleri, alpha ve tüm öğe attribute'ları korunur. toXML'in
+// varsayılanla doldurduğu değerler (ör. Speed 0 → 4) doldurulmuş halleriyle
+// okunur; bu nedenle okunan Screen tekrar yazıldığında aynı XML elde edilir.
+
+// ParseScreenXML, SDK XML formatındaki program tanımını Screen'e dönüştürür.
+// <screen> kökü yoksa üst seviyedeki <program> elemanları okunur.
+// Dönen Screen, NewScreen gibi yeni bir ekran olarak işaretlenir;
+// SendScreen ile gönderildiğinde güncel timeStamps eklenir.
+//
+//	data, _ := os.ReadFile("vitrin.xml")
+//	screen, err := huidu.ParseScreenXML(data)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	err = dev.SendScreen(screen)
+func ParseScreenXML(data []byte) (*Screen, error) {
+	screen, err := parseScreenXML(cleanXML(data))
+	if err != nil {
+		return nil, err
+	}
+	screen.isNew = true
+	return screen, nil
+}
+
+// WriteXML, Screen'i girintili SDK XML formatında w'ya yazar.
+// Çıktı ParseScreenXML ile tekrar okunabilir.
+//
+//	f, _ := os.Create("vitrin.xml")
+//	defer f.Close()
+//	err := screen.WriteXML(f)
+func (s *Screen) WriteXML(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(s); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// MarshalXML, xml.Marshaler arayüzünü uygular. Screen her zaman <screen>
+// elemanı olarak, timeStamps olmadan yazılır; start'ın adı kullanılmaz.
+func (s Screen) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	dec := xml.NewDecoder(strings.NewReader(s.xmlWithAttrs(nil)))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := e.EncodeToken(tok); err != nil {
+			return err
+		}
+	}
+}
+
+// UnmarshalXML, xml.Unmarshaler arayüzünü uygular (bkz. ParseScreenXML).
+func (s *Screen) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var sx screenXML
+	if err := d.DecodeElement(&sx, &start); err != nil {
+		return fmt.Errorf("ekran XML'i ayrıştırılamadı: %w", err)
+	}
+	*s = *sx.screen()
+	s.isNew = true
+	return nil
+}
+
 // ─── Program (Program) ──────────────────────────────────────────────────────────
 
 // ProgramConfig, program oluşturma için yapılandırma parametreleridir.
@@ -244,6 +341,69 @@ type Area struct {
 // areaItem, alana eklenebilecek içerik öğelerinin ortak arayüzüdür.
 type areaItem interface {
 	toXML() string
+
+	// view, öğenin salt okunur görünümünü döner (bkz. Area.Items).
+	view() AreaItem
+}
+
+// ItemKind, alan içerik öğesinin tipidir.
+type ItemKind string
+
+const (
+	ItemText  ItemKind = "text"  // Metin (AddText)
+	ItemImage ItemKind = "image" // Görsel (AddImage)
+	ItemVideo ItemKind = "video" // Video (AddVideo)
+	ItemClock ItemKind = "clock" // Saat (AddClock)
+)
+
+// AreaItem, alandaki bir içerik öğesinin salt okunur görünümüdür.
+// Kind'a göre yalnızca ilgili yapılandırma alanı doludur.
+type AreaItem struct {
+	Kind     ItemKind // Öğe tipi
+	GUID     string   // Öğenin benzersiz kimliği
+	Name     string   // Öğenin opsiyonel adı
+	Text     string   // Metin içeriği (yalnızca ItemText)
+	FileName string   // Dosya adı (ItemImage ve ItemVideo)
+
+	TextConfig  *TextConfig  // ItemText yapılandırması
+	ImageConfig *ImageConfig // ItemImage yapılandırması
+	VideoConfig *VideoConfig // ItemVideo yapılandırması
+	ClockConfig *ClockConfig // ItemClock yapılandırması
+
+	// RawXML, kütüphanenin modellemediği öğe tiplerinde (Kind yukarıdaki
+	// sabitlerden biri değilse) öğenin kartta okunduğu haliyle XML'idir.
+	RawXML string
+}
+
+// Items, alandaki içerik öğelerinin görünümlerini oynatma sırasıyla döner.
+// Dönen değerler kopyadır; değiştirmek alanı etkilemez.
+//
+//	screen, _ := dev.GetScreen()
+//	for _, it := range screen.Programs[0].Areas[0].Items() {
+//	    fmt.Println(it.Kind, it.Text, it.FileName)
+//	}
+func (a *Area) Items() []AreaItem {
+	views := make([]AreaItem, len(a.items))
+	for i, item := range a.items {
+		views[i] = item.view()
+	}
+	return views
+}
+
+// RemoveItem, i sıradaki içerik öğesini alandan kaldırır.
+// i geçersizse false döner.
+func (a *Area) RemoveItem(i int) bool {
+	if i < 0 || i >= len(a.items) {
+		return false
+	}
+	a.items = append(a.items[:i], a.items[i+1:]...)
+	return true
+}
+
+// ClearItems, alandaki tüm içerik öğelerini kaldırır.
+// Alanın konumu ve GUID'i korunur; yeni öğeler Add* ile eklenebilir.
+func (a *Area) ClearItems() {
+	a.items = nil
 }
 
 // AddText, alana metin öğesi ekler.
@@ -258,6 +418,11 @@ type areaItem interface {
 //	    Speed:    4,
 //	})
 func (a *Area) AddText(text string, config TextConfig) {
+	a.items = append(a.items, newTextItem(uuid.New().String(), text, config))
+}
+
+// newTextItem, AddText varsayılanlarını uygulayarak metin öğesi oluşturur.
+func newTextItem(guid, text string, config TextConfig) *textItem {
 	if config.FontName == "" {
 		config.FontName = "Arial"
 	}
@@ -274,13 +439,12 @@ func (a *Area) AddText(text string, config TextConfig) {
 		config.VAlign = VAlignMiddle
 	}
 
-	item := &textItem{
-		guid:   uuid.New().String(),
+	return &textItem{
+		guid:   guid,
 		name:   config.Name,
 		text:   text,
 		config: config,
 	}
-	a.items = append(a.items, item)
 }
 
 // AddImage, alana görsel öğesi ekler.
@@ -292,17 +456,21 @@ func (a *Area) AddText(text string, config TextConfig) {
 //	    Duration: 5,
 //	})
 func (a *Area) AddImage(fileName string, config ImageConfig) {
+	a.items = append(a.items, newImageItem(uuid.New().String(), fileName, config))
+}
+
+// newImageItem, AddImage varsayılanlarını uygulayarak görsel öğesi oluşturur.
+func newImageItem(guid, fileName string, config ImageConfig) *imageItem {
 	if config.Fit == "" {
 		config.Fit = ImageFitStretch
 	}
 
-	item := &imageItem{
-		guid:     uuid.New().String(),
+	return &imageItem{
+		guid:     guid,
 		name:     config.Name,
 		fileName: fileName,
 		config:   config,
 	}
-	a.items = append(a.items, item)
 }
 
 // AddVideo, alana video öğesi ekler.
@@ -312,13 +480,17 @@ func (a *Area) AddImage(fileName string, config ImageConfig) {
 //	    AspectRatio: true,
 //	})
 func (a *Area) AddVideo(fileName string, config VideoConfig) {
-	item := &videoItem{
-		guid:     uuid.New().String(),
+	a.items = append(a.items, newVideoItem(uuid.New().String(), fileName, config))
+}
+
+// newVideoItem, video öğesi oluşturur.
+func newVideoItem(guid, fileName string, config VideoConfig) *videoItem {
+	return &videoItem{
+		guid:     guid,
 		name:     config.Name,
 		fileName: fileName,
 		config:   config,
 	}
-	a.items = append(a.items, item)
 }
 
 // AddClock, alana saat öğesi ekler.
@@ -332,16 +504,20 @@ func (a *Area) AddVideo(fileName string, config VideoConfig) {
 //	    TimeColor:   "#00ff00",
 //	})
 func (a *Area) AddClock(config ClockConfig) {
+	a.items = append(a.items, newClockItem(uuid.New().String(), config))
+}
+
+// newClockItem, AddClock varsayılanlarını uygulayarak saat öğesi oluşturur.
+func newClockItem(guid string, config ClockConfig) *clockItem {
 	if config.Type == "" {
 		config.Type = ClockDigital
 	}
 
-	item := &clockItem{
-		guid:   uuid.New().String(),
+	return &clockItem{
+		guid:   guid,
 		name:   config.Name,
 		config: config,
 	}
-	a.items = append(a.items, item)
 }
 
 // toXML, Area'yı SDK XML formatına dönüştürür.
@@ -373,136 +549,136 @@ func (a *Area) toXML() string {
 // TextConfig, metin öğesinin yapılandırma parametreleridir.
 type TextConfig struct {
 	// Name, öğenin opsiyonel adıdır.
-	Name string
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
 
 	// FontName, font adıdır (varsayılan: "Arial").
-	FontName string
+	FontName string `json:"fontName,omitempty" yaml:"fontName,omitempty"`
 
 	// FontSize, font boyutudur (varsayılan: 12).
-	FontSize int
+	FontSize int `json:"fontSize,omitempty" yaml:"fontSize,omitempty"`
 
 	// Color, metin rengidir (#RRGGBB formatında, varsayılan: "#ff0000").
-	Color string
+	Color string `json:"color,omitempty" yaml:"color,omitempty"`
 
 	// Bold, kalın yazı bayrağıdır.
-	Bold bool
+	Bold bool `json:"bold,omitempty" yaml:"bold,omitempty"`
 
 	// Italic, italik yazı bayrağıdır.
-	Italic bool
+	Italic bool `json:"italic,omitempty" yaml:"italic,omitempty"`
 
 	// Underline, altı çizili yazı bayrağıdır.
-	Underline bool
+	Underline bool `json:"underline,omitempty" yaml:"underline,omitempty"`
 
 	// HAlign, yatay hizalama (varsayılan: center).
-	HAlign HAlign
+	HAlign HAlign `json:"hAlign,omitempty" yaml:"hAlign,omitempty"`
 
 	// VAlign, dikey hizalama (varsayılan: middle).
-	VAlign VAlign
+	VAlign VAlign `json:"vAlign,omitempty" yaml:"vAlign,omitempty"`
 
 	// BackgroundColor, arka plan rengidir (#RRGGBB formatında).
 	// Boş bırakılırsa arka plan rengi kullanılmaz.
-	BackgroundColor string
+	BackgroundColor string `json:"backgroundColor,omitempty" yaml:"backgroundColor,omitempty"`
 
 	// Effect, giriş efekti tipidir (varsayılan: EffectImmediate).
-	Effect EffectType
+	Effect EffectType `json:"effect,omitempty" yaml:"effect,omitempty"`
 
 	// OutEffect, çıkış efekti tipidir.
-	OutEffect EffectType
+	OutEffect EffectType `json:"outEffect,omitempty" yaml:"outEffect,omitempty"`
 
 	// Speed, efekt hızıdır (1-10, varsayılan: 4).
-	Speed int
+	Speed int `json:"speed,omitempty" yaml:"speed,omitempty"`
 
 	// Duration, gösterim süresidir (saniye cinsinden, varsayılan: 3).
-	Duration int
+	Duration int `json:"duration,omitempty" yaml:"duration,omitempty"`
 }
 
 // ImageConfig, görsel öğesinin yapılandırma parametreleridir.
 type ImageConfig struct {
 	// Name, öğenin opsiyonel adıdır.
-	Name string
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
 
 	// Fit, görselin alana nasıl yerleştirileceğidir (varsayılan: stretch).
-	Fit ImageFit
+	Fit ImageFit `json:"fit,omitempty" yaml:"fit,omitempty"`
 
 	// Effect, giriş efekti tipidir.
-	Effect EffectType
+	Effect EffectType `json:"effect,omitempty" yaml:"effect,omitempty"`
 
 	// OutEffect, çıkış efekti tipidir.
-	OutEffect EffectType
+	OutEffect EffectType `json:"outEffect,omitempty" yaml:"outEffect,omitempty"`
 
 	// Speed, efekt hızıdır (1-10, varsayılan: 4).
-	Speed int
+	Speed int `json:"speed,omitempty" yaml:"speed,omitempty"`
 
 	// Duration, gösterim süresidir (saniye, varsayılan: 3).
-	Duration int
+	Duration int `json:"duration,omitempty" yaml:"duration,omitempty"`
 }
 
 // VideoConfig, video öğesinin yapılandırma parametreleridir.
 type VideoConfig struct {
 	// Name, öğenin opsiyonel adıdır.
-	Name string
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
 
 	// AspectRatio, en-boy oranının korunup korunmayacağını belirtir.
-	AspectRatio bool
+	AspectRatio bool `json:"aspectRatio,omitempty" yaml:"aspectRatio,omitempty"`
 }
 
 // ClockConfig, saat öğesinin yapılandırma parametreleridir.
 type ClockConfig struct {
 	// Name, öğenin opsiyonel adıdır.
-	Name string
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
 
 	// Type, saat tipidir (digital veya dial). Varsayılan: digital.
-	Type ClockType
+	Type ClockType `json:"type,omitempty" yaml:"type,omitempty"`
 
 	// Timezone, saat dilimi (ör: "+8:00"). Boş ise cihaz saati kullanılır.
-	Timezone string
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
 
 	// Adjust, zaman ince ayarı (ör: "+00:05:00", "-00:05:00").
-	Adjust string
+	Adjust string `json:"adjust,omitempty" yaml:"adjust,omitempty"`
 
 	// ShowTitle, başlık gösterimi.
-	ShowTitle bool
+	ShowTitle bool `json:"showTitle,omitempty" yaml:"showTitle,omitempty"`
 
 	// TitleValue, başlık metni.
-	TitleValue string
+	TitleValue string `json:"titleValue,omitempty" yaml:"titleValue,omitempty"`
 
 	// TitleColor, başlık rengi (#RRGGBB).
-	TitleColor string
+	TitleColor string `json:"titleColor,omitempty" yaml:"titleColor,omitempty"`
 
 	// ShowDate, tarih gösterimi.
-	ShowDate bool
+	ShowDate bool `json:"showDate,omitempty" yaml:"showDate,omitempty"`
 
 	// DateFormat, tarih formatı (1-7):
 	// 1: YYYY/MM/DD, 2: MM/DD/YYYY, 3: DD/MM/YYYY,
 	// 4: Jan DD YYYY, 5: DD Jan YYYY, 6: YYYY年MM月DD日, 7: MM月DD日
-	DateFormat int
+	DateFormat int `json:"dateFormat,omitempty" yaml:"dateFormat,omitempty"`
 
 	// DateColor, tarih rengi (#RRGGBB).
-	DateColor string
+	DateColor string `json:"dateColor,omitempty" yaml:"dateColor,omitempty"`
 
 	// ShowWeek, haftanın günü gösterimi.
-	ShowWeek bool
+	ShowWeek bool `json:"showWeek,omitempty" yaml:"showWeek,omitempty"`
 
 	// WeekFormat, gün formatı (1: yerel dil, 2: Monday, 3: Mon).
-	WeekFormat int
+	WeekFormat int `json:"weekFormat,omitempty" yaml:"weekFormat,omitempty"`
 
 	// WeekColor, gün rengi (#RRGGBB).
-	WeekColor string
+	WeekColor string `json:"weekColor,omitempty" yaml:"weekColor,omitempty"`
 
 	// ShowTime, saat gösterimi.
-	ShowTime bool
+	ShowTime bool `json:"showTime,omitempty" yaml:"showTime,omitempty"`
 
 	// TimeFormat, saat formatı (1: hh:mm:ss, 2: hh:ss, 3: hh時mm分ss秒, 4: hh時mm分).
-	TimeFormat int
+	TimeFormat int `json:"timeFormat,omitempty" yaml:"timeFormat,omitempty"`
 
 	// TimeColor, saat rengi (#RRGGBB).
-	TimeColor string
+	TimeColor string `json:"timeColor,omitempty" yaml:"timeColor,omitempty"`
 
 	// ShowLunarCalendar, ay takvimi (çin takvimi) gösterimi.
-	ShowLunarCalendar bool
+	ShowLunarCalendar bool `json:"showLunarCalendar,omitempty" yaml:"showLunarCalendar,omitempty"`
 
 	// LunarCalendarColor, ay takvimi rengi (#RRGGBB).
-	LunarCalendarColor string
+	LunarCalendarColor string `json:"lunarCalendarColor,omitempty" yaml:"lunarCalendarColor,omitempty"`
 }
 
 // ─── İçerik Öğesi Uygulamaları ──────────────────────────────────────────────────
@@ -554,6 +730,11 @@ func (t *textItem) toXML() string {
 	return xmlElementWithChildren("text", attrs, styleXML, stringXML, fontXML, effectXML)
 }
 
+func (t *textItem) view() AreaItem {
+	c := t.config
+	return AreaItem{Kind: ItemText, GUID: t.guid, Name: t.name, Text: t.text, TextConfig: &c}
+}
+
 // imageItem, görsel içerik öğesidir.
 type imageItem struct {
 	guid     string
@@ -577,6 +758,11 @@ func (i *imageItem) toXML() string {
 	return xmlElementWithChildren("image", attrs, effectXML, fileXML)
 }
 
+func (i *imageItem) view() AreaItem {
+	c := i.config
+	return AreaItem{Kind: ItemImage, GUID: i.guid, Name: i.name, FileName: i.fileName, ImageConfig: &c}
+}
+
 // videoItem, video içerik öğesidir.
 type videoItem struct {
 	guid     string
@@ -598,6 +784,11 @@ func (v *videoItem) toXML() string {
 	return xmlElementWithChildren("video", attrs, fileXML)
 }
 
+func (v *videoItem) view() AreaItem {
+	c := v.config
+	return AreaItem{Kind: ItemVideo, GUID: v.guid, Name: v.name, FileName: v.fileName, VideoConfig: &c}
+}
+
 // clockItem, saat içerik öğesidir.
 type clockItem struct {
 	guid   string
@@ -686,6 +877,31 @@ func (cl *clockItem) toXML() string {
 	return xmlElementWithChildren("clock", attrs, children...)
 }
 
+func (cl *clockItem) view() AreaItem {
+	c := cl.config
+	return AreaItem{Kind: ItemClock, GUID: cl.guid, Name: cl.name, ClockConfig: &c}
+}
+
+// ─── Tanınmayan Öğe ─────────────────────────────────────────────────────────────
+
+// rawItem, kütüphanenin modellemediği bir öğe tipidir (ör. firmware'e özgü
+// sayaç veya sensör öğeleri). GetScreen ile okunan ekran geri
+// gönderildiğinde bu öğeler kaybolmasın diye okunduğu haliyle yazılır.
+type rawItem struct {
+	kind ItemKind
+	guid string
+	name string
+	xml  string
+}
+
+func (r *rawItem) toXML() string {
+	return r.xml
+}
+
+func (r *rawItem) view() AreaItem {
+	return AreaItem{Kind: r.kind, GUID: r.guid, Name: r.name, RawXML: r.xml}
+}
+
 // ─── Efekt Yardımcısı ───────────────────────────────────────────────────────────
 
 // buildEffectXML, efekt XML elementini oluşturur.
@@ -718,25 +934,81 @@ func buildEffectXML(inEffect, outEffect EffectType, speed, duration int) string
 //	area.AddText("Test", huidu.TextConfig{Color: "#ff0000"})
 //	err := dev.SendScreen(screen)
 func (d *Device) SendScreen(screen *Screen) error {
+	return d.SendScreenContext(context.Background(), screen)
+}
+
+// SendScreenContext, SendScreen ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) SendScreenContext(ctx context.Context, screen *Screen) error {
 	if err := d.ensureConnected(); err != nil {
 		return err
 	}
 
 	screenXML := screen.toXML()
-	fullXML := buildSdkXML(d.sdkGUID, MethodAddProgram, screenXML)
+	fullXML := buildSdkXML(d.GUID(), MethodAddProgram, screenXML)
 
-	resp, err := d.sendSdkCmdAndReceive([]byte(fullXML))
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(fullXML))
 	if err != nil {
 		return err
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("SendScreen başarısız: %s", resp.Result)
+		return newResultError("SendScreen", resp)
 	}
 
+	d.setScreen(screenXML)
 	return nil
 }
 
+// GetScreen, cihazda kayıtlı programları okur ve Screen olarak döner.
+//
+// Programların, alanların ve öğelerin GUID'leri korunur; dönen ekran
+// düzenlenip SendScreen ile geri gönderilebilir, tek bir program
+// UpdateProgram ile güncellenebilir.
+//
+//	screen, err := dev.GetScreen()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	area := screen.Programs[0].Areas[0]
+//	area.ClearItems()
+//	area.AddText("Yeni duyuru", huidu.TextConfig{Color: huidu.ColorYellow})
+//	err = dev.SendScreen(screen)
+func (d *Device) GetScreen() (*Screen, error) {
+	return d.GetScreenContext(context.Background())
+}
+
+// GetScreenContext, GetScreen ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) GetScreenContext(ctx context.Context) (*Screen, error) {
+	if err := d.ensureConnected(); err != nil {
+		return nil, err
+	}
+
+	xmlData := buildSdkXML(d.GUID(), MethodGetProgram, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		return nil, newResultError("GetScreen", resp)
+	}
+
+	screen, err := parseScreenXML(resp.InnerXML)
+	if err != nil {
+		return nil, err
+	}
+
+	d.setScreen(resp.InnerXML)
+	return screen, nil
+}
+
+// setScreen, cihazda olduğu bilinen ekranın XML'ini saklar (bkz. CurrentProgram).
+func (d *Device) setScreen(screenXML string) {
+	d.mu.Lock()
+	d.screenXML = screenXML
+	d.mu.Unlock()
+}
+
 // SendText, ekrana tek bir metin göndermek için kısayol fonksiyondur.
 // Tam ekran metin alanı oluşturur ve gönderir.
 //
@@ -750,6 +1022,11 @@ func (d *Device) SendScreen(screen *Screen) error {
 //	    Speed:    3,
 //	})
 func (d *Device) SendText(text string, config TextConfig) error {
+	return d.SendTextContext(context.Background(), text, config)
+}
+
+// SendTextContext, SendText ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) SendTextContext(ctx context.Context, text string, config TextConfig) error {
 	w, h := 64, 32
 	d.mu.Lock()
 	if d.info != nil {
@@ -763,7 +1040,7 @@ func (d *Device) SendText(text string, config TextConfig) error {
 	area := prog.AddArea(0, 0, w, h)
 	area.AddText(text, config)
 
-	return d.SendScreen(screen)
+	return d.SendScreenContext(ctx, screen)
 }
 
 // ─── Program update/delete ──────────────────────────────────────────────────────
@@ -771,20 +1048,25 @@ func (d *Device) SendText(text string, config TextConfig) error {
 // UpdateProgram, belirtilen programı günceller.
 // Program'ın GUID'i mevcut bir programla eşleşmelidir.
 func (d *Device) UpdateProgram(program *Program) error {
+	return d.UpdateProgramContext(context.Background(), program)
+}
+
+// UpdateProgramContext, UpdateProgram ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) UpdateProgramContext(ctx context.Context, program *Program) error {
 	if err := d.ensureConnected(); err != nil {
 		return err
 	}
 
 	programXML := program.toXML()
-	fullXML := buildSdkXML(d.sdkGUID, MethodUpdateProgram, programXML)
+	fullXML := buildSdkXML(d.GUID(), MethodUpdateProgram, programXML)
 
-	resp, err := d.sendSdkCmdAndReceive([]byte(fullXML))
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(fullXML))
 	if err != nil {
 		return err
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("UpdateProgram başarısız: %s", resp.Result)
+		return newResultError("UpdateProgram", resp)
 	}
 
 	return nil
@@ -793,20 +1075,113 @@ func (d *Device) UpdateProgram(program *Program) error {
 // DeleteProgram, belirtilen programı siler.
 // Program'ın GUID'i ile eşleşen program cihazdan kaldırılır.
 func (d *Device) DeleteProgram(program *Program) error {
+	return d.DeleteProgramContext(context.Background(), program)
+}
+
+// DeleteProgramContext, DeleteProgram ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) DeleteProgramContext(ctx context.Context, program *Program) error {
 	if err := d.ensureConnected(); err != nil {
 		return err
 	}
 
 	programXML := program.toXML()
-	fullXML := buildSdkXML(d.sdkGUID, MethodDeleteProgram, programXML)
+	fullXML := buildSdkXML(d.GUID(), MethodDeleteProgram, programXML)
+
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(fullXML))
+	if err != nil {
+		return err
+	}
+
+	if !resp.IsSuccess() {
+		return newResultError("DeleteProgram", resp)
+	}
+
+	return nil
+}
+
+// ─── Oynatma Kontrolü ───────────────────────────────────────────────────────────
+//
+// SDK'nın oynatma moduyla ilgili yalnızca iki komutu vardır:
+// GetCurrentPlayProgramGUID ve SetPlayTypeToNormal. Realtime moda geçmek
+// için ayrı bir komut yoktur; program realtime bayrağıyla
+// (ProgramConfig.Realtime) gönderildiğinde kart onu hemen oynatır. Kesme
+// (interrupt) oynatmasına geçiren bir istemci komutu da protokolde yer
+// almadığından kütüphane yalnızca normal moda dönüşü sunar.
+
+// CurrentProgram, şu an oynatılan programın GUID'ini sorgular.
+//
+// Bu Device ile son gönderilen (SendScreen) veya okunan (GetScreen) ekranda
+// bu GUID'e sahip bir program varsa o da döner; yoksa program nil'dir.
+// Program, kartın aldığı XML'den her çağrıda yeniden oluşturulur: SendScreen
+// sonrasında Screen'de yapılan değişiklikler yansımaz, dönen Program'ın
+// değiştirilmesi de sonraki çağrıları etkilemez. Kart başka bir istemci
+// tarafından güncellendiyse önce GetScreen çağrılmalıdır.
+//
+//	guid, prog, err := dev.CurrentProgram()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if prog != nil {
+//	    fmt.Printf("Yayında: %s (%s)\n", prog.Name, guid)
+//	}
+func (d *Device) CurrentProgram() (guid string, program *Program, err error) {
+	return d.CurrentProgramContext(context.Background())
+}
+
+// CurrentProgramContext, CurrentProgram ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) CurrentProgramContext(ctx context.Context) (guid string, program *Program, err error) {
+	if err := d.ensureConnected(); err != nil {
+		return "", nil, err
+	}
+
+	xmlData := buildSdkXML(d.GUID(), MethodGetCurrentPlayProgramGUID, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !resp.IsSuccess() {
+		return "", nil, newResultError("CurrentProgram", resp)
+	}
+
+	guid = parseCurrentProgramXML(resp.InnerXML)
+
+	d.mu.Lock()
+	screenXML := d.screenXML
+	d.mu.Unlock()
+	if screenXML != "" && guid != "" {
+		if screen, err := parseScreenXML(screenXML); err == nil {
+			program = screen.ProgramByGUID(guid)
+		}
+	}
+	return guid, program, nil
+}
+
+// ResumeNormalPlayback, kartı normal oynatma moduna döndürür.
+//
+// Realtime (ProgramConfig.Realtime) programlar ve kesme (interrupt)
+// oynatmaları normal program listesinin önüne geçer; bu komut kartı
+// bu durumdan çıkarıp kayıtlı program listesini oynatmaya döndürür.
+//
+//	err := dev.ResumeNormalPlayback()
+func (d *Device) ResumeNormalPlayback() error {
+	return d.ResumeNormalPlaybackContext(context.Background())
+}
+
+// ResumeNormalPlaybackContext, ResumeNormalPlayback ile aynıdır; ctx iptal edildiğinde komut yarıda kesilir.
+func (d *Device) ResumeNormalPlaybackContext(ctx context.Context) error {
+	if err := d.ensureConnected(); err != nil {
+		return err
+	}
 
-	resp, err := d.sendSdkCmdAndReceive([]byte(fullXML))
+	xmlData := buildSdkXML(d.GUID(), MethodSetPlayTypeToNormal, "")
+	resp, err := d.sendSdkCmdAndReceive(ctx, []byte(xmlData))
 	if err != nil {
 		return err
 	}
 
 	if !resp.IsSuccess() {
-		return fmt.Errorf("DeleteProgram başarısız: %s", resp.Result)
+		return newResultError("ResumeNormalPlayback", resp)
 	}
 
 	return nil
@@ -0,0 +1,236 @@
+package huidu_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+	"github.com/alparslanahmed/huidu-led/huidutest"
+)
+
+// unknownItemScreen, kütüphanenin modellemediği öğe tipleri (counter,
+// temperature) içeren bir ekrandır.
+const unknownItemScreen = `<screen>
+  <program guid="p-1" type="normal" id="0" name="Sayaç">
+    <playControl count="1" disabled="false"/>
+    <area guid="a-1" name="ana" alpha="255">
+      <rectangle x="0" y="0" width="128" height="32"/>
+      <resources>
+        <text guid="t-1" name="başlık"><string>Kalan &amp; gün</string></text>
+        <counter guid="c-1" name="geri sayım" target="2027-01-01 00:00:00">
+          <font name="Arial" size="büyük" color="#ff0000"/>
+          <unit day="true" hour="false"/>
+        </counter>
+        <temperature guid="s-1" unit="celsius"/>
+      </resources>
+    </area>
+  </program>
+</screen>`
+
+// canonXML, girinti boşluklarını atıp kendiliğinden kapanan elemanları
+// açarak s'yi karşılaştırılabilir biçime getirir.
+func canonXML(t *testing.T, s string) string {
+	t.Helper()
+	dec := xml.NewDecoder(strings.NewReader(s))
+	var buf strings.Builder
+	enc := xml.NewEncoder(&buf)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("XML okunamadı: %v\n%s", err, s)
+		}
+		if cd, ok := tok.(xml.CharData); ok && strings.TrimSpace(string(cd)) == "" {
+			continue
+		}
+		if err := enc.EncodeToken(tok); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestParseScreenKeepsUnknownItems(t *testing.T) {
+	screen, err := huidu.ParseScreenXML([]byte(unknownItemScreen))
+	if err != nil {
+		t.Fatalf("ParseScreenXML: %v", err)
+	}
+
+	items := screen.Programs[0].Areas[0].Items()
+	if len(items) != 3 {
+		t.Fatalf("öğe sayısı = %d, beklenen 3", len(items))
+	}
+	tests := []struct {
+		kind huidu.ItemKind
+		guid string
+		name string
+		raw  []string // RawXML'de bulunması gerekenler
+	}{
+		{huidu.ItemText, "t-1", "başlık", nil},
+		{"counter", "c-1", "geri sayım", []string{`target="2027-01-01 00:00:00"`, `size="büyük"`, `<unit day="true" hour="false"/>`}},
+		{"temperature", "s-1", "", []string{`<temperature guid="s-1" unit="celsius"/>`}},
+	}
+	for i, tt := range tests {
+		it := items[i]
+		if it.Kind != tt.kind || it.GUID != tt.guid || it.Name != tt.name {
+			t.Errorf("öğe %d = %s/%s/%s, beklenen %s/%s/%s", i, it.Kind, it.GUID, it.Name, tt.kind, tt.guid, tt.name)
+		}
+		if tt.raw == nil && it.RawXML != "" {
+			t.Errorf("öğe %d: bilinen tipte RawXML dolu: %s", i, it.RawXML)
+		}
+		for _, want := range tt.raw {
+			if !strings.Contains(it.RawXML, want) {
+				t.Errorf("öğe %d RawXML = %s, %s bekleniyordu", i, it.RawXML, want)
+			}
+		}
+	}
+
+	// Yeniden yazılan XML tekrar okunduğunda öğeler aynı kalmalı
+	var buf strings.Builder
+	if err := screen.WriteXML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	again, err := huidu.ParseScreenXML([]byte(buf.String()))
+	if err != nil {
+		t.Fatalf("yazılan XML okunamadı: %v\n%s", err, buf.String())
+	}
+	got := again.Programs[0].Areas[0].Items()
+	if len(got) != 3 {
+		t.Fatalf("ikinci okumada öğe sayısı = %d", len(got))
+	}
+	for i := 1; i < 3; i++ {
+		if got[i].Kind != items[i].Kind || canonXML(t, got[i].RawXML) != canonXML(t, items[i].RawXML) {
+			t.Errorf("öğe %d ikinci okumada farklı:\n%s\n%s", i, got[i].RawXML, items[i].RawXML)
+		}
+	}
+}
+
+func TestGetScreenSendsUnknownItemsBack(t *testing.T) {
+	ctrl := huidutest.NewController()
+	defer ctrl.Close()
+	dev := connect(t, ctrl)
+
+	// Kartta başka bir araçla oluşturulmuş program
+	_, err := dev.SendRawXML(`<?xml version="1.0" encoding="utf-8"?><sdk guid="` + dev.GUID() + `">` +
+		`<in method="AddProgram">` + unknownItemScreen + `</in></sdk>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	screen, err := dev.GetScreen()
+	if err != nil {
+		t.Fatalf("GetScreen: %v", err)
+	}
+	area := screen.Programs[0].Areas[0]
+	area.RemoveItem(0)
+	area.AddText("Yeni başlık", huidu.TextConfig{})
+	if err := dev.SendScreen(screen); err != nil {
+		t.Fatal(err)
+	}
+
+	got := ctrl.Screen()
+	for _, want := range []string{`<counter guid="c-1"`, `target="2027-01-01 00:00:00"`, `<temperature guid="s-1"`, "Yeni başlık"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("kart ekranında %s yok:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, `guid="t-1"`) {
+		t.Errorf("kaldırılan öğe hâlâ kartta:\n%s", got)
+	}
+}
+
+func TestAreaItemJSONUnknownKind(t *testing.T) {
+	screen, err := huidu.ParseScreenXML([]byte(unknownItemScreen))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(screen)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	var back huidu.Screen
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	want := screen.Programs[0].Areas[0].Items()
+	got := back.Programs[0].Areas[0].Items()
+	if len(got) != len(want) || got[1].Kind != "counter" || got[1].RawXML != want[1].RawXML || got[2].RawXML != want[2].RawXML {
+		t.Fatalf("JSON sonrası öğeler = %+v", got)
+	}
+
+	// Ham XML'i olmayan bilinmeyen tip reddedilir
+	var it huidu.AreaItem
+	if err := json.Unmarshal([]byte(`{"kind":"counter","guid":"c-2"}`), &it); err == nil {
+		t.Fatal("rawXML'siz bilinmeyen tip kabul edildi")
+	}
+	if _, err := json.Marshal(huidu.AreaItem{Kind: "counter"}); err == nil {
+		t.Fatal("rawXML'siz bilinmeyen tip yazıldı")
+	}
+}
+
+func TestCurrentProgramAndResumeNormalPlayback(t *testing.T) {
+	ctrl := huidutest.NewController()
+	defer ctrl.Close()
+	dev := connect(t, ctrl)
+
+	if guid, prog, err := dev.CurrentProgram(); err != nil || guid != "" || prog != nil {
+		t.Fatalf("boş kart: CurrentProgram = %q, %v, %v", guid, prog, err)
+	}
+
+	screen := huidu.NewScreen()
+	normal := screen.AddProgram("normal")
+	normal.AddArea(0, 0, 64, 32).AddText("günlük", huidu.TextConfig{})
+	urgent := screen.AddProgramWithConfig(huidu.ProgramConfig{Name: "acil", Realtime: true})
+	urgent.AddArea(0, 0, 64, 32).AddText("acil", huidu.TextConfig{})
+	if err := dev.SendScreen(screen); err != nil {
+		t.Fatal(err)
+	}
+
+	// Gönderimden sonra Screen'de yapılan değişiklikler önbelleğe yansımaz
+	urgent.Name = "değişti"
+	screen.Programs = nil
+
+	guid, prog, err := dev.CurrentProgram()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if guid != urgent.GUID || prog == nil || prog.Name != "acil" || !prog.Realtime {
+		t.Fatalf("realtime sonrası CurrentProgram = %q, %+v", guid, prog)
+	}
+	prog.Name = "yerel değişiklik"
+	if _, again, _ := dev.CurrentProgram(); again == nil || again.Name != "acil" {
+		t.Fatalf("dönen programın değiştirilmesi önbelleği etkiledi: %+v", again)
+	}
+
+	if err := dev.ResumeNormalPlayback(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ctrl.LastRequest(huidu.MethodSetPlayTypeToNormal); !ok {
+		t.Fatal("SetPlayTypeToNormal gönderilmedi")
+	}
+	guid, prog, err = dev.CurrentProgram()
+	if err != nil || guid != normal.GUID || prog == nil || prog.Name != "normal" {
+		t.Fatalf("normal moda dönüş sonrası CurrentProgram = %q, %+v, %v", guid, prog, err)
+	}
+
+	// Ekranı bilmeyen bir Device yalnızca GUID döner; GetScreen sonrası program da gelir
+	other := connect(t, ctrl)
+	if guid, prog, err := other.CurrentProgram(); err != nil || guid != normal.GUID || prog != nil {
+		t.Fatalf("önbelleksiz CurrentProgram = %q, %+v, %v", guid, prog, err)
+	}
+	if _, err := other.GetScreen(); err != nil {
+		t.Fatal(err)
+	}
+	if _, prog, err := other.CurrentProgram(); err != nil || prog == nil || prog.GUID != normal.GUID {
+		t.Fatalf("GetScreen sonrası CurrentProgram = %+v, %v", prog, err)
+	}
+}
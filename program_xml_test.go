@@ -0,0 +1,198 @@
+package huidu_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+)
+
+// xmlNode, XML belgelerini nitelik nitelik karşılaştırmak için kullanılan
+// basit ağaçtır.
+type xmlNode struct {
+	name     string
+	attrs    map[string]string
+	text     string
+	children []*xmlNode
+}
+
+func parseXMLTree(t *testing.T, data []byte) *xmlNode {
+	t.Helper()
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var stack []*xmlNode
+	var root *xmlNode
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("XML okunamadı: %v", err)
+		}
+		switch tt := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{name: tt.Name.Local, attrs: map[string]string{}}
+			for _, a := range tt.Attr {
+				n.attrs[a.Name.Local] = a.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			} else {
+				root = n
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += strings.TrimSpace(string(tt))
+			}
+		}
+	}
+	return root
+}
+
+// diffXMLTree, iki ağaç arasındaki farkları yol bilgisiyle döner. path,
+// want düğümünün yoludur; skip, karşılaştırılmayacak "yol@nitelik" değerleridir.
+func diffXMLTree(path string, want, got *xmlNode, skip map[string]bool) []string {
+	if got.name != want.name {
+		return []string{fmt.Sprintf("%s: eleman %q, beklenen %q", path, got.name, want.name)}
+	}
+
+	var diffs []string
+	keys := make(map[string]bool)
+	for k := range want.attrs {
+		keys[k] = true
+	}
+	for k := range got.attrs {
+		keys[k] = true
+	}
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		if skip[path+"@"+k] {
+			continue
+		}
+		w, wok := want.attrs[k]
+		g, gok := got.attrs[k]
+		switch {
+		case !gok:
+			diffs = append(diffs, fmt.Sprintf("%s@%s: eksik (beklenen %q)", path, k, w))
+		case !wok:
+			diffs = append(diffs, fmt.Sprintf("%s@%s: fazladan (%q)", path, k, g))
+		case w != g:
+			diffs = append(diffs, fmt.Sprintf("%s@%s = %q, beklenen %q", path, k, g, w))
+		}
+	}
+	if want.text != got.text {
+		diffs = append(diffs, fmt.Sprintf("%s metni = %q, beklenen %q", path, got.text, want.text))
+	}
+	if len(want.children) != len(got.children) {
+		return append(diffs, fmt.Sprintf("%s: %d alt eleman, beklenen %d", path, len(got.children), len(want.children)))
+	}
+	for i := range want.children {
+		child := fmt.Sprintf("%s/%s[%d]", path, want.children[i].name, i)
+		diffs = append(diffs, diffXMLTree(child, want.children[i], got.children[i], skip)...)
+	}
+	return diffs
+}
+
+func TestScreenXMLRoundTrip(t *testing.T) {
+	data, err := os.ReadFile("testdata/screen.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	screen, err := huidu.ParseScreenXML(data)
+	if err != nil {
+		t.Fatalf("ParseScreenXML: %v", err)
+	}
+
+	// Okunan değerlerden bir kısmı
+	if len(screen.Programs) != 2 || len(screen.Programs[0].Areas) != 3 {
+		t.Fatalf("program/alan sayısı = %d/%d", len(screen.Programs), len(screen.Programs[0].Areas))
+	}
+	p0, p1 := screen.Programs[0], screen.Programs[1]
+	if p0.PlayCount != 2 || p1.Duration != "00:05:00" || !p1.Disabled || !p1.Realtime {
+		t.Errorf("oynatma kontrolü = %+v / %+v", p0, p1)
+	}
+	kinds := []huidu.ItemKind{}
+	for _, a := range p0.Areas {
+		for _, it := range a.Items() {
+			kinds = append(kinds, it.Kind)
+		}
+	}
+	if fmt.Sprint(kinds) != "[text text image video clock]" {
+		t.Errorf("öğe tipleri = %v", kinds)
+	}
+	if got := p0.Areas[0].Items()[0].Text; got != "Kampanya: 2 al 1 öde & ücretsiz <kargo>" {
+		t.Errorf("metin = %q", got)
+	}
+
+	var buf bytes.Buffer
+	if err := screen.WriteXML(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// timeStamps yazılmaz (bkz. Screen.WriteXML); diğer her nitelik aynı olmalı
+	skip := map[string]bool{"/screen@timeStamps": true}
+	for _, d := range diffXMLTree("/screen", parseXMLTree(t, data), parseXMLTree(t, buf.Bytes()), skip) {
+		t.Error(d)
+	}
+	if t.Failed() {
+		t.Logf("yazılan XML:\n%s", buf.String())
+	}
+}
+
+// Screen, xml.Marshal ile hem değer hem işaretçi olarak yazılabilmeli ve
+// başka bir belgenin içinden xml.Unmarshal ile geri okunabilmelidir.
+func TestScreenXMLMarshal(t *testing.T) {
+	data, err := os.ReadFile("testdata/screen.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen, err := huidu.ParseScreenXML(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byValue, err := xml.Marshal(*screen)
+	if err != nil {
+		t.Fatalf("xml.Marshal(Screen): %v", err)
+	}
+	byPointer, err := xml.Marshal(screen)
+	if err != nil {
+		t.Fatalf("xml.Marshal(*Screen): %v", err)
+	}
+	if !bytes.Equal(byValue, byPointer) {
+		t.Fatalf("değer ve işaretçi çıktıları farklı:\n%s\n%s", byValue, byPointer)
+	}
+
+	var doc struct {
+		XMLName xml.Name       `xml:"kiosk"`
+		Screens []huidu.Screen `xml:"screen"`
+	}
+	in := "<kiosk>" + string(byValue) + string(byPointer) + "</kiosk>"
+	if err := xml.Unmarshal([]byte(in), &doc); err != nil {
+		t.Fatalf("xml.Unmarshal: %v", err)
+	}
+	if len(doc.Screens) != 2 {
+		t.Fatalf("okunan ekran sayısı = %d", len(doc.Screens))
+	}
+	for i, s := range doc.Screens {
+		if len(s.Programs) != 2 || s.Programs[0].GUID != screen.Programs[0].GUID ||
+			len(s.Programs[0].Areas[2].Items()) != 1 {
+			t.Fatalf("ekran %d = %+v", i, s)
+		}
+	}
+}
@@ -1,7 +1,11 @@
 package huidu
 
 import (
+	"bytes"
 	"encoding/binary"
+	"math"
+	"strings"
+	"time"
 )
 
 // ─── Paket Oluşturma ────────────────────────────────────────────────────────────
@@ -32,6 +36,21 @@ func buildVersionPacket() []byte {
 	return pkt
 }
 
+// buildVersionAnswer, cihazın gönderdiği versiyon anlaşma isteğine
+// (CmdServiceAsk) verilen yanıtı oluşturur. Cihazın kendisi bağlandığı
+// (Server) durumda bazı firmware'ler anlaşmayı kendileri başlatır.
+//
+// Paket Formatı (toplam 8 byte):
+//
+//	[2B] uzunluk = 0x0008
+//	[2B] komut   = 0x2002 (CmdServiceAnswer)
+//	[4B] versiyon = 0x1000005 (transportVersion)
+func buildVersionAnswer() []byte {
+	pkt := buildVersionPacket()
+	binary.LittleEndian.PutUint16(pkt[2:4], uint16(CmdServiceAnswer))
+	return pkt
+}
+
 // buildHeartbeat, heartbeat (nabız) paketi oluşturur.
 // TCP bağlantısı canlı tutmak için DefaultHeartbeatInterval aralığında gönderilir.
 //
@@ -41,7 +60,8 @@ func buildVersionPacket() []byte {
 //	[2B] komut   = 0x005f (CmdHeartbeatAsk)
 //
 // Cihaz, CmdHeartbeatAnswer (0x0060) ile yanıt verir.
-// Eğer 3 heartbeat aralığı boyunca yanıt gelmezse bağlantı kopmuş kabul edilir.
+// Art arda WithHeartbeatMaxMissed (varsayılan 3) heartbeat yanıtsız kalırsa
+// bağlantı kopmuş kabul edilir.
 func buildHeartbeat() []byte {
 	pkt := make([]byte, 4)
 	binary.LittleEndian.PutUint16(pkt[0:2], 4)
@@ -49,6 +69,20 @@ func buildHeartbeat() []byte {
 	return pkt
 }
 
+// buildHeartbeatAnswer, cihazın gönderdiği heartbeat'e verilen yanıt paketini
+// oluşturur.
+//
+// Paket Formatı (toplam 4 byte):
+//
+//	[2B] uzunluk = 0x0004
+//	[2B] komut   = 0x0060 (CmdHeartbeatAnswer)
+func buildHeartbeatAnswer() []byte {
+	pkt := make([]byte, 4)
+	binary.LittleEndian.PutUint16(pkt[0:2], 4)
+	binary.LittleEndian.PutUint16(pkt[2:4], uint16(CmdHeartbeatAnswer))
+	return pkt
+}
+
 // buildSdkCmdPackets, XML tabanlı SDK komutunu binary paketlere dönüştürür.
 // Büyük XML verileri MaxContentLength (8000 byte) limitine göre otomatik
 // olarak parçalara bölünür (fragmentation).
@@ -246,6 +280,59 @@ func parseFileEndResponse(data []byte) (errCode ErrorCode, ok bool) {
 	return errCode, true
 }
 
+// buildReadFileAskPacket, cihazdan dosya parçası okuma paketi oluşturur.
+// Kart her istek için tek bir CmdReadFileAnswer döner; dosya, offset
+// ilerletilerek parça parça istenir.
+//
+// Paket Formatı (headLen=12):
+//
+//	[0-1]   length (2B LE)
+//	[2-3]   cmd = 0x8007 (CmdReadFileAsk) (2B LE)
+//	[4-7]   okunacak offset (4B LE)
+//	[8-11]  istenen en fazla byte sayısı (4B LE)
+//	[12+]   dosya adı (null-terminated UTF-8 string)
+func buildReadFileAskPacket(fileName string, offset int64, length int) []byte {
+	const headLen = 12
+	nameBytes := []byte(fileName)
+	pktLen := headLen + len(nameBytes) + 1
+	pkt := make([]byte, pktLen)
+
+	binary.LittleEndian.PutUint16(pkt[0:2], uint16(pktLen))
+	binary.LittleEndian.PutUint16(pkt[2:4], uint16(CmdReadFileAsk))
+	binary.LittleEndian.PutUint32(pkt[4:8], uint32(offset))
+	binary.LittleEndian.PutUint32(pkt[8:12], uint32(length))
+	copy(pkt[headLen:], nameBytes)
+	pkt[pktLen-1] = 0
+
+	return pkt
+}
+
+// parseReadFileResponse, CmdReadFileAnswer paketini ayrıştırır.
+//
+// Paket Formatı:
+//
+//	[0-3]   header
+//	[4-5]   hata kodu (2B LE)
+//	[6-9]   dosyanın toplam boyutu (4B LE)
+//	[10-13] parçanın offset'i (4B LE)
+//	[14+]   parça verisi
+func parseReadFileResponse(data []byte) (errCode ErrorCode, fileSize int64, offset int64, chunk []byte, ok bool) {
+	if len(data) < 6 {
+		return 0, 0, 0, nil, false
+	}
+	errCode = ErrorCode(binary.LittleEndian.Uint16(data[4:6]))
+	if errCode != ErrSuccess {
+		// Hata yanıtlarında boyut ve offset alanları bulunmayabilir
+		return errCode, 0, 0, nil, true
+	}
+	if len(data) < 14 {
+		return 0, 0, 0, nil, false
+	}
+	fileSize = int64(binary.LittleEndian.Uint32(data[6:10]))
+	offset = int64(binary.LittleEndian.Uint32(data[10:14]))
+	return errCode, fileSize, offset, data[14:], true
+}
+
 // buildUDPScanPacket, ağda cihaz arama için UDP broadcast paketi oluşturur.
 // UDP port 10001'e broadcast olarak gönderilir.
 //
@@ -261,3 +348,114 @@ func buildUDPScanPacket() []byte {
 	binary.LittleEndian.PutUint32(pkt[4:8], transportVersion)
 	return pkt
 }
+
+// parseSearchDeviceAnswer, CmdSearchDeviceAnswer UDP paketini ayrıştırır.
+//
+// Paket Formatı:
+//
+//	[2B] length
+//	[2B] cmd = 0x1002 (CmdSearchDeviceAnswer)
+//	[4B] versiyon
+//	[15B] cihaz ID (NUL ile doldurulmuş ASCII)
+//	[NB] (isteğe bağlı) GetDeviceInfo formatında cihaz XML'i
+//
+// Eski firmware'ler yalnızca cihaz ID'sini gönderir; XML kısmı varsa
+// model ve firmware versiyonu da doldurulur. IP adresi paketin geldiği
+// adresten alınır.
+func parseSearchDeviceAnswer(data []byte) (DiscoveredDevice, bool) {
+	var dev DiscoveredDevice
+	if len(data) < 8 || CmdType(binary.LittleEndian.Uint16(data[2:4])) != CmdSearchDeviceAnswer {
+		return dev, false
+	}
+	if pktLen := int(binary.LittleEndian.Uint16(data[0:2])); pktLen >= tcpHeaderLength && pktLen < len(data) {
+		data = data[:pktLen]
+	}
+
+	rest := data[8:]
+	idField := rest
+	if len(idField) > maxDeviceIDLength {
+		idField = idField[:maxDeviceIDLength]
+	}
+	if i := bytes.IndexByte(idField, 0); i >= 0 {
+		idField = idField[:i]
+	}
+	dev.DeviceID = strings.TrimSpace(string(idField))
+	dev.Port = DefaultPort
+
+	// İsteğe bağlı XML kısmı
+	if len(rest) > maxDeviceIDLength {
+		tail := rest[maxDeviceIDLength:]
+		if i := bytes.IndexByte(tail, '<'); i >= 0 {
+			if info, err := parseDeviceInfoXML(strings.TrimRight(cleanXML(tail[i:]), "\x00")); err == nil {
+				dev.Model = info.Model
+				dev.FirmwareVersion = info.AppVersion
+				if dev.DeviceID == "" {
+					dev.DeviceID = info.DeviceID
+				}
+			}
+		}
+	}
+	return dev, dev.DeviceID != ""
+}
+
+// gpsInfoLength, CmdGPSInfoAnswer paketinin header dahil uzunluğudur.
+const gpsInfoLength = 39
+
+// ParseGPSPosition, kartın kendiliğinden gönderdiği CmdGPSInfoAnswer
+// paketini (header dahil) GPSPosition olarak çözer. Subscribe ile ham
+// paket alan kullanıcılar içindir; SubscribeGPS bu fonksiyonu kullanır.
+//
+// Deneysel: Huidu bu paketin düzenini SDK belgelerinde yayımlamaz.
+// Aşağıdaki düzen GPS modüllü kartların gönderdiği paketlerden çıkarılmıştır
+// ve firmware sürümleri arasında değişebilir. Beklenen uzunlukta olmayan
+// paketler tahmin yürütülerek çözülmez; *ProtocolError döner.
+//
+// Paket Formatı:
+//
+//	[0-1]   length = 39 (2B LE)
+//	[2-3]   cmd = 0x3007 (CmdGPSInfoAnswer)
+//	[4]     doğu/batı ('E' veya 'W')
+//	[5]     kuzey/güney ('N' veya 'S')
+//	[6-13]  boylam, derece (float64 LE, işaretsiz)
+//	[14-21] enlem, derece (float64 LE, işaretsiz)
+//	[22-25] hız, km/sa (float32 LE)
+//	[26-29] yön, kuzeyden saat yönünde derece (float32 LE)
+//	[30-31] yıl (2B LE), [32] ay, [33] gün, [34] saat, [35] dakika, [36] saniye (UTC)
+//	[37]    konum durumu (0: konum yok, 1: geçerli konum)
+//	[38]    kullanılan uydu sayısı
+//
+// Ham paket GPSPosition.Raw alanında korunur.
+func ParseGPSPosition(data []byte) (GPSPosition, error) {
+	var pos GPSPosition
+	if len(data) < 4 {
+		return pos, protocolErrorf(CmdGPSInfoAnswer, "GPS paketi çok kısa: %d byte", len(data))
+	}
+	if cmd := CmdType(binary.LittleEndian.Uint16(data[2:4])); cmd != CmdGPSInfoAnswer {
+		return pos, protocolErrorf(cmd, "GPS paketi değil: %s", cmd)
+	}
+	if len(data) != gpsInfoLength || int(binary.LittleEndian.Uint16(data[0:2])) != len(data) {
+		return pos, protocolErrorf(CmdGPSInfoAnswer, "beklenmeyen GPS paketi uzunluğu: %d byte (header %d), beklenen %d",
+			len(data), binary.LittleEndian.Uint16(data[0:2]), gpsInfoLength)
+	}
+
+	pos.Longitude = math.Float64frombits(binary.LittleEndian.Uint64(data[6:14]))
+	pos.Latitude = math.Float64frombits(binary.LittleEndian.Uint64(data[14:22]))
+	if data[4] == 'W' || data[4] == 'w' {
+		pos.Longitude = -pos.Longitude
+	}
+	if data[5] == 'S' || data[5] == 's' {
+		pos.Latitude = -pos.Latitude
+	}
+	pos.Speed = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[22:26])))
+	pos.Course = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[26:30])))
+
+	year := int(binary.LittleEndian.Uint16(data[30:32]))
+	if year > 0 {
+		pos.Time = time.Date(year, time.Month(data[32]), int(data[33]),
+			int(data[34]), int(data[35]), int(data[36]), 0, time.UTC)
+	}
+	pos.Fix = data[37] != 0
+	pos.Satellites = int(data[38])
+	pos.Raw = append([]byte(nil), data...)
+	return pos, nil
+}
@@ -0,0 +1,138 @@
+package huidu_test
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+)
+
+// gpsFrame, 28.9784 D / 41.0082 K konumunda, 54.5 km/sa hızla 270.25°
+// yönünde giden, 9 uyduyla konum kilidi olan bir kartın
+// 2026-03-14 09:30:15 UTC tarihli CmdGPSInfoAnswer çerçevesidir.
+const gpsFrame = "2700" + "0730" + "454e" +
+	"0a68226c78fa3c40" + "e2e995b20c814440" +
+	"00005a42" + "00208743" +
+	"ea07030e091e0f" + "01" + "09"
+
+func TestParseGPSPosition(t *testing.T) {
+	data, err := hex.DecodeString(gpsFrame)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pos, err := huidu.ParseGPSPosition(data)
+	if err != nil {
+		t.Fatalf("ParseGPSPosition: %v", err)
+	}
+	want := time.Date(2026, 3, 14, 9, 30, 15, 0, time.UTC)
+	if pos.Longitude != 28.9784 || pos.Latitude != 41.0082 || pos.Speed != 54.5 || pos.Course != 270.25 ||
+		!pos.Time.Equal(want) || !pos.Fix || pos.Satellites != 9 {
+		t.Fatalf("konum = %+v", pos)
+	}
+	if hex.EncodeToString(pos.Raw) != gpsFrame {
+		t.Fatalf("Raw = %x", pos.Raw)
+	}
+
+	// Batı ve güney yarımküre işaretleri
+	data[4], data[5] = 'W', 'S'
+	pos, err = huidu.ParseGPSPosition(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pos.Longitude != -28.9784 || pos.Latitude != -41.0082 {
+		t.Fatalf("W/S konum = %f, %f", pos.Latitude, pos.Longitude)
+	}
+}
+
+func TestParseGPSPositionRejectsUnexpectedPackets(t *testing.T) {
+	valid, _ := hex.DecodeString(gpsFrame)
+
+	longer := append(append([]byte(nil), valid...), 0, 0, 0, 0)
+	longer[0] = byte(len(longer))
+
+	wrongHeader := append([]byte(nil), valid...)
+	wrongHeader[0] = 40
+
+	wrongCmd := append([]byte(nil), valid...)
+	wrongCmd[2], wrongCmd[3] = 0x5f, 0x00 // CmdHeartbeatAsk
+
+	tests := []struct {
+		name string
+		data []byte
+		cmd  huidu.CmdType
+	}{
+		{"boş", nil, huidu.CmdGPSInfoAnswer},
+		{"kısa", valid[:20], huidu.CmdGPSInfoAnswer},
+		{"uzun", longer, huidu.CmdGPSInfoAnswer},
+		{"header uzunluğu farklı", wrongHeader, huidu.CmdGPSInfoAnswer},
+		{"farklı komut", wrongCmd, huidu.CmdHeartbeatAsk},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := huidu.ParseGPSPosition(tt.data)
+			var pe *huidu.ProtocolError
+			if !errors.As(err, &pe) {
+				t.Fatalf("hata = %v, *ProtocolError bekleniyordu", err)
+			}
+			if pe.Cmd != tt.cmd {
+				t.Fatalf("ProtocolError.Cmd = %s, beklenen %s", pe.Cmd, tt.cmd)
+			}
+		})
+	}
+}
+
+// searchAnswer, id alanı ve isteğe bağlı XML içeren bir
+// CmdSearchDeviceAnswer paketi oluşturur.
+func searchAnswer(cmd huidu.CmdType, id, xml string) []byte {
+	pkt := make([]byte, 8+15, 8+15+len(xml))
+	binary.LittleEndian.PutUint16(pkt[2:4], uint16(cmd))
+	binary.LittleEndian.PutUint32(pkt[4:8], 0x1000005)
+	copy(pkt[8:], id)
+	pkt = append(pkt, xml...)
+	binary.LittleEndian.PutUint16(pkt[0:2], uint16(len(pkt)))
+	return pkt
+}
+
+func TestParseSearchDeviceAnswer(t *testing.T) {
+	const info = `<device cpu="ARM" model="C16L" id="C16-XML-9" name=""/><version fpga="1" app="7.10.2.0" kernel="3"/>`
+
+	// Uzunluk alanı yalnızca kimliği kapsıyorsa sonraki XML yok sayılır
+	trailing := searchAnswer(huidu.CmdSearchDeviceAnswer, "C16-12345", info)
+	binary.LittleEndian.PutUint16(trailing[0:2], 8+15)
+
+	tests := []struct {
+		name string
+		data []byte
+		ok   bool
+		want huidu.DiscoveredDevice
+	}{
+		{"yalnızca kimlik", searchAnswer(huidu.CmdSearchDeviceAnswer, "C16-12345", ""), true,
+			huidu.DiscoveredDevice{DeviceID: "C16-12345", Port: huidu.DefaultPort}},
+		{"kimlik ve XML", searchAnswer(huidu.CmdSearchDeviceAnswer, "C16-12345", info), true,
+			huidu.DiscoveredDevice{DeviceID: "C16-12345", Port: huidu.DefaultPort, Model: "C16L", FirmwareVersion: "7.10.2.0"}},
+		{"kimlik XML'den", searchAnswer(huidu.CmdSearchDeviceAnswer, "", info), true,
+			huidu.DiscoveredDevice{DeviceID: "C16-XML-9", Port: huidu.DefaultPort, Model: "C16L", FirmwareVersion: "7.10.2.0"}},
+		{"uzunluk alanı dışındaki XML", trailing, true,
+			huidu.DiscoveredDevice{DeviceID: "C16-12345", Port: huidu.DefaultPort}},
+		{"boş", nil, false, huidu.DiscoveredDevice{}},
+		{"kısaltılmış header", searchAnswer(huidu.CmdSearchDeviceAnswer, "C16-12345", "")[:6], false, huidu.DiscoveredDevice{}},
+		{"kimliksiz", searchAnswer(huidu.CmdSearchDeviceAnswer, "", "")[:8], false, huidu.DiscoveredDevice{}},
+		{"kendi tarama paketimiz", searchAnswer(huidu.CmdSearchDeviceAsk, "C16-12345", ""), false, huidu.DiscoveredDevice{}},
+		{"ilgisiz komut", searchAnswer(huidu.CmdHeartbeatAnswer, "C16-12345", info), false, huidu.DiscoveredDevice{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := huidu.ParseSearchDeviceAnswer(tt.data)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, beklenen %v (%+v)", ok, tt.ok, got)
+			}
+			if ok && got != tt.want {
+				t.Fatalf("cihaz = %+v, beklenen %+v", got, tt.want)
+			}
+		})
+	}
+}
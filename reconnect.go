@@ -0,0 +1,200 @@
+package huidu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ─── Otomatik Yeniden Bağlanma ──────────────────────────────────────────────────
+//
+// Bu dosya, WithAutoReconnect seçeneğinin uygulamasını içerir.
+//
+// Kopuş tespiti:
+//   - TCP yazma hatası (sendOn)
+//   - TCP okuma hatası veya EOF (readLoop)
+//   - Heartbeat gönderilemediğinde (heartbeatLoop → sendOn)
+//   - Art arda WithHeartbeatMaxMissed heartbeat yanıtsız kaldığında
+//     (ErrHeartbeatTimeout)
+//
+// Kopuş fark edildiğinde bağlantı kapatılır, IsConnected() false döner,
+// EventDisconnected yayınlanır ve arka planda üstel bekleme (exponential
+// backoff) ile 3 aşamalı handshake yeniden denenir. Başarılı handshake
+// sonrasında sdkGUID ve önbellekteki cihaz bilgisi yenilenir.
+//
+// Kopuş bir komut sırasında olduysa komut da yeniden bağlanmayı bekler;
+// salt okunur komutlar yeni oturumda otomatik olarak tekrarlanır.
+
+var (
+	// ErrConnectionLost, komut sırasında TCP bağlantısının koptuğunu belirtir.
+	// Alttaki ağ hatası da errors.Is/As ile erişilebilir durumdadır.
+	ErrConnectionLost = errors.New("huidu: bağlantı koptu")
+
+	// ErrClosed, Close() çağrıldıktan sonra bağlantı kurulmaya çalışıldığında döner.
+	ErrClosed = errors.New("huidu: cihaz kapatıldı")
+)
+
+// markBroken, l üzerinde bir G/Ç hatası oluştuğunda çağrılır.
+// l hâlâ aktif bağlantıysa kapatılır, EventDisconnected yayınlanır ve
+// WithAutoReconnect açıksa arka planda yeniden bağlanma başlatılır.
+//
+// Dönen hata ErrConnectionLost ve err'i birlikte sarar.
+func (d *Device) markBroken(l *link, err error) error {
+	lost := fmt.Errorf("%w: %w", ErrConnectionLost, err)
+
+	d.mu.Lock()
+	if d.link != l || !d.connected {
+		// Handshake sırasındaki hatalar connectLocked tarafından ele alınır.
+		d.mu.Unlock()
+		return lost
+	}
+	d.closeInternal()
+	gen := d.generation
+	startLoop := d.opts.autoReconnect && !d.closed && !d.reconnecting
+	if startLoop {
+		d.reconnecting = true
+	}
+	lifeCtx := d.lifeCtx
+	d.mu.Unlock()
+
+	d.logf("Bağlantı koptu: %v", err)
+	d.emit(Event{Type: EventDisconnected, Err: err})
+
+	if startLoop {
+		go func() {
+			defer func() {
+				d.mu.Lock()
+				d.reconnecting = false
+				d.mu.Unlock()
+			}()
+			if rerr := d.reconnect(lifeCtx, gen, 0); rerr != nil {
+				d.logf("Arka plan yeniden bağlanma durduruldu: %v", rerr)
+			}
+		}()
+	}
+	return lost
+}
+
+// reconnect, gen kuşağındaki bağlantı koptuktan sonra yeniden bağlanır.
+// Başka bir goroutine bu arada bağlanmışsa hemen döner. maxAttempts 0 ise
+// ctx iptal edilene veya Close() çağrılana kadar denemeye devam eder.
+//
+// Denemeler arasında WithReconnectBackoff ile ayarlanan süre, her başarısız
+// denemede iki katına çıkarılarak (üst sınıra kadar) beklenir.
+func (d *Device) reconnect(ctx context.Context, gen uint64, maxAttempts int) error {
+	delay := d.opts.reconnectMinDelay
+	for attempt := 1; ; attempt++ {
+		done, err := d.tryReconnect(ctx, gen, attempt)
+		if done {
+			return err
+		}
+		if maxAttempts > 0 && attempt >= maxAttempts {
+			return fmt.Errorf("%d denemede yeniden bağlanılamadı: %w", attempt, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		delay *= 2
+		if delay > d.opts.reconnectMaxDelay {
+			delay = d.opts.reconnectMaxDelay
+		}
+	}
+}
+
+// tryReconnect, tek bir yeniden bağlanma denemesi yapar.
+// done=true ise artık denemeye gerek yoktur (başarılı, zaten bağlı ya da kapatılmış).
+func (d *Device) tryReconnect(ctx context.Context, gen uint64, attempt int) (done bool, err error) {
+	d.dialMu.Lock()
+	defer d.dialMu.Unlock()
+
+	d.mu.Lock()
+	switch {
+	case d.closed:
+		d.mu.Unlock()
+		return true, ErrClosed
+	case d.generation != gen && d.connected:
+		d.mu.Unlock()
+		return true, nil
+	}
+	d.closeInternal()
+	d.mu.Unlock()
+
+	d.logf("Yeniden bağlanılıyor (deneme %d)", attempt)
+	d.emit(Event{Type: EventReconnecting, Attempt: attempt})
+
+	if err := d.connectLocked(ctx); err != nil {
+		if errors.Is(err, ErrClosed) || errors.Is(err, errNoRedial) {
+			return true, err
+		}
+		d.logf("Yeniden bağlanma denemesi %d başarısız: %v", attempt, err)
+		d.emit(Event{Type: EventReconnecting, Attempt: attempt, Err: err})
+		return false, err
+	}
+
+	d.emit(Event{Type: EventReconnected, Attempt: attempt})
+	return true, nil
+}
+
+// currentGeneration, aktif bağlantının kuşak numarasını döner.
+func (d *Device) currentGeneration() uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.generation
+}
+
+// isIdempotent, bir SDK metodunun bağlantı koptuktan sonra güvenle
+// tekrarlanıp tekrarlanamayacağını belirtir. Yalnızca cihaz durumunu
+// değiştirmeyen sorgu (Get*) metotları idempotent kabul edilir.
+func isIdempotent(method SdkMethod) bool {
+	return strings.HasPrefix(string(method), "Get")
+}
+
+// emit, olayı yapılandırılmış olay işleyicisine iletilmek üzere sıraya
+// koyar. İşleyici deliverEvents goroutine'inde, hiçbir kilit tutulmadan
+// ve olayların oluşma sırasıyla çağrılır; bu nedenle emit kilit tutulurken
+// de güvenle çağrılabilir.
+func (d *Device) emit(ev Event) {
+	if d.opts.onEvent == nil {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+
+	d.evMu.Lock()
+	d.evQueue = append(d.evQueue, ev)
+	start := !d.evRunning
+	d.evRunning = true
+	d.evMu.Unlock()
+
+	if start {
+		go d.deliverEvents()
+	}
+}
+
+// deliverEvents, kuyruktaki olayları sırayla işleyiciye iletir ve kuyruk
+// boşalınca sona erer.
+func (d *Device) deliverEvents() {
+	for {
+		d.evMu.Lock()
+		if len(d.evQueue) == 0 {
+			d.evQueue = nil
+			d.evRunning = false
+			d.evMu.Unlock()
+			return
+		}
+		ev := d.evQueue[0]
+		d.evQueue = d.evQueue[1:]
+		d.evMu.Unlock()
+
+		d.opts.onEvent(ev)
+	}
+}
@@ -0,0 +1,168 @@
+package huidu
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ─── Meşgul Cihazda Tekrar Deneme ───────────────────────────────────────────────
+//
+// Kart aynı anda tek istemciye hizmet verir; HDPlayer veya başka bir panel
+// bağlıyken komutlar ErrDeviceOccupied (CmdErrorAnswer) veya
+// kDeviceOccupied (SDK result) ile reddedilir. Reddedilen komut kartta
+// çalıştırılmadığından kısa bir beklemeden sonra tekrar gönderilebilir.
+//
+// Her tekrar denemeden önce EventRetrying yayınlanır ve loglanır; metrik
+// toplayıcılar WithEventHandler ile bu olayları sayabilir.
+
+// Varsayılan tekrar deneme değerleri.
+const (
+	DefaultRetryAttempts       = 3
+	DefaultRetryInitialBackoff = 500 * time.Millisecond
+	DefaultRetryMaxBackoff     = 5 * time.Second
+)
+
+// RetryPolicy, kartın geçici olarak reddettiği komutların nasıl tekrar
+// deneneceğini belirler. Sıfır değerli alanlar için varsayılanlar kullanılır.
+type RetryPolicy struct {
+	// MaxAttempts, ilk deneme dahil toplam deneme sayısıdır.
+	// 1 verilirse tekrar deneme yapılmaz. 0: DefaultRetryAttempts.
+	MaxAttempts int
+
+	// InitialBackoff, ilk tekrar denemeden önceki beklemedir; her denemede
+	// iki katına çıkar. 0: DefaultRetryInitialBackoff.
+	InitialBackoff time.Duration
+
+	// MaxBackoff, beklemenin üst sınırıdır. 0: DefaultRetryMaxBackoff.
+	MaxBackoff time.Duration
+
+	// Codes, tekrar denenecek hata kodlarıdır. CmdErrorAnswer, dosya
+	// yanıtları ve ResultCode ile eşlenen SDK result değerleri için geçerlidir.
+	// Boşsa yalnızca ErrDeviceOccupied tekrar denenir.
+	Codes []ErrorCode
+
+	// Results, Codes'a ek olarak tekrar denenecek ham SDK result değerleridir
+	// (ör. bilinen bir koda eşlenmeyen firmware'e özgü değerler).
+	Results []string
+
+	// NonIdempotent, durumu değiştiren komutların (Set*, AddProgram vb.) da
+	// tekrar denenmesine izin verir. Varsayılan olarak yalnızca sorgular
+	// (Get*) ve kaldığı yerden devam eden dosya yüklemeleri tekrar denenir.
+	NonIdempotent bool
+}
+
+// WithRetryPolicy, meşgul cihaz gibi geçici retlerde uygulanacak tekrar
+// deneme politikasını ayarlar. Varsayılan olarak tekrar deneme yapılmaz.
+//
+//	device := huidu.NewDevice("192.168.6.1", 10001,
+//	    huidu.WithRetryPolicy(huidu.RetryPolicy{
+//	        MaxAttempts:   5,
+//	        NonIdempotent: true, // reddedilen Set* komutları kartta çalışmadı
+//	    }),
+//	)
+func WithRetryPolicy(p RetryPolicy) DeviceOption {
+	return func(o *deviceOptions) {
+		o.retry = &p
+	}
+}
+
+// attempts, toplam deneme sayısını döner.
+func (p *RetryPolicy) attempts() int {
+	if p == nil {
+		return 1
+	}
+	if p.MaxAttempts <= 0 {
+		return DefaultRetryAttempts
+	}
+	return p.MaxAttempts
+}
+
+// backoff, attempt. denemeden sonraki bekleme süresini döner (attempt 1'den başlar).
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay, ceiling := p.InitialBackoff, p.MaxBackoff
+	if delay <= 0 {
+		delay = DefaultRetryInitialBackoff
+	}
+	if ceiling <= 0 {
+		ceiling = DefaultRetryMaxBackoff
+	}
+	for i := 1; i < attempt && delay < ceiling; i++ {
+		delay *= 2
+	}
+	if delay > ceiling {
+		delay = ceiling
+	}
+	return delay
+}
+
+// retryable, yanıtın veya hatanın tekrar denenebilir bir ret olup
+// olmadığını döner. Tekrar denenebilirse reddin nedeni de döner.
+func (p *RetryPolicy) retryable(op string, resp *SdkResponse, err error) (error, bool) {
+	if err != nil {
+		var code ErrorCode
+		if errors.As(err, &code) && p.hasCode(code) {
+			return err, true
+		}
+		return nil, false
+	}
+	if resp == nil || resp.IsSuccess() {
+		return nil, false
+	}
+	for _, r := range p.Results {
+		if r == resp.Result {
+			return newResultError(op, resp), true
+		}
+	}
+	if code, ok := ResultCode(resp.Result); ok && p.hasCode(code) {
+		return newResultError(op, resp), true
+	}
+	return nil, false
+}
+
+// hasCode, kodun tekrar denenecekler arasında olup olmadığını döner.
+func (p *RetryPolicy) hasCode(code ErrorCode) bool {
+	if len(p.Codes) == 0 {
+		return code == ErrDeviceOccupied
+	}
+	for _, c := range p.Codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// withRetry, fn'i tekrar deneme politikasına göre çalıştırır. fn'e deneme
+// numarası (1'den başlar) verilir. Politika yoksa, işlem idempotent değilse
+// veya ret tekrar denenebilir değilse fn'in sonucu olduğu gibi döner.
+func (d *Device) withRetry(ctx context.Context, op string, idempotent bool, fn func(attempt int) (*SdkResponse, error)) (*SdkResponse, error) {
+	p := d.opts.retry
+	limit := p.attempts()
+	if !idempotent && (p == nil || !p.NonIdempotent) {
+		limit = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := fn(attempt)
+		if attempt >= limit {
+			return resp, err
+		}
+		reason, ok := p.retryable(op, resp, err)
+		if !ok {
+			return resp, err
+		}
+
+		delay := p.backoff(attempt)
+		d.logf("%s reddedildi (%v), %s sonra tekrar denenecek (%d/%d)", op, reason, delay, attempt+1, limit)
+		d.emit(Event{Type: EventRetrying, Attempt: attempt + 1, Op: op, Delay: delay, Err: reason})
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, err
+		}
+	}
+}
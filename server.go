@@ -0,0 +1,308 @@
+package huidu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// ─── Ters Bağlantı Sunucusu ─────────────────────────────────────────────────────
+//
+// SetServerInfo ile bir sunucuya yönlendirilen kartlar, sunucuya kendileri
+// TCP bağlantısı açar. Bu sayede NAT arkasındaki (ör. hücresel modemli)
+// kartlar da yönetilebilir.
+//
+// Bağlantıyı cihaz başlatsa da protokoldeki roller değişmez: kütüphane SDK
+// istemcisi olarak versiyon anlaşmasını ve GetIFVersion handshake'ini
+// başlatır, cihaz yanıtlar. Anlaşmayı kendisi başlatan firmware'lerin
+// CmdServiceAsk ve heartbeat paketleri okuma döngüsünde yanıtlanır.
+
+// ErrServerClosed, Close() çağrıldıktan sonra Serve ve ListenAndServe'den döner.
+var ErrServerClosed = errors.New("huidu: sunucu kapatıldı")
+
+// Server, cihazların kendiliğinden açtığı TCP bağlantılarını kabul eder.
+// Her bağlantı için handshake yapılır, kart DeviceID ile tanımlanır ve
+// kullanıma hazır *Device handler'a iletilir.
+//
+//	srv := huidu.NewServer(":10001", func(dev *huidu.Device) {
+//	    info := dev.CachedDeviceInfo()
+//	    log.Printf("%s bağlandı (%s)", info.DeviceID, dev.Host())
+//	    dev.SendText("Merhaba", huidu.TextConfig{})
+//	}, huidu.WithLogger(log.Default()))
+//	log.Fatal(srv.ListenAndServe())
+type Server struct {
+	// addr, dinlenecek TCP adresidir (ör. ":10001").
+	addr string
+
+	// handler, handshake'i tamamlanan her cihaz için çağrılır.
+	handler func(*Device)
+
+	// options, kabul edilen cihazlara uygulanacak seçeneklerdir.
+	options []DeviceOption
+
+	// opts, sunucunun kendi logger ve timeout ayarlarıdır.
+	opts deviceOptions
+
+	// ctx, Close() ile iptal edilir ve devam eden handshake'leri sonlandırır.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// mu, aşağıdaki alanları korur.
+	mu      sync.Mutex
+	ln      net.Listener
+	devices map[string]*Device
+	closed  bool
+
+	// handshakes, devam eden handshake goroutine'lerini sayar.
+	handshakes sync.WaitGroup
+}
+
+// NewServer, addr adresini dinleyecek yeni bir Server oluşturur.
+// Dinleme henüz başlamaz; ListenAndServe veya Serve çağrılmalıdır.
+//
+// handler, handshake'i tamamlanan her cihaz için bağlantıya ait goroutine'de
+// çağrılır; bloklaması diğer cihazları etkilemez. options kabul edilen her
+// Device'a uygulanır (WithTimeout, WithLogger, WithEventHandler vb.).
+// WithAutoReconnect yok sayılır: bağlantıyı cihaz açtığından kopuş sonrası
+// cihazın yeniden bağlanması beklenir ve handler yeni Device ile tekrar çağrılır.
+func NewServer(addr string, handler func(*Device), options ...DeviceOption) *Server {
+	opts := defaultDeviceOptions()
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Server{
+		addr:    addr,
+		handler: handler,
+		options: options,
+		opts:    opts,
+		ctx:     ctx,
+		cancel:  cancel,
+		devices: make(map[string]*Device),
+	}
+}
+
+// ListenAndServe, addr üzerinde TCP dinlemeye başlar ve Serve'ü çağırır.
+// Close() çağrılana kadar döner; döndüğünde hata her zaman nil değildir.
+func (s *Server) ListenAndServe() error {
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("dinleme başlatılamadı: %w", err)
+	}
+	return s.Serve(ln)
+}
+
+// Serve, ln üzerinden gelen bağlantıları kabul eder.
+// Close() çağrıldığında ErrServerClosed döner.
+func (s *Server) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		ln.Close()
+		return ErrServerClosed
+	}
+	s.ln = ln
+	s.mu.Unlock()
+
+	s.logf("Sunucu dinliyor: %s", ln.Addr())
+
+	var delay time.Duration
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				// Geçici hata (ör. dosya tanımlayıcısı sınırı): kısa bekleyip devam et
+				if delay == 0 {
+					delay = 5 * time.Millisecond
+				} else if delay *= 2; delay > time.Second {
+					delay = time.Second
+				}
+				s.logf("Bağlantı kabul hatası, %s sonra tekrar denenecek: %v", delay, err)
+				time.Sleep(delay)
+				continue
+			}
+			return fmt.Errorf("bağlantı kabul edilemedi: %w", err)
+		}
+		delay = 0
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return ErrServerClosed
+		}
+		s.handshakes.Add(1)
+		s.mu.Unlock()
+
+		go func() {
+			dev := s.handshake(conn)
+			s.handshakes.Done()
+			if dev != nil && s.handler != nil {
+				s.handler(dev)
+			}
+		}()
+	}
+}
+
+// handshake, kabul edilen bir bağlantı için handshake yapar ve cihazı kaydeder.
+// Başarısız olursa bağlantıyı kapatıp nil döner.
+func (s *Server) handshake(conn net.Conn) *Device {
+	s.logf("Cihaz bağlantısı kabul edildi: %s", conn.RemoteAddr())
+
+	// Bağlantıyı cihaz açtığından kopuştan sonra yeniden kurulamaz;
+	// cihazın sunucuya tekrar bağlanması beklenir.
+	dev := NewDeviceFromConn(conn, s.options...)
+	dev.opts.autoReconnect = false
+	dev.noRedial = true
+
+	// Kopan cihazı kayıttan düş; kullanıcının olay işleyicisini de çağır
+	userEvent := dev.opts.onEvent
+	dev.opts.onEvent = func(ev Event) {
+		if ev.Type == EventDisconnected {
+			s.remove(dev)
+		}
+		if userEvent != nil {
+			userEvent(ev)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 3*s.opts.timeout)
+	err := dev.ConnectContext(ctx)
+	cancel()
+	if err != nil {
+		s.logf("Handshake başarısız (%s): %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return nil
+	}
+
+	info := dev.CachedDeviceInfo()
+	if info == nil || info.DeviceID == "" {
+		s.logf("Cihaz kimliği alınamadı (%s), bağlantı kapatılıyor", conn.RemoteAddr())
+		dev.Close()
+		return nil
+	}
+
+	if !s.register(info.DeviceID, dev) {
+		dev.Close()
+		return nil
+	}
+	s.logf("Cihaz hazır: %s (%s)", info.DeviceID, conn.RemoteAddr())
+	return dev
+}
+
+// register, cihazı DeviceID ile kaydeder. Aynı kimlikle eski bir bağlantı
+// varsa (ör. kart modem yeniden başladıktan sonra tekrar bağlandıysa) kapatılır.
+// Sunucu kapatılmışsa false döner.
+func (s *Server) register(id string, dev *Device) bool {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return false
+	}
+	old := s.devices[id]
+	s.devices[id] = dev
+	s.mu.Unlock()
+
+	if old != nil && old != dev {
+		s.logf("Cihaz yeniden bağlandı, eski bağlantı kapatılıyor: %s", id)
+		old.Close()
+	}
+	return true
+}
+
+// remove, dev hâlâ kayıtlıysa kaydını siler.
+func (s *Server) remove(dev *Device) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, d := range s.devices {
+		if d == dev {
+			delete(s.devices, id)
+			return
+		}
+	}
+}
+
+// Device, deviceID kimliğiyle bağlı cihazı döner (bağlı değilse nil).
+func (s *Server) Device(deviceID string) *Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.devices[deviceID]
+	if d == nil || !d.IsConnected() {
+		return nil
+	}
+	return d
+}
+
+// Devices, şu an bağlı olan tüm cihazları döner. Uygulama tarafından
+// Close() ile kapatılan cihazlar listelenmez.
+func (s *Server) Devices() []*Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	devs := make([]*Device, 0, len(s.devices))
+	for _, d := range s.devices {
+		if d.IsConnected() {
+			devs = append(devs, d)
+		}
+	}
+	return devs
+}
+
+// Addr, dinlenen adresi döner. Serve çağrılmadan önce nil döner.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ln == nil {
+		return nil
+	}
+	return s.ln.Addr()
+}
+
+// Close, dinlemeyi durdurur, devam eden handshake'leri iptal eder ve bağlı
+// tüm cihazların bağlantısını kapatır. Handler'ların dönmesi beklenmez.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	var err error
+	if s.ln != nil {
+		err = s.ln.Close()
+	}
+	devs := make([]*Device, 0, len(s.devices))
+	for _, d := range s.devices {
+		devs = append(devs, d)
+	}
+	s.devices = make(map[string]*Device)
+	s.mu.Unlock()
+
+	s.cancel()
+	for _, d := range devs {
+		d.Close()
+	}
+	s.handshakes.Wait()
+	return err
+}
+
+// isClosed, Close() çağrılıp çağrılmadığını döner.
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// logf, yapılandırılmış logger varsa mesaj yazar.
+func (s *Server) logf(format string, v ...interface{}) {
+	if s.opts.logger != nil {
+		s.opts.logger.Printf("[huidu-server] "+format, v...)
+	}
+}
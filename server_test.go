@@ -0,0 +1,180 @@
+package huidu_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	huidu "github.com/alparslanahmed/huidu-led"
+	"github.com/alparslanahmed/huidu-led/huidutest"
+)
+
+// dialIn, ctrl'ı sunucuya bağlanan bir kart gibi addr'a bağlar. Dönen kanal
+// kart bağlantıyı bırakınca kapanır.
+func dialIn(t *testing.T, ctrl *huidutest.Controller, addr string) <-chan struct{} {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ctrl.Serve(conn)
+	}()
+	return done
+}
+
+// within, cond doğru olana kadar en fazla bir saniye bekler.
+func within(cond func() bool) bool {
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return true
+}
+
+func TestServerReverseConnections(t *testing.T) {
+	info := huidutest.DefaultDeviceInfo
+	info.DeviceID = "C16-REV-1"
+	ctrl := huidutest.NewController(huidutest.WithDeviceInfo(info))
+	defer ctrl.Close()
+
+	ready := make(chan *huidu.Device, 4)
+	disconnected := make(chan struct{}, 4)
+	srv := huidu.NewServer("127.0.0.1:0", func(dev *huidu.Device) { ready <- dev },
+		huidu.WithTimeout(2*time.Second),
+		huidu.WithAutoReconnect(true),
+		huidu.WithEventHandler(func(ev huidu.Event) {
+			if ev.Type == huidu.EventDisconnected {
+				disconnected <- struct{}{}
+			}
+		}),
+	)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ln) }()
+	defer srv.Close()
+
+	accept := func() *huidu.Device {
+		t.Helper()
+		select {
+		case dev := <-ready:
+			return dev
+		case <-time.After(3 * time.Second):
+			t.Fatal("handler çağrılmadı")
+			return nil
+		}
+	}
+
+	// Handshake ve kimlikle kayıt
+	dialIn(t, ctrl, ln.Addr().String())
+	first := accept()
+	if got := first.CachedDeviceInfo(); got == nil || *got != info {
+		t.Fatalf("CachedDeviceInfo = %+v", got)
+	}
+	if srv.Device(info.DeviceID) != first || len(srv.Devices()) != 1 {
+		t.Fatalf("Device(%q) kayıtlı cihazı dönmedi", info.DeviceID)
+	}
+	if err := first.SetBrightness(55); err != nil {
+		t.Fatalf("ters bağlantı üzerinden komut: %v", err)
+	}
+	if ctrl.Luminance().DefaultValue != 55 {
+		t.Fatal("komut karta ulaşmadı")
+	}
+
+	// Aynı kimlikle yeniden bağlanan kart eski bağlantının yerini alır
+	firstGone := make(chan struct{})
+	go func() {
+		within(func() bool { return !first.IsConnected() })
+		close(firstGone)
+	}()
+	dialIn(t, ctrl, ln.Addr().String())
+	second := accept()
+	if second == first || srv.Device(info.DeviceID) != second {
+		t.Fatal("yeniden bağlanan kart kaydı değiştirmedi")
+	}
+	<-firstGone
+	if first.IsConnected() {
+		t.Fatal("eski bağlantı kapatılmadı")
+	}
+	if n := srv.Registered(); n != 1 {
+		t.Fatalf("kayıtlı cihaz = %d, beklenen 1", n)
+	}
+
+	// Kopan cihaz kayıttan düşer; WithAutoReconnect sunucuda yok sayılır
+	ctrl.DropConnections()
+	select {
+	case <-disconnected:
+	case <-time.After(2 * time.Second):
+		t.Fatal("EventDisconnected kullanıcı işleyicisine iletilmedi")
+	}
+	if !within(func() bool { return srv.Registered() == 0 }) {
+		t.Fatalf("kopan cihaz kayıtta kaldı (%d)", srv.Registered())
+	}
+	if srv.Device(info.DeviceID) != nil || len(srv.Devices()) != 0 {
+		t.Fatal("kopan cihaz listeleniyor")
+	}
+
+	// Close bağlı cihazları kapatır ve Serve ErrServerClosed ile döner
+	dialIn(t, ctrl, ln.Addr().String())
+	third := accept()
+	if err := srv.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	select {
+	case err := <-served:
+		if !errors.Is(err, huidu.ErrServerClosed) {
+			t.Fatalf("Serve = %v, ErrServerClosed bekleniyordu", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Serve Close sonrası dönmedi")
+	}
+	if third.IsConnected() {
+		t.Fatal("Close bağlı cihazı kapatmadı")
+	}
+	if err := srv.Serve(ln); !errors.Is(err, huidu.ErrServerClosed) {
+		t.Fatalf("kapalı sunucuda Serve = %v", err)
+	}
+	if err := srv.ListenAndServe(); !errors.Is(err, huidu.ErrServerClosed) {
+		t.Fatalf("kapalı sunucuda ListenAndServe = %v", err)
+	}
+}
+
+// Kimliğini bildirmeyen kart kaydedilmez ve bağlantısı kapatılır.
+func TestServerRejectsDeviceWithoutID(t *testing.T) {
+	info := huidutest.DefaultDeviceInfo
+	info.DeviceID = ""
+	ctrl := huidutest.NewController(huidutest.WithDeviceInfo(info))
+	defer ctrl.Close()
+
+	called := make(chan struct{}, 1)
+	srv := huidu.NewServer("127.0.0.1:0", func(*huidu.Device) { called <- struct{}{} }, huidu.WithTimeout(2*time.Second))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	select {
+	case <-dialIn(t, ctrl, ln.Addr().String()):
+	case <-time.After(3 * time.Second):
+		t.Fatal("kimliksiz kartın bağlantısı kapatılmadı")
+	}
+	select {
+	case <-called:
+		t.Fatal("kimliksiz kart için handler çağrıldı")
+	default:
+	}
+	if n := srv.Registered(); n != 0 {
+		t.Fatalf("kayıtlı cihaz = %d", n)
+	}
+}
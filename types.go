@@ -1,8 +1,10 @@
 package huidu
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"net"
 	"time"
 )
 
@@ -19,6 +21,10 @@ const (
 	// SDK orijinal kodunda 30 saniye olarak belirlenmiştir.
 	DefaultHeartbeatInterval = 30 * time.Second
 
+	// DefaultHeartbeatMaxMissed, bağlantı kopmuş sayılmadan önce yanıtsız
+	// kalabilecek ardışık heartbeat sayısıdır.
+	DefaultHeartbeatMaxMissed = 3
+
 	// MaxContentLength, tek bir TCP paketinde taşınabilecek maksimum veri boyutudur.
 	// SDK'daki _maxContentLength sabitiyle aynıdır.
 	MaxContentLength = 8000
@@ -39,6 +45,19 @@ const (
 	// SDK'daki _SDK_VERSION sabitiyle aynıdır.
 	sdkVersion uint32 = 0x1000000
 
+	// DefaultReconnectMinDelay, ilk yeniden bağlanma denemesinden önceki beklemedir.
+	DefaultReconnectMinDelay = 1 * time.Second
+
+	// DefaultReconnectMaxDelay, yeniden bağlanma denemeleri arasındaki en uzun beklemedir.
+	DefaultReconnectMaxDelay = 1 * time.Minute
+
+	// DefaultReconnectAttempts, bir komutun kopuş sonrası beklediği
+	// en fazla yeniden bağlanma denemesi sayısıdır.
+	DefaultReconnectAttempts = 3
+
+	// DefaultDiscoverInterval, Discover tarama paketinin yeniden gönderilme aralığıdır.
+	DefaultDiscoverInterval = 2 * time.Second
+
 	// maxDeviceIDLength, cihaz ID'sinin maksimum uzunluğudur.
 	maxDeviceIDLength = 15
 )
@@ -86,7 +105,8 @@ const (
 	// Aynı 12 byte header formatını kullanır.
 	CmdSdkCmdAnswer CmdType = 0x2004
 
-	// CmdGPSInfoAnswer, GPS bilgi yanıtıdır.
+	// CmdGPSInfoAnswer, GPS bilgi yanıtıdır. GPS raporlaması açık kartlar
+	// (bkz. SetGPSReporting) bu paketi kendiliğinden, periyodik olarak gönderir.
 	CmdGPSInfoAnswer CmdType = 0x3007
 
 	// CmdFileStartAsk, dosya transfer başlatma isteğidir.
@@ -138,6 +158,8 @@ func (c CmdType) String() string {
 		return "SdkCmdAsk"
 	case CmdSdkCmdAnswer:
 		return "SdkCmdAnswer"
+	case CmdGPSInfoAnswer:
+		return "GPSInfoAnswer"
 	case CmdFileStartAsk:
 		return "FileStartAsk"
 	case CmdFileStartAnswer:
@@ -514,6 +536,44 @@ type WifiInfo struct {
 	StationPass string     // Station modu: şifre
 }
 
+// CellularInfo, 3G/4G modem ve PPPoE bağlantı durumunu tutar.
+// GetCellularInfo komutuyla alınır.
+type CellularInfo struct {
+	HasModem     bool   // Kartta 3G/4G modem var mı
+	Enabled      bool   // Mobil veri aktif mi
+	SIMInserted  bool   // SIM kart takılı mı
+	Status       string // Modem/bağlantı durumu (firmware'in bildirdiği metin)
+	Network      string // Şebeke tipi (ör: "4G", "3G")
+	Operator     string // Operatör adı
+	Signal       int    // Sinyal seviyesi (firmware'e göre 0-5 çubuk veya yüzde)
+	DBM          int    // Sinyal gücü (dBm, bilinmiyorsa 0)
+	IP           string // Mobil bağlantıdan alınan IP adresi
+	APN          string // Kullanılan APN
+	IMEI         string // Modem IMEI numarası
+	Number       string // SIM telefon numarası (operatör bildiriyorsa)
+	Manufacturer string // Modem üreticisi
+	ModemModel   string // Modem modeli
+	ModemVersion string // Modem firmware versiyonu
+}
+
+// APNAuth, APN kimlik doğrulama tipini tanımlar.
+type APNAuth string
+
+const (
+	APNAuthNone APNAuth = "none" // Kimlik doğrulama yok
+	APNAuthPAP  APNAuth = "pap"  // PAP
+	APNAuthCHAP APNAuth = "chap" // CHAP
+	APNAuthAuto APNAuth = "auto" // PAP veya CHAP (modem seçer)
+)
+
+// APNInfo, mobil veri bağlantısının APN ayarlarını tutar.
+type APNInfo struct {
+	APN      string  // Erişim noktası adı (ör: "internet")
+	User     string  // Kullanıcı adı (gerekmiyorsa boş)
+	Password string  // Şifre (gerekmiyorsa boş)
+	Auth     APNAuth // Kimlik doğrulama tipi (boşsa APNAuthNone)
+}
+
 // ServerInfo, cihazın bağlandığı TCP sunucu bilgisini tutar.
 type ServerInfo struct {
 	Host string // Sunucu IP veya domain adı
@@ -566,6 +626,19 @@ type BootLogoInfo struct {
 	MD5    string // Logo dosyasının MD5 hash'i
 }
 
+// MultiScreenSyncInfo, çoklu ekran senkronizasyon ayarını tutar.
+// Yan yana çalışan birden fazla kartın içeriği kare hizalı oynatması için
+// her kartta etkinleştirilmelidir.
+//
+// Kart <enable> dışında da senkron parametreleri döndürebilir (firmware'e
+// göre değişir). Bunlar RawXML'de saklanır ve SetMultiScreenSync ile aynen
+// geri yazılır; böylece GetMultiScreenSync → değiştir → SetMultiScreenSync
+// akışında hiçbir ayar kaybolmaz.
+type MultiScreenSyncInfo struct {
+	Enabled bool   // Senkron oynatma aktif mi
+	RawXML  string // GetMultiScreenSync yanıtının ham iç XML'i; boşsa yalnızca <enable> yazılır
+}
+
 // FontInfo, cihazda yüklü bir fontu temsil eder.
 type FontInfo struct {
 	FontName  string // Font görünen adı
@@ -584,12 +657,74 @@ type FileInfo struct {
 	Type      string // Dosya tipi
 }
 
+// GPSPosition, kartın CmdGPSInfoAnswer ile bildirdiği konumdur.
+// Device.SubscribeGPS ile alınır.
+type GPSPosition struct {
+	Latitude   float64   // Enlem, derece (güney negatif)
+	Longitude  float64   // Boylam, derece (batı negatif)
+	Speed      float64   // Hız (km/sa)
+	Course     float64   // Hareket yönü, kuzeyden saat yönünde derece
+	Time       time.Time // GPS zamanı (UTC; bilinmiyorsa sıfır değer)
+	Fix        bool      // Konum geçerli mi (uydu kilidi var mı)
+	Satellites int       // Kullanılan uydu sayısı
+	Raw        []byte    // Header dahil ham paket
+}
+
+// DiscoveredDevice, Discover ile ağda bulunan bir cihazı tanımlar.
+// Model ve FirmwareVersion alanları yalnızca cihaz yanıtında bu bilgileri
+// gönderiyorsa doludur.
+type DiscoveredDevice struct {
+	DeviceID        string // Benzersiz cihaz kimliği
+	IP              string // Yanıtın geldiği IP adresi
+	Port            int    // TCP kontrol portu (DefaultPort)
+	Model           string // Kart modeli
+	FirmwareVersion string // Firmware versiyonu
+	Interface       string // Yanıtın alındığı yerel ağ arayüzü
+}
+
+// DiscoverOptions, Discover taramasının ayarlarıdır. Sıfır değer tüm
+// broadcast destekli IPv4 arayüzlerinde varsayılan ayarlarla tarar.
+type DiscoverOptions struct {
+	// Interfaces, taramanın yapılacağı ağ arayüzü adlarıdır (ör. "eth0").
+	// Boşsa broadcast destekleyen tüm aktif IPv4 arayüzleri kullanılır.
+	Interfaces []string
+
+	// Port, tarama paketinin gönderileceği UDP portudur (varsayılan: DefaultPort).
+	Port int
+
+	// Interval, tarama paketinin yeniden gönderilme aralığıdır
+	// (varsayılan: DefaultDiscoverInterval). UDP kayıplarına karşı tarama
+	// context sona erene kadar bu aralıkla tekrarlanır.
+	Interval time.Duration
+}
+
 // UploadProgress, dosya yükleme ilerleme bilgisini taşır.
+// DownloadFile da aynı callback'i kullanır; bu durumda Download true olur
+// ve SentBytes cihazdan alınan byte sayısını gösterir.
 type UploadProgress struct {
 	FileName   string  // Yüklenen dosya adı
 	TotalBytes int64   // Toplam dosya boyutu
 	SentBytes  int64   // Gönderilen byte sayısı
 	Percent    float64 // İlerleme yüzdesi (0-100)
+	Download   bool    // Cihazdan indirme ilerlemesi mi
+}
+
+// UploadResult, UploadIfChanged sonucunu taşır. Listeler UploadIfChanged'e
+// verilen yolları verilen sırayla taşır; cihazdaki ad filepath.Base(yol)'dur.
+// Hatayla dönen sonuçta denenmemiş yollar iki listede de bulunmaz.
+type UploadResult struct {
+	Uploaded []string // Yüklenen dosyalar (cihazda yok, eksik veya içeriği farklı)
+	Skipped  []string // Cihazda aynı ad ve MD5 ile eksiksiz bulunduğu için atlananlar
+}
+
+// CommandStats, tamamlanan bir komutun ölçümlerini taşır
+// (bkz. WithCommandObserver).
+type CommandStats struct {
+	Op       string        // SDK metot adı (ör. "GetLuminancePloy"), "UploadFile" veya "DownloadFile"
+	File     string        // Aktarılan dosyanın adı (yalnızca dosya aktarımları)
+	Bytes    int64         // Aktarılan byte sayısı (yalnızca başarılı dosya aktarımları)
+	Duration time.Duration // Tekrar denemeler ve yeniden bağlanma dahil toplam süre
+	Err      error         // Komut başarısızsa hata (SDK result hataları dahil)
 }
 
 // ─── Seçenek Yapıları ───────────────────────────────────────────────────────────
@@ -599,20 +734,33 @@ type UploadProgress struct {
 type DeviceOption func(*deviceOptions)
 
 type deviceOptions struct {
-	timeout           time.Duration
-	heartbeatInterval time.Duration
-	autoReconnect     bool
-	logger            Logger
-	onProgress        func(UploadProgress)
+	timeout            time.Duration
+	heartbeatInterval  time.Duration
+	heartbeatMaxMissed int
+	autoReconnect      bool
+	reconnectMinDelay  time.Duration
+	reconnectMaxDelay  time.Duration
+	reconnectAttempts  int
+	logger             Logger
+	onProgress         func(UploadProgress)
+	onEvent            func(Event)
+	onCommand          func(CommandStats)
+	dialer             func(ctx context.Context, network, addr string) (net.Conn, error)
+	retry              *RetryPolicy
 }
 
 func defaultDeviceOptions() deviceOptions {
 	return deviceOptions{
-		timeout:           DefaultTimeout,
-		heartbeatInterval: DefaultHeartbeatInterval,
-		autoReconnect:     false,
-		logger:            nil,
-		onProgress:        nil,
+		timeout:            DefaultTimeout,
+		heartbeatInterval:  DefaultHeartbeatInterval,
+		heartbeatMaxMissed: DefaultHeartbeatMaxMissed,
+		autoReconnect:      false,
+		reconnectMinDelay:  DefaultReconnectMinDelay,
+		reconnectMaxDelay:  DefaultReconnectMaxDelay,
+		reconnectAttempts:  DefaultReconnectAttempts,
+		logger:             nil,
+		onProgress:         nil,
+		onEvent:            nil,
 	}
 }
 
@@ -636,13 +784,85 @@ func WithHeartbeatInterval(d time.Duration) DeviceOption {
 	}
 }
 
+// WithHeartbeatMaxMissed, art arda kaç heartbeat yanıtsız kaldığında
+// bağlantının kopmuş sayılacağını ayarlar (varsayılan: 3). Sınır aşılınca
+// soket kapatılır, IsConnected() false döner ve EventDisconnected
+// ErrHeartbeatTimeout ile yayınlanır. 0 verilirse yanıt takibi yapılmaz.
+//
+//	device := huidu.NewDevice("192.168.6.1", 10001,
+//	    huidu.WithHeartbeatInterval(5*time.Second),
+//	    huidu.WithHeartbeatMaxMissed(2), // ~10-15 sn içinde kopuş tespiti
+//	)
+func WithHeartbeatMaxMissed(n int) DeviceOption {
+	return func(o *deviceOptions) {
+		o.heartbeatMaxMissed = n
+	}
+}
+
 // WithAutoReconnect, bağlantı koptuğunda otomatik yeniden bağlanmayı aktifleştirir.
+//
+// Kopuş fark edildiğinde arka planda handshake üstel bekleme ile yeniden
+// denenir. Kopuş bir komut sırasında olduysa komut yeniden bağlanmayı bekler
+// ve komut salt okunursa (Get*) yeni oturumda otomatik olarak tekrarlanır.
 func WithAutoReconnect(enabled bool) DeviceOption {
 	return func(o *deviceOptions) {
 		o.autoReconnect = enabled
 	}
 }
 
+// WithReconnectBackoff, yeniden bağlanma denemeleri arasındaki bekleme
+// süresini ayarlar. İlk bekleme min'dir; her başarısız denemede iki katına
+// çıkar ve max'ı geçmez. Varsayılan: 1sn - 1dk.
+func WithReconnectBackoff(min, max time.Duration) DeviceOption {
+	return func(o *deviceOptions) {
+		o.reconnectMinDelay = min
+		o.reconnectMaxDelay = max
+	}
+}
+
+// WithReconnectAttempts, bir komutun kopuş sonrası en fazla kaç yeniden
+// bağlanma denemesi bekleyeceğini ayarlar (varsayılan: 3). Arka plandaki
+// yeniden bağlanma döngüsü bu sınırdan etkilenmez; Close() çağrılana kadar sürer.
+func WithReconnectAttempts(n int) DeviceOption {
+	return func(o *deviceOptions) {
+		o.reconnectAttempts = n
+	}
+}
+
+// WithEventHandler, bağlantı olayları (bağlandı, koptu, yeniden bağlanıyor,
+// yeniden bağlandı) ve tekrar denemeler için callback ayarlar. Olaylar
+// oluşma sırasıyla, cihaz kilitleri tutulmadan ayrı bir goroutine'de iletilir;
+// callback içinden komut veya Connect çağrılabilir. Callback uzun sürerse
+// sonraki olaylar sırada bekler.
+//
+//	dev := huidu.NewDevice("192.168.6.1", 10001,
+//	    huidu.WithAutoReconnect(true),
+//	    huidu.WithEventHandler(func(ev huidu.Event) {
+//	        log.Printf("%s: %s (deneme %d)", dev.Host(), ev.Type, ev.Attempt)
+//	    }),
+//	)
+func WithEventHandler(fn func(Event)) DeviceOption {
+	return func(o *deviceOptions) {
+		o.onEvent = fn
+	}
+}
+
+// WithDialer, cihaza bağlantı açmak için kullanılacak fonksiyonu ayarlar.
+// Varsayılan olarak net.Dialer ile doğrudan TCP bağlantısı kurulur.
+// SSH tünelleri, SOCKS proxy'ler veya seri-TCP köprüleri üzerinden bağlanmak
+// için kullanılabilir; addr "host:port" biçimindedir (IPv6 için "[::1]:10001").
+// ctx, WithTimeout süresiyle sınırlandırılmış olarak verilir.
+//
+//	socks, _ := proxy.SOCKS5("tcp", "127.0.0.1:1080", nil, proxy.Direct)
+//	dev := huidu.NewDevice("10.8.0.12", 10001,
+//	    huidu.WithDialer(socks.(proxy.ContextDialer).DialContext),
+//	)
+func WithDialer(fn func(ctx context.Context, network, addr string) (net.Conn, error)) DeviceOption {
+	return func(o *deviceOptions) {
+		o.dialer = fn
+	}
+}
+
 // WithLogger, özel bir loglama arayüzü ayarlar.
 // Varsayılan olarak loglama devre dışıdır.
 func WithLogger(l Logger) DeviceOption {
@@ -651,13 +871,82 @@ func WithLogger(l Logger) DeviceOption {
 	}
 }
 
-// WithProgressCallback, dosya yükleme ilerleme callback'i ayarlar.
+// WithProgressCallback, dosya yükleme ve indirme ilerleme callback'i ayarlar.
 func WithProgressCallback(fn func(UploadProgress)) DeviceOption {
 	return func(o *deviceOptions) {
 		o.onProgress = fn
 	}
 }
 
+// WithCommandObserver, her SDK komutu ve dosya aktarımı tamamlandığında
+// çağrılacak callback'i ayarlar. Gecikme histogramları ve aktarım hızı gibi
+// metrikleri toplamak için kullanılır (bkz. huiduprom paketi). Callback
+// komutu çalıştıran goroutine'de senkron çağrılır; kısa sürmelidir.
+//
+//	dev := huidu.NewDevice("192.168.6.1", 10001,
+//	    huidu.WithCommandObserver(func(s huidu.CommandStats) {
+//	        log.Printf("%s %s err=%v", s.Op, s.Duration, s.Err)
+//	    }),
+//	)
+func WithCommandObserver(fn func(CommandStats)) DeviceOption {
+	return func(o *deviceOptions) {
+		o.onCommand = fn
+	}
+}
+
+// ─── Bağlantı Olayları ──────────────────────────────────────────────────────────
+
+// EventType, Device bağlantı olayının tipini belirtir.
+type EventType int
+
+const (
+	EventConnected    EventType = iota // Connect() başarıyla tamamlandı
+	EventDisconnected                  // Bağlantı koptu
+	EventReconnecting                  // Yeniden bağlanma denemesi başladı veya başarısız oldu
+	EventReconnected                   // Yeniden bağlanma başarılı
+	EventRetrying                      // Kartın reddettiği komut tekrar denenecek (bkz. WithRetryPolicy)
+)
+
+// String, EventType'ın okunabilir adını döner.
+func (t EventType) String() string {
+	switch t {
+	case EventConnected:
+		return "Connected"
+	case EventDisconnected:
+		return "Disconnected"
+	case EventReconnecting:
+		return "Reconnecting"
+	case EventReconnected:
+		return "Reconnected"
+	case EventRetrying:
+		return "Retrying"
+	default:
+		return fmt.Sprintf("Event(%d)", int(t))
+	}
+}
+
+// Event, bağlantı durumundaki bir değişikliği bildirir.
+// WithEventHandler ile ayarlanan callback'e iletilir.
+type Event struct {
+	Type    EventType     // Olay tipi
+	Attempt int           // Yeniden bağlanma veya tekrar deneme numarası (1'den başlar)
+	Op      string        // Tekrar denenen işlem (yalnızca EventRetrying)
+	Delay   time.Duration // Tekrar denemeden önceki bekleme (yalnızca EventRetrying)
+	Err     error         // Kopuşa, başarısız denemeye veya rete neden olan hata
+	Time    time.Time     // Olayın zamanı
+}
+
+// ─── Heartbeat İstatistikleri ───────────────────────────────────────────────────
+
+// HeartbeatStats, aktif bağlantının heartbeat ölçümlerini içerir.
+// Device.HeartbeatStats ile alınır; bağlantı kalitesini izlemek için kullanılabilir.
+type HeartbeatStats struct {
+	RTT        time.Duration // Son ölçülen gidiş-dönüş süresi (henüz ölçülmediyse 0)
+	LastSent   time.Time     // Son heartbeat isteğinin gönderildiği zaman
+	LastAnswer time.Time     // Son heartbeat yanıtının alındığı zaman
+	Missed     int           // Art arda yanıtsız kalan heartbeat sayısı
+}
+
 // ─── Logger Arayüzü ─────────────────────────────────────────────────────────────
 
 // Logger, kütüphanenin loglama arayüzüdür.
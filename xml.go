@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/xml"
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
 )
 
@@ -150,8 +152,10 @@ type SdkResponse struct {
 	// Result, işlem sonucudur (ör: "kSuccess", "kParseXmlFailed").
 	Result string
 
-	// InnerXML, <out> elementinin ham iç XML içeriğidir.
-	// Ayrıntılı veri ayrıştırma için kullanılır.
+	// InnerXML, <out> elementinin iç XML içeriğidir.
+	// Ayrıntılı veri ayrıştırma için kullanılır. Nitelik değerleri ve metin
+	// içeriği kaçışlı tutulur (& → &amp;, < → &lt;); böylece InnerXML her
+	// zaman geçerli bir XML parçasıdır ve tekrar ayrıştırılabilir.
 	InnerXML string
 
 	// RawXML, yanıtın tamamının ham XML metnidir (debug için).
@@ -225,7 +229,11 @@ func parseSdkResponse(rawXML string) (*SdkResponse, error) {
 							innerBuf.WriteString(">")
 						}
 					case xml.CharData:
-						innerBuf.WriteString(string(t))
+						// Decoder kaçışları çözer. Nitelikler gibi metin de
+						// yeniden kaçışlanır; aksi halde "a &amp; b" içeren bir
+						// yanıt "a & b" olarak kalır ve iç XML'i ayrıştıran
+						// parse* fonksiyonları (ör. program metinleri) hata verir.
+						innerBuf.WriteString(charDataEscaper.Replace(string(t)))
 					}
 				}
 				resp.InnerXML = strings.TrimSpace(innerBuf.String())
@@ -255,6 +263,21 @@ func extractGUID(xmlData string) string {
 	return xmlData[start : start+end]
 }
 
+// extractMethod, SDK XML verisinden metot adını çıkarır.
+// extractGUID gibi tam ayrıştırma yapmadan string arar.
+func extractMethod(xmlData string) string {
+	idx := strings.Index(xmlData, `method="`)
+	if idx < 0 {
+		return ""
+	}
+	start := idx + 8
+	end := strings.Index(xmlData[start:], `"`)
+	if end < 0 {
+		return ""
+	}
+	return xmlData[start : start+end]
+}
+
 // replaceGUID, XML verisindeki GUID değerini yenisiyle değiştirir.
 // SDK handshake sonrasında "##GUID" placeholder'ını gerçek GUID ile değiştirmek
 // için kullanılır.
@@ -435,6 +458,106 @@ func parseWifiInfoXML(innerXML string) (*WifiInfo, error) {
 	return info, nil
 }
 
+// parseCellularInfoXML, GetPppoeInfo yanıtının iç XML'inden CellularInfo çıkarır.
+//
+// Beklenen format:
+//
+//	<pppoe valid="true">
+//	  <enable value="true"/><insert value="true"/><status value="..."/>
+//	  <network value="4G"/><operators value="..."/><signal value="4"/>
+//	  <dbm value="-71"/><address ip="..."/><apn value="..."/>
+//	  <imei value="..."/><number value="..."/><manufacturer value="..."/>
+//	  <model value="..."/><version value="..."/>
+//	</pppoe>
+func parseCellularInfoXML(innerXML string) (*CellularInfo, error) {
+	info := &CellularInfo{}
+	decoder := xml.NewDecoder(strings.NewReader(innerXML))
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch se.Name.Local {
+		case "pppoe":
+			for _, a := range se.Attr {
+				if a.Name.Local == "valid" {
+					info.HasModem = strings.ToLower(a.Value) == "true"
+				}
+			}
+		case "address":
+			for _, a := range se.Attr {
+				if a.Name.Local == "ip" {
+					info.IP = a.Value
+				}
+			}
+		default:
+			for _, a := range se.Attr {
+				if a.Name.Local != "value" {
+					continue
+				}
+				switch se.Name.Local {
+				case "enable":
+					info.Enabled = strings.ToLower(a.Value) == "true"
+				case "insert":
+					info.SIMInserted = strings.ToLower(a.Value) == "true"
+				case "status":
+					info.Status = a.Value
+				case "network":
+					info.Network = a.Value
+				case "operators":
+					info.Operator = a.Value
+				case "signal", "dbm":
+					n, err := parseCellularInt(se.Name.Local, a.Value)
+					if err != nil {
+						return nil, err
+					}
+					if se.Name.Local == "signal" {
+						info.Signal = n
+					} else {
+						info.DBM = n
+					}
+				case "apn":
+					info.APN = a.Value
+				case "imei":
+					info.IMEI = a.Value
+				case "number":
+					info.Number = a.Value
+				case "manufacturer":
+					info.Manufacturer = a.Value
+				case "model":
+					info.ModemModel = a.Value
+				case "version":
+					info.ModemVersion = a.Value
+				}
+			}
+		}
+	}
+	return info, nil
+}
+
+// parseCellularInt, GetPppoeInfo yanıtındaki sayısal bir değeri çözer.
+// Boş değer (modem bilgi vermiyor) 0 kabul edilir; sayı olmayan değer
+// *ProtocolError döner.
+func parseCellularInt(name, value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, &ProtocolError{
+			Cmd: CmdSdkCmdAnswer,
+			Msg: fmt.Sprintf("GetPppoeInfo yanıtında geçersiz %s değeri: %q", name, value),
+			Err: err,
+		}
+	}
+	return n, nil
+}
+
 // parseLuminanceInfoXML, GetLuminancePloy yanıtının iç XML'inden LuminanceInfo çıkarır.
 func parseLuminanceInfoXML(innerXML string) (*LuminanceInfo, error) {
 	info := &LuminanceInfo{DefaultValue: 100, SensorMin: 1, SensorMax: 100, SensorTime: 10}
@@ -710,6 +833,370 @@ func parseFileListXML(innerXML string) ([]FileInfo, error) {
 	return files, nil
 }
 
+// parseMultiScreenSyncXML, GetMulScreenSync yanıtının iç XML'inden
+// MultiScreenSyncInfo çıkarır.
+//
+// Beklenen format:
+//
+//	<enable value="true"/>
+//
+// Firmware sürümüne göre <enable> dışında senkron parametreleri de
+// gelebilir; bunlar RawXML alanında olduğu gibi saklanır.
+func parseMultiScreenSyncXML(innerXML string) (*MultiScreenSyncInfo, error) {
+	return &MultiScreenSyncInfo{
+		Enabled: parseEnableXML(innerXML),
+		RawXML:  strings.TrimSpace(innerXML),
+	}, nil
+}
+
+// parseGPSReportingXML, GetGpsRespondEnable yanıtının iç XML'inden
+// GPS raporlama durumunu çıkarır. Format parseMultiScreenSyncXML ile aynıdır.
+func parseGPSReportingXML(innerXML string) (bool, error) {
+	return parseEnableXML(innerXML), nil
+}
+
+// parseEnableXML, <enable value="..."/> elemanının değerini döner.
+// Eleman yoksa false döner.
+func parseEnableXML(innerXML string) bool {
+	enabled := false
+	decoder := xml.NewDecoder(strings.NewReader(innerXML))
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if se.Name.Local == "enable" {
+			for _, a := range se.Attr {
+				if a.Name.Local == "value" {
+					enabled = strings.ToLower(a.Value) == "true"
+				}
+			}
+		}
+	}
+	return enabled
+}
+
+// parseCurrentProgramXML, GetCurrentPlayProgramGUID yanıtının iç XML'inden
+// oynatılan programın GUID'ini çıkarır. Oynatılan program yoksa boş döner.
+//
+// Beklenen format:
+//
+//	<program guid="..."/>
+func parseCurrentProgramXML(innerXML string) string {
+	decoder := xml.NewDecoder(strings.NewReader(innerXML))
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		for _, a := range se.Attr {
+			if a.Name.Local == "guid" {
+				return a.Value
+			}
+		}
+	}
+}
+
+// ─── Program XML Ayrıştırma ─────────────────────────────────────────────────────
+
+// screenXML ve alt tipleri, Screen.toXML çıktısının (ve GetProgram
+// yanıtının) encoding/xml ile okunan ara temsilidir.
+type screenXML struct {
+	Programs []programXML `xml:"program"`
+}
+
+type programXML struct {
+	Type        string `xml:"type,attr"`
+	ID          int    `xml:"id,attr"`
+	GUID        string `xml:"guid,attr"`
+	Name        string `xml:"name,attr"`
+	Flag        string `xml:"flag,attr"`
+	PlayControl struct {
+		Count    int    `xml:"count,attr"`
+		Duration string `xml:"duration,attr"`
+		Disabled bool   `xml:"disabled,attr"`
+	} `xml:"playControl"`
+	Areas []areaXML `xml:"area"`
+}
+
+type areaXML struct {
+	GUID      string `xml:"guid,attr"`
+	Name      string `xml:"name,attr"`
+	Alpha     *int   `xml:"alpha,attr"`
+	Rectangle struct {
+		X      int `xml:"x,attr"`
+		Y      int `xml:"y,attr"`
+		Width  int `xml:"width,attr"`
+		Height int `xml:"height,attr"`
+	} `xml:"rectangle"`
+	// Resources.Items, öğeleri sırası korunarak tutar (text, image, video, clock).
+	Resources struct {
+		Items []resourceXML `xml:",any"`
+	} `xml:"resources"`
+}
+
+// resourceXML, <resources> altındaki tüm öğe tiplerinin birleşimidir;
+// XMLName.Local öğe tipini belirler.
+type resourceXML struct {
+	XMLName     xml.Name
+	GUID        string `xml:"guid,attr"`
+	Name        string `xml:"name,attr"`
+	Background  string `xml:"background,attr"`
+	Fit         string `xml:"fit,attr"`
+	AspectRatio bool   `xml:"aspectRatio,attr"`
+	Type        string `xml:"type,attr"`
+	Timezone    string `xml:"timezone,attr"`
+	Adjust      string `xml:"adjust,attr"`
+
+	Style struct {
+		Align  string `xml:"align,attr"`
+		VAlign string `xml:"valign,attr"`
+	} `xml:"style"`
+	String string `xml:"string"`
+	Font   struct {
+		Name      string `xml:"name,attr"`
+		Size      int    `xml:"size,attr"`
+		Color     string `xml:"color,attr"`
+		Bold      bool   `xml:"bold,attr"`
+		Italic    bool   `xml:"italic,attr"`
+		Underline bool   `xml:"underline,attr"`
+	} `xml:"font"`
+	Effect struct {
+		In       int `xml:"in,attr"`
+		InSpeed  int `xml:"inSpeed,attr"`
+		Out      int `xml:"out,attr"`
+		Duration int `xml:"duration,attr"`
+	} `xml:"effect"`
+	File struct {
+		Name string `xml:"name,attr"`
+	} `xml:"file"`
+
+	Title         clockPartXML `xml:"title"`
+	Date          clockPartXML `xml:"date"`
+	Week          clockPartXML `xml:"week"`
+	Time          clockPartXML `xml:"time"`
+	LunarCalendar clockPartXML `xml:"lunarCalendar"`
+
+	// Raw, tanınmayan öğe tiplerinde elemanın tamamıdır (bkz. rawItem).
+	Raw string `xml:"-"`
+}
+
+// UnmarshalXML, xml.Unmarshaler arayüzünü uygular. Bilinen öğe tipleri
+// alanlara çözülür; diğerleri yalnızca GUID ve adıyla birlikte ham XML
+// olarak saklanır, böylece içerikleri ayrıştırma hatasına yol açmaz.
+func (r *resourceXML) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	switch ItemKind(start.Name.Local) {
+	case ItemText, ItemImage, ItemVideo, ItemClock:
+		type plain resourceXML
+		return d.DecodeElement((*plain)(r), &start)
+	}
+
+	var body struct {
+		Inner string `xml:",innerxml"`
+	}
+	if err := d.DecodeElement(&body, &start); err != nil {
+		return err
+	}
+	r.XMLName = start.Name
+	var attrs []string
+	for _, a := range start.Attr {
+		switch a.Name.Local {
+		case "guid":
+			r.GUID = a.Value
+		case "name":
+			r.Name = a.Value
+		}
+		attrs = append(attrs, a.Name.Local, a.Value)
+	}
+	if strings.TrimSpace(body.Inner) == "" {
+		r.Raw = xmlElement(start.Name.Local, attrs...)
+	} else {
+		// Inner ham (kaçışlı) metindir; tekrar kaçışlanmaz
+		r.Raw = strings.TrimSuffix(xmlElement(start.Name.Local, attrs...), "/>") +
+			">" + body.Inner + "</" + start.Name.Local + ">"
+	}
+	return nil
+}
+
+type clockPartXML struct {
+	Value   string `xml:"value,attr"`
+	Format  int    `xml:"format,attr"`
+	Color   string `xml:"color,attr"`
+	Display bool   `xml:"display,attr"`
+}
+
+// parseScreenXML, GetProgram yanıtının iç XML'inden Screen çıkarır.
+//
+// Beklenen format Screen.toXML çıktısıdır:
+//
+//	<screen><program ...><playControl .../><area ...><rectangle .../>
+//	<resources><text ...>...</text><image ...>...</image></resources>
+//	</area></program></screen>
+//
+// <screen> kökü yoksa üst seviyedeki <program> elemanları okunur.
+// Programların, alanların ve öğelerin GUID'leri korunur. Dönen Screen'e
+// timeStamps eklenmez (isNew=false). Tanınmayan öğe tipleri atlanmaz:
+// GUID, ad ve ham XML'leriyle rawItem olarak alana eklenir ve Screen
+// tekrar yazıldığında aynen gönderilir.
+func parseScreenXML(innerXML string) (*Screen, error) {
+	doc := strings.TrimSpace(innerXML)
+	if !strings.Contains(doc, "<screen") {
+		doc = "<screen>" + doc + "</screen>"
+	}
+
+	var sx screenXML
+	decoder := xml.NewDecoder(strings.NewReader(doc))
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("<screen> elemanı bulunamadı: %w", err)
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "screen" {
+			if err := decoder.DecodeElement(&sx, &se); err != nil {
+				return nil, fmt.Errorf("ekran XML'i ayrıştırılamadı: %w", err)
+			}
+			break
+		}
+	}
+
+	return sx.screen(), nil
+}
+
+// screen, ara temsilden Screen oluşturur.
+func (sx *screenXML) screen() *Screen {
+	screen := &Screen{}
+	for _, px := range sx.Programs {
+		p := &Program{
+			Type:     ProgramType(px.Type),
+			ID:       px.ID,
+			GUID:     px.GUID,
+			Name:     px.Name,
+			Realtime: px.Flag == "realtime",
+			Duration: px.PlayControl.Duration,
+			Disabled: px.PlayControl.Disabled,
+		}
+		if p.Type == "" {
+			p.Type = ProgramNormal
+		}
+		// toXML, sayı ve süre verilmediğinde count="1" yazar
+		if p.Duration == "" && px.PlayControl.Count > 1 {
+			p.PlayCount = px.PlayControl.Count
+		}
+		for _, ax := range px.Areas {
+			p.Areas = append(p.Areas, parseAreaXML(ax))
+		}
+		screen.Programs = append(screen.Programs, p)
+	}
+	return screen
+}
+
+// parseAreaXML, ara temsilden Area oluşturur.
+func parseAreaXML(ax areaXML) *Area {
+	a := &Area{
+		GUID:   ax.GUID,
+		Name:   ax.Name,
+		X:      ax.Rectangle.X,
+		Y:      ax.Rectangle.Y,
+		Width:  ax.Rectangle.Width,
+		Height: ax.Rectangle.Height,
+		Alpha:  255,
+	}
+	if ax.Alpha != nil {
+		a.Alpha = *ax.Alpha
+	}
+
+	for _, r := range ax.Resources.Items {
+		// buildEffectXML süreyi 1/10 saniye cinsinden yazar
+		speed, duration := r.Effect.InSpeed, r.Effect.Duration/10
+		switch r.XMLName.Local {
+		case "text":
+			a.items = append(a.items, &textItem{
+				guid: r.GUID,
+				name: r.Name,
+				text: r.String,
+				config: TextConfig{
+					Name:            r.Name,
+					FontName:        r.Font.Name,
+					FontSize:        r.Font.Size,
+					Color:           r.Font.Color,
+					Bold:            r.Font.Bold,
+					Italic:          r.Font.Italic,
+					Underline:       r.Font.Underline,
+					HAlign:          HAlign(r.Style.Align),
+					VAlign:          VAlign(r.Style.VAlign),
+					BackgroundColor: r.Background,
+					Effect:          EffectType(r.Effect.In),
+					OutEffect:       EffectType(r.Effect.Out),
+					Speed:           speed,
+					Duration:        duration,
+				},
+			})
+		case "image":
+			a.items = append(a.items, &imageItem{
+				guid:     r.GUID,
+				name:     r.Name,
+				fileName: r.File.Name,
+				config: ImageConfig{
+					Name:      r.Name,
+					Fit:       ImageFit(r.Fit),
+					Effect:    EffectType(r.Effect.In),
+					OutEffect: EffectType(r.Effect.Out),
+					Speed:     speed,
+					Duration:  duration,
+				},
+			})
+		case "video":
+			a.items = append(a.items, &videoItem{
+				guid:     r.GUID,
+				name:     r.Name,
+				fileName: r.File.Name,
+				config:   VideoConfig{Name: r.Name, AspectRatio: r.AspectRatio},
+			})
+		case "clock":
+			a.items = append(a.items, &clockItem{
+				guid: r.GUID,
+				name: r.Name,
+				config: ClockConfig{
+					Name:               r.Name,
+					Type:               ClockType(r.Type),
+					Timezone:           r.Timezone,
+					Adjust:             r.Adjust,
+					ShowTitle:          r.Title.Display,
+					TitleValue:         r.Title.Value,
+					TitleColor:         r.Title.Color,
+					ShowDate:           r.Date.Display,
+					DateFormat:         r.Date.Format,
+					DateColor:          r.Date.Color,
+					ShowWeek:           r.Week.Display,
+					WeekFormat:         r.Week.Format,
+					WeekColor:          r.Week.Color,
+					ShowTime:           r.Time.Display,
+					TimeFormat:         r.Time.Format,
+					TimeColor:          r.Time.Color,
+					ShowLunarCalendar:  r.LunarCalendar.Display,
+					LunarCalendarColor: r.LunarCalendar.Color,
+				},
+			})
+		default:
+			a.items = append(a.items, &rawItem{
+				kind: ItemKind(r.XMLName.Local),
+				guid: r.GUID,
+				name: r.Name,
+				xml:  r.Raw,
+			})
+		}
+	}
+	return a
+}
+
 // ─── XML Yardımcı Fonksiyonlar ──────────────────────────────────────────────────
 
 // xmlEscape, XML özel karakterlerini güvenli formata dönüştürür.
@@ -721,6 +1208,9 @@ func xmlEscape(s string) string {
 	return buf.String()
 }
 
+// charDataEscaper, metin içeriğini boşluk karakterlerine dokunmadan kaçışlar.
+var charDataEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
 // cleanXML, C# SDK'nın ürettiği XML'deki BOM karakterlerini temizler.
 // UTF-8 BOM (0xEF, 0xBB, 0xBF) ile başlayan XML'ler Go'nun xml
 // ayrıştırıcısında sorun çıkarabilir.
@@ -747,6 +1237,20 @@ func buildSetEthernetXML(info *EthernetInfo) string {
 	return xmlElementWithChildren("eth", []string{"valid", "true"}, enableElem, dhcpElem, addrElem)
 }
 
+// buildSetAPNXML, SetApn komutunun XML içeriğini oluşturur.
+func buildSetAPNXML(info *APNInfo) string {
+	auth := info.Auth
+	if auth == "" {
+		auth = APNAuthNone
+	}
+	return xmlElement("apn",
+		"value", info.APN,
+		"user", info.User,
+		"passwd", info.Password,
+		"auth", string(auth),
+	)
+}
+
 // buildSetLuminanceXML, SetLuminancePloy komutunun XML içeriğini oluşturur.
 func buildSetLuminanceXML(info *LuminanceInfo) string {
 	modeStr := "default"
@@ -842,6 +1346,67 @@ func buildSetBootLogoXML(info *BootLogoInfo) string {
 	)
 }
 
+// buildSetMultiScreenSyncXML, SetMulScreenSync komutunun XML içeriğini oluşturur.
+// info.RawXML doluysa yalnızca üst seviye <enable> elemanının value niteliği
+// değiştirilir; diğer elemanlar ve nitelikler kartın gönderdiği gibi geri yazılır.
+func buildSetMultiScreenSyncXML(info *MultiScreenSyncInfo) (string, error) {
+	enable := xmlElement("enable", "value", boolStr(info.Enabled))
+	if strings.TrimSpace(info.RawXML) == "" {
+		return enable, nil
+	}
+	return replaceEnableXML(info.RawXML, info.Enabled)
+}
+
+// replaceEnableXML, raw içindeki üst seviye <enable> başlangıç etiketinin
+// value niteliğini enabled yapar. Etiketin dışındaki metin byte byte korunur.
+// raw'da <enable> yoksa sona eklenir.
+func replaceEnableXML(raw string, enabled bool) (string, error) {
+	decoder := xml.NewDecoder(strings.NewReader(raw))
+	depth := 0
+	for {
+		start := decoder.InputOffset()
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("senkron ayarı XML'i okunamadı: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if depth == 0 && t.Name.Local == "enable" {
+				end := decoder.InputOffset()
+				var attrs []string
+				found := false
+				for _, a := range t.Attr {
+					v := a.Value
+					if a.Name.Local == "value" {
+						v, found = boolStr(enabled), true
+					}
+					attrs = append(attrs, a.Name.Local, v)
+				}
+				if !found {
+					attrs = append(attrs, "value", boolStr(enabled))
+				}
+				tag := xmlElement("enable", attrs...)
+				if !strings.HasSuffix(raw[start:end], "/>") {
+					tag = strings.TrimSuffix(tag, "/>") + ">"
+				}
+				return raw[:start] + tag + raw[end:], nil
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return raw + xmlElement("enable", "value", boolStr(enabled)), nil
+}
+
+// buildSetGPSReportingXML, SetGpsRespondEnable komutunun XML içeriğini oluşturur.
+func buildSetGPSReportingXML(enabled bool) string {
+	return xmlElement("enable", "value", boolStr(enabled))
+}
+
 // boolStr, Go bool değerini SDK'nın beklediği lowercase string'e dönüştürür.
 func boolStr(b bool) string {
 	if b {